@@ -0,0 +1,124 @@
+// Package attributes provides the attribute creation helpers shared by all the telemetry signals,
+// so the same value is always converted in the same way regardless of the signal using it.
+package attributes
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type Attribute = attribute.KeyValue
+
+// DefaultJSONMaxLen is the maximum length in bytes of the JSON encoded values created by New.
+const DefaultJSONMaxLen = 4096
+
+// truncatedSuffix is appended to the JSON encoded values that exceed the maximum length.
+const truncatedSuffix = "..."
+
+// New creates a new attribute.KeyValue pair based on the provided key and value.
+// The function supports multiple types for the value parameter including
+// basic types (string, bool, int, int64, float64), their pointer types, slices of basic types,
+// and any type implementing the fmt.Stringer interface.
+// Maps and structs are encoded as compact JSON limited to DefaultJSONMaxLen bytes,
+// and any other type is formatted with fmt.Sprint.
+//
+// Usage:
+//
+//	attr := attributes.New("key1", "value1")
+//	fmt.Println(attr) // Output: "key1":"value1"
+func New(key string, value interface{}) Attribute {
+	switch v := value.(type) {
+	case string:
+		return attribute.Key(key).String(v)
+	case *string:
+		return attribute.Key(key).String(*v)
+	case bool:
+		return attribute.Key(key).Bool(v)
+	case *bool:
+		return attribute.Key(key).Bool(*v)
+	case int:
+		return attribute.Key(key).Int(v)
+	case *int:
+		return attribute.Key(key).Int(*v)
+	case int64:
+		return attribute.Key(key).Int64(v)
+	case *int64:
+		return attribute.Key(key).Int64(*v)
+	case float64:
+		return attribute.Key(key).Float64(v)
+	case *float64:
+		return attribute.Key(key).Float64(*v)
+	case []string:
+		return attribute.Key(key).StringSlice(v)
+	case []bool:
+		return attribute.Key(key).BoolSlice(v)
+	case []int:
+		return attribute.Key(key).IntSlice(v)
+	case []int64:
+		return attribute.Key(key).Int64Slice(v)
+	case []float64:
+		return attribute.Key(key).Float64Slice(v)
+	case fmt.Stringer:
+		return attribute.Key(key).String(v.String())
+	default:
+		if isComplex(v) {
+			return NewJSON(key, v, DefaultJSONMaxLen)
+		}
+
+		return attribute.Key(key).String(fmt.Sprint(v))
+	}
+}
+
+// NewJSON creates a new string attribute with the value encoded as compact JSON.
+// Values longer than maxLen bytes are truncated and end with "...", so the result
+// is no longer valid JSON. A maxLen lower or equal to 0 disables the limit.
+// If the value can't be encoded as JSON, it's formatted with fmt.Sprint.
+//
+// Usage:
+//
+//	attr := attributes.NewJSON("tyk.api.definition", apiDef, 1024)
+func NewJSON(key string, value interface{}, maxLen int) Attribute {
+	encoded := ""
+
+	data, err := json.Marshal(value)
+	if err == nil {
+		encoded = string(data)
+	} else {
+		encoded = fmt.Sprint(value)
+	}
+
+	return attribute.Key(key).String(truncate(encoded, maxLen))
+}
+
+// truncate limits s to maxLen bytes, including the truncated suffix,
+// without splitting multi-byte characters.
+func truncate(s string, maxLen int) string {
+	if maxLen <= 0 || len(s) <= maxLen {
+		return s
+	}
+
+	if maxLen <= len(truncatedSuffix) {
+		return truncatedSuffix[:maxLen]
+	}
+
+	n := maxLen - len(truncatedSuffix)
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n] + truncatedSuffix
+}
+
+// isComplex checks if the value is a map or a struct, or a pointer to any of them.
+func isComplex(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	return v.Kind() == reflect.Struct || v.Kind() == reflect.Map
+}
@@ -0,0 +1,162 @@
+package attributes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type stringer string
+
+func (s stringer) String() string {
+	return string(s)
+}
+
+type policy struct {
+	Name  string `json:"name"`
+	Limit int    `json:"limit"`
+}
+
+type unsupported struct {
+	Fn func()
+}
+
+func TestNew(t *testing.T) {
+	value := "value"
+
+	tests := []struct {
+		name  string
+		key   string
+		value interface{}
+		want  Attribute
+	}{
+		{
+			name:  "string",
+			key:   "key",
+			value: "value",
+			want:  attribute.Key("key").String("value"),
+		},
+		{
+			name:  "pointer to string",
+			key:   "key",
+			value: &value,
+			want:  attribute.Key("key").String("value"),
+		},
+		{
+			name:  "int slice",
+			key:   "key",
+			value: []int{1, 2},
+			want:  attribute.Key("key").IntSlice([]int{1, 2}),
+		},
+		{
+			name:  "stringer",
+			key:   "key",
+			value: stringer("value"),
+			want:  attribute.Key("key").String("value"),
+		},
+		{
+			name:  "struct",
+			key:   "key",
+			value: policy{Name: "rate-limit", Limit: 10},
+			want:  attribute.Key("key").String(`{"name":"rate-limit","limit":10}`),
+		},
+		{
+			name:  "pointer to struct",
+			key:   "key",
+			value: &policy{Name: "rate-limit", Limit: 10},
+			want:  attribute.Key("key").String(`{"name":"rate-limit","limit":10}`),
+		},
+		{
+			name:  "struct that can't be encoded as JSON",
+			key:   "key",
+			value: unsupported{},
+			want:  attribute.Key("key").String("{<nil>}"),
+		},
+		{
+			name:  "empty struct",
+			key:   "key",
+			value: struct{}{},
+			want:  attribute.Key("key").String("{}"),
+		},
+		{
+			name:  "map",
+			key:   "key",
+			value: map[string]interface{}{"b": 1, "a": []string{"x"}},
+			want:  attribute.Key("key").String(`{"a":["x"],"b":1}`),
+		},
+		{
+			name:  "large map",
+			key:   "key",
+			value: map[string]string{"body": strings.Repeat("a", DefaultJSONMaxLen)},
+			want:  attribute.Key("key").String(`{"body":"` + strings.Repeat("a", DefaultJSONMaxLen-12) + "..."),
+		},
+		{
+			name:  "default",
+			key:   "key",
+			value: uint8(1),
+			want:  attribute.Key("key").String("1"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(tt.key, tt.value)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  interface{}
+		maxLen int
+		want   Attribute
+	}{
+		{
+			name:   "struct",
+			value:  policy{Name: "rate-limit", Limit: 10},
+			maxLen: 100,
+			want:   attribute.Key("key").String(`{"name":"rate-limit","limit":10}`),
+		},
+		{
+			name:   "truncated",
+			value:  policy{Name: "rate-limit", Limit: 10},
+			maxLen: 16,
+			want:   attribute.Key("key").String(`{"name":"rate...`),
+		},
+		{
+			name:   "no limit",
+			value:  []string{"a", "b"},
+			maxLen: 0,
+			want:   attribute.Key("key").String(`["a","b"]`),
+		},
+		{
+			name:   "limit shorter than the suffix",
+			value:  "value",
+			maxLen: 2,
+			want:   attribute.Key("key").String(".."),
+		},
+		{
+			name:   "multi-byte characters are not split",
+			value:  "ñññ",
+			maxLen: 7,
+			want:   attribute.Key("key").String(`"ñ...`),
+		},
+		{
+			name:   "value that can't be encoded as JSON",
+			value:  unsupported{},
+			maxLen: 100,
+			want:   attribute.Key("key").String("{<nil>}"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewJSON("key", tt.value, tt.maxLen)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -41,6 +41,7 @@ func Test_SetDefault(t *testing.T) {
 				Exporter:           "http",
 				Endpoint:           "test",
 				ConnectionTimeout:  10,
+				HTTPEncoding:       "protobuf",
 				ResourceName:       "test-resource",
 				SpanProcessorType:  "simple",
 				ContextPropagation: "b3",
@@ -90,6 +91,120 @@ func Test_SetDefault(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "default pipeline values",
+			givenCfg: OpenTelemetry{
+				Enabled:           true,
+				ConnectionTimeout: 5,
+				Pipelines: []Pipeline{
+					{},
+					{
+						Exporter:          "stdout",
+						SpanProcessorType: "simple",
+					},
+					{
+						Exporter:     "http",
+						HTTPEncoding: "json",
+					},
+					{
+						Exporter: "http",
+					},
+				},
+			},
+			expectedCfg: OpenTelemetry{
+				Enabled:            true,
+				Exporter:           "grpc",
+				Endpoint:           "localhost:4317",
+				ConnectionTimeout:  5,
+				ResourceName:       "tyk",
+				SpanProcessorType:  "batch",
+				ContextPropagation: "tracecontext",
+				Sampling: Sampling{
+					Type: ALWAYSON,
+				},
+				Pipelines: []Pipeline{
+					{
+						Exporter:          "grpc",
+						Endpoint:          "localhost:4317",
+						ConnectionTimeout: 5,
+						SpanProcessorType: "batch",
+					},
+					{
+						Exporter:          "stdout",
+						ConnectionTimeout: 5,
+						SpanProcessorType: "simple",
+					},
+					{
+						Exporter:          "http",
+						Endpoint:          "localhost:4317",
+						ConnectionTimeout: 5,
+						HTTPEncoding:      "json",
+						SpanProcessorType: "batch",
+					},
+					{
+						Exporter:          "http",
+						Endpoint:          "localhost:4317",
+						ConnectionTimeout: 5,
+						HTTPEncoding:      "protobuf",
+						SpanProcessorType: "batch",
+					},
+				},
+			},
+		},
+		{
+			name: "default load shedding values",
+			givenCfg: OpenTelemetry{
+				Enabled: true,
+				LoadShedding: LoadShedding{
+					Enabled: true,
+				},
+			},
+			expectedCfg: OpenTelemetry{
+				Enabled:            true,
+				Exporter:           "grpc",
+				Endpoint:           "localhost:4317",
+				ConnectionTimeout:  1,
+				ResourceName:       "tyk",
+				SpanProcessorType:  "batch",
+				ContextPropagation: "tracecontext",
+				Sampling: Sampling{
+					Type: ALWAYSON,
+				},
+				LoadShedding: LoadShedding{
+					Enabled:                true,
+					MaxConsecutiveFailures: 5,
+					CoolDown:               30,
+				},
+			},
+		},
+		{
+			name: "default retry values",
+			givenCfg: OpenTelemetry{
+				Enabled: true,
+				Retry: Retry{
+					Enabled:     true,
+					MaxInterval: 10,
+				},
+			},
+			expectedCfg: OpenTelemetry{
+				Enabled:            true,
+				Exporter:           "grpc",
+				Endpoint:           "localhost:4317",
+				ConnectionTimeout:  1,
+				ResourceName:       "tyk",
+				SpanProcessorType:  "batch",
+				ContextPropagation: "tracecontext",
+				Sampling: Sampling{
+					Type: ALWAYSON,
+				},
+				Retry: Retry{
+					Enabled:         true,
+					InitialInterval: 5,
+					MaxInterval:     10,
+					MaxElapsedTime:  60,
+				},
+			},
+		},
 	}
 
 	for _, tc := range tcs {
@@ -0,0 +1,75 @@
+package config
+
+import "fmt"
+
+// Deprecation describes a deprecated config field and the field replacing it.
+type Deprecation struct {
+	// Field is the JSON path of the deprecated field, e.g. "pipelines[].endpoint".
+	Field string
+	// Replacement is the JSON path of the field replacing it, empty if the field is just dropped.
+	Replacement string
+	// migrate moves the value of the deprecated field of the config to its replacement,
+	// unless the replacement is already set, and reports whether the deprecated field is set.
+	migrate func(cfg *OpenTelemetry) bool
+}
+
+func (d Deprecation) String() string {
+	if d.Replacement == "" {
+		return fmt.Sprintf("config field %q is deprecated and ignored", d.Field)
+	}
+
+	return fmt.Sprintf("config field %q is deprecated, use %q instead", d.Field, d.Replacement)
+}
+
+// deprecations lists the deprecated config fields, in the order they are migrated. A deprecated field
+// is kept in the config, with a "Deprecated:" doc comment, so the existing configs still load.
+var deprecations = []Deprecation{
+	{
+		Field:       "tls.enable",
+		Replacement: "tls.enabled",
+		migrate: func(cfg *OpenTelemetry) bool {
+			return cfg.TLS.migrateEnable()
+		},
+	},
+	{
+		Field:       "pipelines[].tls.enable",
+		Replacement: "pipelines[].tls.enabled",
+		migrate: func(cfg *OpenTelemetry) bool {
+			used := false
+
+			for i := range cfg.Pipelines {
+				if cfg.Pipelines[i].TLS.migrateEnable() {
+					used = true
+				}
+			}
+
+			return used
+		},
+	},
+}
+
+// Migrate moves the values of the deprecated fields to the fields replacing them, unless these are
+// already set, and returns the deprecated fields in use so they can be reported. It's called by
+// trace.NewProvider, before the defaults are applied.
+func (c *OpenTelemetry) Migrate() []Deprecation {
+	used := []Deprecation{}
+
+	for _, deprecation := range deprecations {
+		if deprecation.migrate(c) {
+			used = append(used, deprecation)
+		}
+	}
+
+	return used
+}
+
+// migrateEnable moves the deprecated Enable flag to Enabled, and reports whether it's set.
+func (t *TLS) migrateEnable() bool {
+	if !t.Enable {
+		return false
+	}
+
+	t.Enabled = true
+
+	return true
+}
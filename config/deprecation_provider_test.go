@@ -0,0 +1,54 @@
+package config_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"github.com/TykTechnologies/opentelemetry/trace"
+	"github.com/stretchr/testify/assert"
+)
+
+// warnLogger records the messages logged by the provider at every level.
+type warnLogger struct {
+	infos []string
+	warns []string
+}
+
+func (l *warnLogger) Info(args ...interface{}) {
+	l.infos = append(l.infos, fmt.Sprint(args...))
+}
+
+func (l *warnLogger) Warn(args ...interface{}) {
+	l.warns = append(l.warns, fmt.Sprint(args...))
+}
+
+func (l *warnLogger) Error(args ...interface{}) {}
+
+func TestMigrateWithProvider(t *testing.T) {
+	config.SetTestDeprecations(t, config.NewTestDeprecation("resource_name", "user_agent", func(cfg *config.OpenTelemetry) bool {
+		if cfg.ResourceName == "" {
+			return false
+		}
+
+		if cfg.UserAgent == "" {
+			cfg.UserAgent = cfg.ResourceName
+		}
+
+		return true
+	}))
+
+	cfg := &config.OpenTelemetry{ResourceName: "gateway"}
+	logger := &warnLogger{}
+
+	provider, err := trace.NewProvider(trace.WithContext(context.Background()), trace.WithLogger(logger), trace.WithConfig(cfg))
+	assert.Nil(t, err)
+
+	// the deprecated field is migrated by the provider and reported at the warn level
+	assert.Equal(t, "gateway", cfg.UserAgent)
+	assert.Equal(t, []string{`config field "resource_name" is deprecated, use "user_agent" instead`}, logger.warns)
+	assert.NotContains(t, logger.infos, logger.warns[0])
+
+	assert.Nil(t, provider.Shutdown(context.Background()))
+}
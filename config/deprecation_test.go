@@ -0,0 +1,90 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrate(t *testing.T) {
+	previous := deprecations
+	t.Cleanup(func() {
+		deprecations = previous
+	})
+
+	renamed := Deprecation{
+		Field:       "resource_name",
+		Replacement: "service_name",
+		migrate: func(cfg *OpenTelemetry) bool {
+			if cfg.ResourceName == "" {
+				return false
+			}
+
+			if cfg.UserAgent == "" {
+				cfg.UserAgent = cfg.ResourceName
+			}
+
+			return true
+		},
+	}
+	dropped := Deprecation{
+		Field: "warm_up",
+		migrate: func(cfg *OpenTelemetry) bool {
+			return cfg.WarmUp
+		},
+	}
+
+	deprecations = []Deprecation{renamed, dropped}
+
+	t.Run("deprecated fields not set", func(t *testing.T) {
+		cfg := &OpenTelemetry{}
+
+		assert.Empty(t, cfg.Migrate())
+		assert.Equal(t, &OpenTelemetry{}, cfg)
+	})
+
+	t.Run("deprecated fields migrated", func(t *testing.T) {
+		cfg := &OpenTelemetry{ResourceName: "gateway", WarmUp: true}
+
+		used := cfg.Migrate()
+		assert.Len(t, used, 2)
+		assert.Equal(t, `config field "resource_name" is deprecated, use "service_name" instead`, used[0].String())
+		assert.Equal(t, `config field "warm_up" is deprecated and ignored`, used[1].String())
+		assert.Equal(t, "gateway", cfg.UserAgent)
+	})
+
+	t.Run("replacement already set", func(t *testing.T) {
+		cfg := &OpenTelemetry{ResourceName: "gateway", UserAgent: "tyk"}
+
+		assert.Len(t, cfg.Migrate(), 1)
+		assert.Equal(t, "tyk", cfg.UserAgent)
+	})
+}
+
+func TestMigrate_TLSEnable(t *testing.T) {
+	t.Run("root and pipelines migrated", func(t *testing.T) {
+		cfg := &OpenTelemetry{
+			TLS: TLS{Enable: true},
+			Pipelines: []Pipeline{
+				{Exporter: "grpc"},
+				{Exporter: "http", TLS: TLS{Enable: true}},
+			},
+		}
+
+		used := cfg.Migrate()
+		assert.Len(t, used, 2)
+		assert.Equal(t, `config field "tls.enable" is deprecated, use "tls.enabled" instead`, used[0].String())
+		assert.Equal(t, `config field "pipelines[].tls.enable" is deprecated, use "pipelines[].tls.enabled" instead`, used[1].String())
+
+		assert.True(t, cfg.TLS.Enabled)
+		assert.False(t, cfg.Pipelines[0].TLS.Enabled)
+		assert.True(t, cfg.Pipelines[1].TLS.Enabled)
+	})
+
+	t.Run("replacement in use", func(t *testing.T) {
+		cfg := &OpenTelemetry{TLS: TLS{Enabled: true}}
+
+		assert.Empty(t, cfg.Migrate())
+		assert.True(t, cfg.TLS.Enabled)
+	})
+}
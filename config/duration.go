@@ -0,0 +1,186 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// seconds is a duration in seconds. In JSON, it can be set as a number of seconds,
+// or as a Go duration string such as "10s" or "1m30s".
+// The config fields stay plain ints, and use it to unmarshal their JSON value.
+type seconds int
+
+// milliseconds is a duration in milliseconds. In JSON, it can be set as a number of milliseconds,
+// or as a Go duration string such as "500ms" or "5s".
+type milliseconds int
+
+// UnmarshalJSON accepts a number of seconds or a duration string.
+func (s *seconds) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalDuration(data, time.Second)
+	if err != nil {
+		return err
+	}
+
+	*s = seconds(value)
+
+	return nil
+}
+
+// UnmarshalJSON accepts a number of milliseconds or a duration string.
+func (m *milliseconds) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalDuration(data, time.Millisecond)
+	if err != nil {
+		return err
+	}
+
+	*m = milliseconds(value)
+
+	return nil
+}
+
+// UnmarshalJSON accepts the durations as numbers or duration strings.
+func (c *OpenTelemetry) UnmarshalJSON(data []byte) error {
+	type plain OpenTelemetry
+
+	aux := struct {
+		*plain
+		ConnectionTimeout *seconds `json:"connection_timeout"`
+		ErrorLogInterval  *seconds `json:"error_log_interval"`
+	}{
+		plain:             (*plain)(c),
+		ConnectionTimeout: (*seconds)(&c.ConnectionTimeout),
+		ErrorLogInterval:  (*seconds)(&c.ErrorLogInterval),
+	}
+
+	return json.Unmarshal(data, &aux)
+}
+
+// UnmarshalJSON accepts the durations as numbers or duration strings.
+func (p *Pipeline) UnmarshalJSON(data []byte) error {
+	type plain Pipeline
+
+	aux := struct {
+		*plain
+		ConnectionTimeout *seconds `json:"connection_timeout"`
+	}{
+		plain:             (*plain)(p),
+		ConnectionTimeout: (*seconds)(&p.ConnectionTimeout),
+	}
+
+	return json.Unmarshal(data, &aux)
+}
+
+// UnmarshalJSON accepts the durations as numbers or duration strings.
+func (r *Retry) UnmarshalJSON(data []byte) error {
+	type plain Retry
+
+	aux := struct {
+		*plain
+		InitialInterval *seconds `json:"initial_interval"`
+		MaxInterval     *seconds `json:"max_interval"`
+		MaxElapsedTime  *seconds `json:"max_elapsed_time"`
+	}{
+		plain:           (*plain)(r),
+		InitialInterval: (*seconds)(&r.InitialInterval),
+		MaxInterval:     (*seconds)(&r.MaxInterval),
+		MaxElapsedTime:  (*seconds)(&r.MaxElapsedTime),
+	}
+
+	return json.Unmarshal(data, &aux)
+}
+
+// UnmarshalJSON accepts the durations as numbers or duration strings.
+func (b *Batch) UnmarshalJSON(data []byte) error {
+	type plain Batch
+
+	aux := struct {
+		*plain
+		Timeout *milliseconds `json:"timeout"`
+	}{
+		plain:   (*plain)(b),
+		Timeout: (*milliseconds)(&b.Timeout),
+	}
+
+	return json.Unmarshal(data, &aux)
+}
+
+// UnmarshalJSON accepts the durations as numbers or duration strings.
+func (g *GRPC) UnmarshalJSON(data []byte) error {
+	type plain GRPC
+
+	aux := struct {
+		*plain
+		DNSRefreshInterval *seconds `json:"dns_refresh_interval"`
+	}{
+		plain:              (*plain)(g),
+		DNSRefreshInterval: (*seconds)(&g.DNSRefreshInterval),
+	}
+
+	return json.Unmarshal(data, &aux)
+}
+
+// UnmarshalJSON accepts the durations as numbers or duration strings.
+func (k *GRPCKeepalive) UnmarshalJSON(data []byte) error {
+	type plain GRPCKeepalive
+
+	aux := struct {
+		*plain
+		Time    *seconds `json:"time"`
+		Timeout *seconds `json:"timeout"`
+	}{
+		plain:   (*plain)(k),
+		Time:    (*seconds)(&k.Time),
+		Timeout: (*seconds)(&k.Timeout),
+	}
+
+	return json.Unmarshal(data, &aux)
+}
+
+// UnmarshalJSON accepts the durations as numbers or duration strings.
+func (l *LoadShedding) UnmarshalJSON(data []byte) error {
+	type plain LoadShedding
+
+	aux := struct {
+		*plain
+		CoolDown *seconds `json:"cool_down"`
+	}{
+		plain:    (*plain)(l),
+		CoolDown: (*seconds)(&l.CoolDown),
+	}
+
+	return json.Unmarshal(data, &aux)
+}
+
+// unmarshalDuration returns the number of units of the JSON number or duration string.
+// The duration strings must be a whole number of units, so they aren't silently rounded.
+func unmarshalDuration(data []byte, unit time.Duration) (int, error) {
+	var value int
+	if err := json.Unmarshal(data, &value); err == nil {
+		return value, nil
+	}
+
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return 0, fmt.Errorf("invalid duration %s: must be a number or a duration string", data)
+	}
+
+	duration, err := time.ParseDuration(str)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", str, err)
+	}
+
+	if duration%unit != 0 {
+		return 0, fmt.Errorf("invalid duration %q: must be a whole number of %s", str, unitName(unit))
+	}
+
+	return int(duration / unit), nil
+}
+
+func unitName(unit time.Duration) string {
+	if unit == time.Millisecond {
+		return "milliseconds"
+	}
+
+	return "seconds"
+}
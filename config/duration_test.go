@@ -0,0 +1,109 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeconds_UnmarshalJSON(t *testing.T) {
+	tcs := []struct {
+		name        string
+		json        string
+		expected    seconds
+		expectedErr string
+	}{
+		{name: "number", json: `10`, expected: 10},
+		{name: "negative number", json: `-1`, expected: -1},
+		{name: "duration string", json: `"1m30s"`, expected: 90},
+		{name: "sub-second duration", json: `"500ms"`, expectedErr: `invalid duration "500ms": must be a whole number of seconds`},
+		{name: "invalid duration", json: `"10"`, expectedErr: `invalid duration "10": time: missing unit in duration "10"`},
+		{name: "invalid type", json: `true`, expectedErr: "invalid duration true: must be a number or a duration string"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var s seconds
+
+			err := json.Unmarshal([]byte(tc.json), &s)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, s)
+		})
+	}
+}
+
+func TestMilliseconds_UnmarshalJSON(t *testing.T) {
+	tcs := []struct {
+		name        string
+		json        string
+		expected    milliseconds
+		expectedErr string
+	}{
+		{name: "number", json: `500`, expected: 500},
+		{name: "duration string", json: `"500ms"`, expected: 500},
+		{name: "duration string in seconds", json: `"5s"`, expected: 5000},
+		{name: "sub-millisecond duration", json: `"1500us"`, expectedErr: `invalid duration "1500us": must be a whole number of milliseconds`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var m milliseconds
+
+			err := json.Unmarshal([]byte(tc.json), &m)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, m)
+		})
+	}
+}
+
+func TestDurations_Config(t *testing.T) {
+	cfg := OpenTelemetry{}
+
+	err := json.Unmarshal([]byte(`{
+		"enabled": true,
+		"connection_timeout": "10s",
+		"error_log_interval": "5m",
+		"batch": {"size": 100, "timeout": "500ms"},
+		"retry": {"enabled": true, "initial_interval": 5, "max_interval": "30s"},
+		"grpc": {"load_balancing": "round_robin", "keepalive": {"time": "40s"}},
+		"load_shedding": {"cool_down": "1m"},
+		"pipelines": [{"endpoint": "collector:4317", "connection_timeout": 3}]
+	}`), &cfg)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 10, cfg.ConnectionTimeout)
+	assert.Equal(t, 500, cfg.Batch.Timeout)
+	assert.Equal(t, 5, cfg.Retry.InitialInterval)
+	assert.Equal(t, 30, cfg.Retry.MaxInterval)
+	assert.Equal(t, 3, cfg.Pipelines[0].ConnectionTimeout)
+	assert.Equal(t, 40, cfg.GRPC.Keepalive.Time)
+	assert.Equal(t, 60, cfg.LoadShedding.CoolDown)
+	assert.Equal(t, 300, cfg.ErrorLogInterval)
+
+	// the other fields of the structs are still unmarshalled
+	assert.True(t, cfg.Enabled)
+	assert.Equal(t, 100, cfg.Batch.Size)
+	assert.True(t, cfg.Retry.Enabled)
+	assert.Equal(t, "collector:4317", cfg.Pipelines[0].Endpoint)
+	assert.Equal(t, ROUNDROBINBALANCING, cfg.GRPC.LoadBalancing)
+
+	// the Go fields stay ints, assignable from int variables
+	timeout := 5
+	cfg.ConnectionTimeout = timeout
+
+	// the durations are still marshalled as numbers
+	data, err := json.Marshal(cfg.Batch)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"size": 100, "timeout": 500, "queue_size": 0}`, string(data))
+}
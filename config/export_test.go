@@ -0,0 +1,18 @@
+package config
+
+import "testing"
+
+// NewTestDeprecation returns a deprecation migrated by the given function, for the external tests.
+func NewTestDeprecation(field, replacement string, migrate func(cfg *OpenTelemetry) bool) Deprecation {
+	return Deprecation{Field: field, Replacement: replacement, migrate: migrate}
+}
+
+// SetTestDeprecations replaces the registered deprecations until the end of the test.
+func SetTestDeprecations(t *testing.T, d ...Deprecation) {
+	previous := deprecations
+	t.Cleanup(func() {
+		deprecations = previous
+	})
+
+	deprecations = d
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -20,15 +21,23 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 	defer cancel()
 
+	// the endpoint can be changed to export to the mock collector
+	endpoint := os.Getenv("OTEL_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "otel-collector:4317"
+	}
+
 	cfg := config.OpenTelemetry{
 		Enabled:           true,
 		Exporter:          "grpc",
-		Endpoint:          "otel-collector:4317",
+		Endpoint:          endpoint,
 		ConnectionTimeout: 10,
 		ResourceName:      "e2e-basic",
 		TLS: config.TLS{
-			Enable: false,
+			Enabled: false,
 		},
+		// the context propagation can be changed to run the same app under different propagators
+		ContextPropagation: os.Getenv("CONTEXT_PROPAGATION"),
 	}
 
 	log.Println("Initializing OpenTelemetry at e2e-basic:", cfg.Endpoint)
@@ -76,6 +85,36 @@ func main() {
 		}
 	}), provider, baseTykAttributes...))
 
+	// hop calls the /test endpoint of the same app, so the trace must contain both server spans
+	// if the context is correctly injected by the transport and extracted by the handler.
+	client := http.Client{Transport: trace.NewHTTPTransport(http.DefaultTransport)}
+
+	mux.Handle("/hop", trace.NewHTTPHandler("get_hop", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, "http://localhost:8080/test", nil)
+		if err != nil {
+			log.Printf("error on creating hop request %s", err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		res, err := client.Do(req)
+		if err != nil {
+			log.Printf("error on hop request %s", err.Error())
+			w.WriteHeader(http.StatusBadGateway)
+
+			return
+		}
+		defer res.Body.Close()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(res.StatusCode)
+
+		if _, err := io.Copy(w, res.Body); err != nil {
+			log.Printf("error on copying hop response %s", err.Error())
+		}
+	}), provider, baseTykAttributes...))
+
 	srv := &http.Server{
 		Addr:    ":8080",
 		Handler: mux,
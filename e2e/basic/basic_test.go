@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/TykTechnologies/opentelemetry/e2e/mockcollector"
+)
+
+// TestMockCollector checks the spans received by the mock collector from the basic app, started with
+// the e2e/basic and e2e/mockcollector docker compose files. It's skipped when MOCK_COLLECTOR_URL isn't set.
+func TestMockCollector(t *testing.T) {
+	collectorURL := os.Getenv("MOCK_COLLECTOR_URL")
+	if collectorURL == "" {
+		t.Skip("MOCK_COLLECTOR_URL is not set")
+	}
+
+	appURL := os.Getenv("BASIC_APP_URL")
+	if appURL == "" {
+		appURL = "http://localhost:8081"
+	}
+
+	const (
+		traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID = "00f067aa0ba902b7"
+	)
+
+	req, err := http.NewRequest(http.MethodGet, appURL+"/test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the incoming trace context sets the trace id of the spans looked up in the collector
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentSpanID+"-01")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code %d", res.StatusCode)
+	}
+
+	// the spans are exported by the batch span processor, within its 5 seconds timeout
+	spans := map[string]mockcollector.Span{}
+	deadline := time.Now().Add(15 * time.Second)
+
+	for len(spans) < 2 && time.Now().Before(deadline) {
+		time.Sleep(500 * time.Millisecond)
+
+		for _, span := range traceSpans(t, collectorURL, traceID) {
+			spans[span.Name] = span
+		}
+	}
+
+	server, ok := spans["GET /test"]
+	if !ok {
+		t.Fatalf("server span not received, got %v", spans)
+	}
+
+	child, ok := spans["childspan"]
+	if !ok {
+		t.Fatalf("child span not received, got %v", spans)
+	}
+
+	if server.ParentSpanID != parentSpanID {
+		t.Errorf("server span parent = %q, want %q", server.ParentSpanID, parentSpanID)
+	}
+
+	if server.Kind != "SPAN_KIND_SERVER" {
+		t.Errorf("server span kind = %q, want SPAN_KIND_SERVER", server.Kind)
+	}
+
+	if server.Attributes["tyk.api.name"] != "test" || server.Attributes["tyk.api.orgid"] != "fakeorg" {
+		t.Errorf("server span attributes = %v", server.Attributes)
+	}
+
+	if server.ResourceAttributes["service.name"] != "e2e-basic" {
+		t.Errorf("server span resource attributes = %v", server.ResourceAttributes)
+	}
+
+	if child.ParentSpanID != server.SpanID {
+		t.Errorf("child span parent = %q, want %q", child.ParentSpanID, server.SpanID)
+	}
+
+	// the JSON numbers are decoded as float64
+	if child.Attributes["test-string-attr"] != "value" || child.Attributes["test-int-attr"] != float64(1) {
+		t.Errorf("child span attributes = %v", child.Attributes)
+	}
+}
+
+// traceSpans returns the spans of the trace received by the mock collector.
+func traceSpans(t *testing.T, collectorURL, traceID string) []mockcollector.Span {
+	t.Helper()
+
+	res, err := http.Get(collectorURL + "/api/traces/" + traceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	spans := []mockcollector.Span{}
+	if err := json.NewDecoder(res.Body).Decode(&spans); err != nil {
+		t.Fatal(err)
+	}
+
+	return spans
+}
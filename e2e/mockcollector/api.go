@@ -0,0 +1,302 @@
+package mockcollector
+
+import (
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Span is the JSON representation of a received span.
+type Span struct {
+	TraceID            string                 `json:"trace_id"`
+	SpanID             string                 `json:"span_id"`
+	ParentSpanID       string                 `json:"parent_span_id"`
+	Name               string                 `json:"name"`
+	Kind               string                 `json:"kind"`
+	StartTime          time.Time              `json:"start_time"`
+	EndTime            time.Time              `json:"end_time"`
+	Attributes         map[string]interface{} `json:"attributes"`
+	ResourceAttributes map[string]interface{} `json:"resource_attributes"`
+	ScopeName          string                 `json:"scope_name"`
+	StatusCode         string                 `json:"status_code"`
+	StatusMessage      string                 `json:"status_message"`
+}
+
+// Metric is the JSON representation of a received metric.
+// Data contains the OTLP/JSON encoding of the metric data points.
+type Metric struct {
+	Name               string                 `json:"name"`
+	Description        string                 `json:"description"`
+	Unit               string                 `json:"unit"`
+	ResourceAttributes map[string]interface{} `json:"resource_attributes"`
+	ScopeName          string                 `json:"scope_name"`
+	Data               json.RawMessage        `json:"data"`
+}
+
+// Handler returns the HTTP handler with the OTLP/HTTP receiver and the query API:
+//   - POST /v1/traces and POST /v1/metrics receive OTLP/HTTP protobuf or JSON payloads.
+//   - GET /api/traces returns the IDs of all the received traces.
+//   - GET /api/traces/{traceID} returns the spans of the given trace.
+//   - GET /api/metrics/{name} returns the metrics with the given name.
+//   - DELETE /api/data removes all the received data.
+func (c *Collector) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /v1/traces", func(w http.ResponseWriter, r *http.Request) {
+		req := &coltracepb.ExportTraceServiceRequest{}
+		if err := readProto(r, req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		c.addSpans(spansFromProto(req.GetResourceSpans()))
+		writeProto(w, r, &coltracepb.ExportTraceServiceResponse{})
+	})
+
+	mux.HandleFunc("POST /v1/metrics", func(w http.ResponseWriter, r *http.Request) {
+		req := &colmetricpb.ExportMetricsServiceRequest{}
+		if err := readProto(r, req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		metrics, err := metricsFromProto(req.GetResourceMetrics())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		c.addMetrics(metrics)
+		writeProto(w, r, &colmetricpb.ExportMetricsServiceResponse{})
+	})
+
+	mux.HandleFunc("GET /api/traces", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, c.TraceIDs())
+	})
+
+	mux.HandleFunc("GET /api/traces/{traceID}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, c.Spans(r.PathValue("traceID")))
+	})
+
+	mux.HandleFunc("GET /api/metrics/{name}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, c.Metrics(r.PathValue("name")))
+	})
+
+	mux.HandleFunc("DELETE /api/data", func(w http.ResponseWriter, r *http.Request) {
+		c.Reset()
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	return mux
+}
+
+func readProto(r *http.Request, msg proto.Message) error {
+	var body io.Reader = r.Body
+
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+
+		body = gz
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if isJSONRequest(r) {
+		data, err = base64EncodeIDs(data)
+		if err != nil {
+			return err
+		}
+
+		return protojson.Unmarshal(data, msg)
+	}
+
+	return proto.Unmarshal(data, msg)
+}
+
+// writeProto writes the response with the same OTLP/HTTP encoding used by the request.
+func writeProto(w http.ResponseWriter, r *http.Request, msg proto.Message) {
+	marshal, contentType := proto.Marshal, "application/x-protobuf"
+	if isJSONRequest(r) {
+		marshal, contentType = protojson.Marshal, "application/json"
+	}
+
+	data, err := marshal(msg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(data)
+}
+
+func isJSONRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "application/json")
+}
+
+// base64EncodeIDs converts the hex encoded IDs used by OTLP/JSON to the base64
+// strings expected by the standard protobuf JSON mapping.
+func base64EncodeIDs(data []byte) ([]byte, error) {
+	var payload interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+
+	var convert func(v interface{}) error
+	convert = func(v interface{}) error {
+		switch value := v.(type) {
+		case map[string]interface{}:
+			for k, field := range value {
+				id, ok := field.(string)
+				if ok && (k == "traceId" || k == "spanId" || k == "parentSpanId") {
+					raw, err := hex.DecodeString(id)
+					if err != nil {
+						return err
+					}
+
+					value[k] = base64.StdEncoding.EncodeToString(raw)
+
+					continue
+				}
+
+				if err := convert(field); err != nil {
+					return err
+				}
+			}
+		case []interface{}:
+			for _, item := range value {
+				if err := convert(item); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	if err := convert(payload); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(payload)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func spansFromProto(resourceSpans []*tracepb.ResourceSpans) []Span {
+	var spans []Span
+
+	for _, rs := range resourceSpans {
+		resourceAttrs := attributesFromProto(rs.GetResource().GetAttributes())
+
+		for _, ss := range rs.GetScopeSpans() {
+			for _, s := range ss.GetSpans() {
+				spans = append(spans, Span{
+					TraceID:            hex.EncodeToString(s.GetTraceId()),
+					SpanID:             hex.EncodeToString(s.GetSpanId()),
+					ParentSpanID:       hex.EncodeToString(s.GetParentSpanId()),
+					Name:               s.GetName(),
+					Kind:               s.GetKind().String(),
+					StartTime:          time.Unix(0, int64(s.GetStartTimeUnixNano())).UTC(),
+					EndTime:            time.Unix(0, int64(s.GetEndTimeUnixNano())).UTC(),
+					Attributes:         attributesFromProto(s.GetAttributes()),
+					ResourceAttributes: resourceAttrs,
+					ScopeName:          ss.GetScope().GetName(),
+					StatusCode:         s.GetStatus().GetCode().String(),
+					StatusMessage:      s.GetStatus().GetMessage(),
+				})
+			}
+		}
+	}
+
+	return spans
+}
+
+func metricsFromProto(resourceMetrics []*metricspb.ResourceMetrics) ([]Metric, error) {
+	var metrics []Metric
+
+	for _, rm := range resourceMetrics {
+		resourceAttrs := attributesFromProto(rm.GetResource().GetAttributes())
+
+		for _, sm := range rm.GetScopeMetrics() {
+			for _, m := range sm.GetMetrics() {
+				data, err := protojson.Marshal(m)
+				if err != nil {
+					return nil, err
+				}
+
+				metrics = append(metrics, Metric{
+					Name:               m.GetName(),
+					Description:        m.GetDescription(),
+					Unit:               m.GetUnit(),
+					ResourceAttributes: resourceAttrs,
+					ScopeName:          sm.GetScope().GetName(),
+					Data:               data,
+				})
+			}
+		}
+	}
+
+	return metrics, nil
+}
+
+func attributesFromProto(kvs []*commonpb.KeyValue) map[string]interface{} {
+	attrs := make(map[string]interface{}, len(kvs))
+	for _, kv := range kvs {
+		attrs[kv.GetKey()] = valueFromProto(kv.GetValue())
+	}
+
+	return attrs
+}
+
+func valueFromProto(v *commonpb.AnyValue) interface{} {
+	switch value := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return value.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return value.BoolValue
+	case *commonpb.AnyValue_IntValue:
+		return value.IntValue
+	case *commonpb.AnyValue_DoubleValue:
+		return value.DoubleValue
+	case *commonpb.AnyValue_BytesValue:
+		return value.BytesValue
+	case *commonpb.AnyValue_ArrayValue:
+		values := make([]interface{}, 0, len(value.ArrayValue.GetValues()))
+		for _, item := range value.ArrayValue.GetValues() {
+			values = append(values, valueFromProto(item))
+		}
+
+		return values
+	case *commonpb.AnyValue_KvlistValue:
+		return attributesFromProto(value.KvlistValue.GetValues())
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/TykTechnologies/opentelemetry/e2e/mockcollector"
+)
+
+func main() {
+	grpcAddr := flag.String("grpc-addr", ":4317", "address of the OTLP gRPC receiver")
+	httpAddr := flag.String("http-addr", ":4318", "address of the OTLP/HTTP receiver and query API")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+
+	collector := mockcollector.New()
+	if err := collector.Start(*grpcAddr, *httpAddr); err != nil {
+		log.Printf("error on starting mock collector %s", err.Error())
+		return
+	}
+
+	log.Printf("mock collector listening on grpc %s and http %s", collector.GRPCAddr(), collector.HTTPAddr())
+
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := collector.Shutdown(shutdownCtx); err != nil {
+		log.Printf("mock collector shutdown: %v", err)
+	}
+}
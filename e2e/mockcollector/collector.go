@@ -0,0 +1,186 @@
+// Package mockcollector provides a lightweight OTLP collector for e2e tests.
+// It receives spans and metrics through OTLP gRPC and OTLP/HTTP (protobuf),
+// keeps them in memory and exposes a JSON query API to retrieve them by trace ID or metric name.
+package mockcollector
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+)
+
+// Collector is an in-memory OTLP collector.
+// It can be started in-process by tests or run as a container with the cmd package.
+type Collector struct {
+	mu      sync.RWMutex
+	spans   map[string][]Span
+	metrics map[string][]Metric
+
+	grpcServer *grpc.Server
+	httpServer *http.Server
+
+	grpcListener net.Listener
+	httpListener net.Listener
+}
+
+// New creates a new Collector with empty storage.
+func New() *Collector {
+	c := &Collector{
+		spans:   map[string][]Span{},
+		metrics: map[string][]Metric{},
+	}
+
+	c.grpcServer = grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(c.grpcServer, &traceService{collector: c})
+	colmetricpb.RegisterMetricsServiceServer(c.grpcServer, &metricsService{collector: c})
+
+	c.httpServer = &http.Server{
+		Handler: c.Handler(),
+	}
+
+	return c
+}
+
+// Start starts the OTLP gRPC receiver on grpcAddr and the HTTP server
+// (OTLP/HTTP receiver and query API) on httpAddr.
+// Use "localhost:0" to listen on random ports, and GRPCAddr/HTTPAddr to retrieve them.
+func (c *Collector) Start(grpcAddr, httpAddr string) error {
+	grpcListener, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		return err
+	}
+
+	httpListener, err := net.Listen("tcp", httpAddr)
+	if err != nil {
+		grpcListener.Close()
+		return err
+	}
+
+	c.grpcListener = grpcListener
+	c.httpListener = httpListener
+
+	go func() {
+		_ = c.grpcServer.Serve(grpcListener)
+	}()
+
+	go func() {
+		_ = c.httpServer.Serve(httpListener)
+	}()
+
+	return nil
+}
+
+// GRPCAddr returns the address of the OTLP gRPC receiver. It's empty if the collector is not started.
+func (c *Collector) GRPCAddr() string {
+	if c.grpcListener == nil {
+		return ""
+	}
+
+	return c.grpcListener.Addr().String()
+}
+
+// HTTPAddr returns the address of the HTTP server. It's empty if the collector is not started.
+func (c *Collector) HTTPAddr() string {
+	if c.httpListener == nil {
+		return ""
+	}
+
+	return c.httpListener.Addr().String()
+}
+
+// Shutdown stops the gRPC and HTTP servers.
+func (c *Collector) Shutdown(ctx context.Context) error {
+	c.grpcServer.GracefulStop()
+
+	return c.httpServer.Shutdown(ctx)
+}
+
+// Spans returns the spans received for the given hex encoded trace ID.
+func (c *Collector) Spans(traceID string) []Span {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]Span{}, c.spans[traceID]...)
+}
+
+// TraceIDs returns the hex encoded IDs of all the traces received.
+func (c *Collector) TraceIDs() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ids := make([]string, 0, len(c.spans))
+	for id := range c.spans {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// Metrics returns the metrics received with the given name.
+func (c *Collector) Metrics(name string) []Metric {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]Metric{}, c.metrics[name]...)
+}
+
+// Reset removes all the received data.
+func (c *Collector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.spans = map[string][]Span{}
+	c.metrics = map[string][]Metric{}
+}
+
+func (c *Collector) addSpans(spans []Span) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, span := range spans {
+		c.spans[span.TraceID] = append(c.spans[span.TraceID], span)
+	}
+}
+
+func (c *Collector) addMetrics(metrics []Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, metric := range metrics {
+		c.metrics[metric.Name] = append(c.metrics[metric.Name], metric)
+	}
+}
+
+type traceService struct {
+	coltracepb.UnimplementedTraceServiceServer
+	collector *Collector
+}
+
+func (s *traceService) Export(_ context.Context, req *coltracepb.ExportTraceServiceRequest,
+) (*coltracepb.ExportTraceServiceResponse, error) {
+	s.collector.addSpans(spansFromProto(req.GetResourceSpans()))
+
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+type metricsService struct {
+	colmetricpb.UnimplementedMetricsServiceServer
+	collector *Collector
+}
+
+func (s *metricsService) Export(_ context.Context, req *colmetricpb.ExportMetricsServiceRequest,
+) (*colmetricpb.ExportMetricsServiceResponse, error) {
+	metrics, err := metricsFromProto(req.GetResourceMetrics())
+	if err != nil {
+		return nil, err
+	}
+
+	s.collector.addMetrics(metrics)
+
+	return &colmetricpb.ExportMetricsServiceResponse{}, nil
+}
@@ -0,0 +1,152 @@
+package mockcollector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"github.com/TykTechnologies/opentelemetry/trace"
+	"github.com/stretchr/testify/assert"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func Test_Collector(t *testing.T) {
+	tcs := []struct {
+		name     string
+		exporter string
+		encoding string
+	}{
+		{
+			name:     "grpc exporter",
+			exporter: config.GRPCEXPORTER,
+		},
+		{
+			name:     "http exporter",
+			exporter: config.HTTPEXPORTER,
+		},
+		{
+			name:     "http exporter with json encoding",
+			exporter: config.HTTPEXPORTER,
+			encoding: config.JSONENCODING,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			collector := New()
+			err := collector.Start("localhost:0", "localhost:0")
+			assert.Nil(t, err)
+
+			defer collector.Shutdown(ctx)
+
+			endpoint := collector.GRPCAddr()
+			if tc.exporter == config.HTTPEXPORTER {
+				endpoint = collector.HTTPAddr()
+			}
+
+			provider, err := trace.NewProvider(trace.WithContext(ctx), trace.WithConfig(&config.OpenTelemetry{
+				Enabled:           true,
+				Exporter:          tc.exporter,
+				Endpoint:          endpoint,
+				HTTPEncoding:      tc.encoding,
+				ConnectionTimeout: 10,
+				ResourceName:      "mock-collector-test",
+				SpanProcessorType: "simple",
+			}))
+			assert.Nil(t, err)
+
+			spanCtx, parent := provider.Tracer().Start(ctx, "parent")
+			_, child := provider.Tracer().Start(spanCtx, "child")
+			child.SetAttributes(trace.NewAttribute("key", "value"))
+			child.End()
+			parent.End()
+
+			assert.Nil(t, provider.Shutdown(ctx))
+
+			traceID := parent.SpanContext().TraceID().String()
+
+			res, err := http.Get(fmt.Sprintf("http://%s/api/traces/%s", collector.HTTPAddr(), traceID))
+			assert.Nil(t, err)
+
+			defer res.Body.Close()
+
+			var spans []Span
+			assert.Nil(t, json.NewDecoder(res.Body).Decode(&spans))
+			assert.Len(t, spans, 2)
+
+			assert.Equal(t, "child", spans[0].Name)
+			assert.Equal(t, traceID, spans[0].TraceID)
+			assert.Equal(t, parent.SpanContext().SpanID().String(), spans[0].ParentSpanID)
+			assert.Equal(t, "value", spans[0].Attributes["key"])
+			assert.Equal(t, "mock-collector-test", spans[0].ResourceAttributes["service.name"])
+
+			assert.Equal(t, "parent", spans[1].Name)
+			assert.Equal(t, []string{traceID}, collector.TraceIDs())
+		})
+	}
+}
+
+func Test_CollectorReset(t *testing.T) {
+	collector := New()
+	collector.addSpans([]Span{{TraceID: "1", Name: "span"}})
+	collector.addMetrics([]Metric{{Name: "metric"}})
+
+	assert.Len(t, collector.Spans("1"), 1)
+	assert.Len(t, collector.Metrics("metric"), 1)
+
+	collector.Reset()
+
+	assert.Empty(t, collector.Spans("1"))
+	assert.Empty(t, collector.Metrics("metric"))
+}
+
+func Test_CollectorHTTPMetrics(t *testing.T) {
+	collector := New()
+	server := httptest.NewServer(collector.Handler())
+
+	defer server.Close()
+
+	payload, err := proto.Marshal(&colmetricpb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{
+			{
+				ScopeMetrics: []*metricspb.ScopeMetrics{
+					{
+						Metrics: []*metricspb.Metric{
+							{
+								Name: "http.server.request.count",
+								Unit: "1",
+								Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{}},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	assert.Nil(t, err)
+
+	res, err := http.Post(server.URL+"/v1/metrics", "application/x-protobuf", bytes.NewReader(payload))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	res.Body.Close()
+
+	res, err = http.Get(server.URL + "/api/metrics/http.server.request.count")
+	assert.Nil(t, err)
+
+	defer res.Body.Close()
+
+	var metrics []Metric
+	assert.Nil(t, json.NewDecoder(res.Body).Decode(&metrics))
+	assert.Len(t, metrics, 1)
+	assert.Equal(t, "1", metrics[0].Unit)
+	assert.Contains(t, string(metrics[0].Data), "sum")
+}
@@ -0,0 +1,130 @@
+// Package slogbridge integrates the library with log/slog: a slog.Handler that correlates the
+// log records with the active span, and an adapter backing the trace.Logger with a slog.Logger.
+package slogbridge
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/TykTechnologies/opentelemetry/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TraceIDKey is the key of the trace id attribute added to the log records.
+	TraceIDKey = "trace_id"
+	// SpanIDKey is the key of the span id attribute added to the log records.
+	SpanIDKey = "span_id"
+	// TraceFlagsKey is the key of the trace flags attribute added to the log records.
+	TraceFlagsKey = "trace_flags"
+)
+
+// handler adds the trace correlation attributes of the span in the record context.
+type handler struct {
+	next slog.Handler
+}
+
+var _ slog.Handler = &handler{}
+
+/*
+	NewHandler wraps the given slog.Handler with one that adds the trace_id, span_id and
+	trace_flags attributes to the records logged with a context holding a valid span,
+	so the logs can be correlated with the traces.
+
+Example
+
+	logger := slog.New(slogbridge.NewHandler(slog.NewJSONHandler(os.Stdout, nil)))
+	logger.InfoContext(ctx, "request processed")
+*/
+func NewHandler(next slog.Handler) slog.Handler {
+	return &handler{next: next}
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		if spanCtx := oteltrace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+			record = record.Clone()
+			record.AddAttrs(
+				slog.String(TraceIDKey, spanCtx.TraceID().String()),
+				slog.String(SpanIDKey, spanCtx.SpanID().String()),
+				slog.String(TraceFlagsKey, spanCtx.TraceFlags().String()),
+			)
+		}
+	}
+
+	return h.next.Handle(ctx, record)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &handler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{next: h.next.WithGroup(name)}
+}
+
+// logger adapts a slog.Logger to the trace.Logger interface.
+type logger struct {
+	logger *slog.Logger
+}
+
+var _ trace.Logger = &logger{}
+
+/*
+	NewLogger returns a trace.Logger backed by the given slog.Logger, or by slog.Default if it's nil.
+	The errors passed as arguments are logged as the error attribute, and the other arguments
+	are formatted as the message.
+
+Example
+
+	provider, err := trace.NewProvider(
+		trace.WithConfig(cfg),
+		trace.WithLogger(slogbridge.NewLogger(slog.Default())),
+	)
+*/
+func NewLogger(l *slog.Logger) trace.Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+
+	return &logger{logger: l}
+}
+
+func (l *logger) Info(args ...interface{}) {
+	l.log(slog.LevelInfo, args)
+}
+
+// Warn logs at the warn level, used by the trace package for the warnings.
+func (l *logger) Warn(args ...interface{}) {
+	l.log(slog.LevelWarn, args)
+}
+
+func (l *logger) Error(args ...interface{}) {
+	l.log(slog.LevelError, args)
+}
+
+func (l *logger) log(level slog.Level, args []interface{}) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	msgArgs := make([]interface{}, 0, len(args))
+	attrs := []slog.Attr{}
+
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			attrs = append(attrs, slog.Any("error", err))
+			continue
+		}
+
+		msgArgs = append(msgArgs, arg)
+	}
+
+	l.logger.LogAttrs(ctx, level, fmt.Sprint(msgArgs...), attrs...)
+}
@@ -0,0 +1,98 @@
+package slogbridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+
+	record := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &record))
+
+	return record
+}
+
+func TestHandler(t *testing.T) {
+	t.Run("span in context", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := slog.New(NewHandler(slog.NewJSONHandler(buf, nil))).With("component", "gateway")
+
+		ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "span")
+		defer span.End()
+
+		logger.InfoContext(ctx, "request processed")
+
+		record := decode(t, buf)
+		assert.Equal(t, "request processed", record["msg"])
+		assert.Equal(t, "gateway", record["component"])
+		assert.Equal(t, span.SpanContext().TraceID().String(), record[TraceIDKey])
+		assert.Equal(t, span.SpanContext().SpanID().String(), record[SpanIDKey])
+		assert.Equal(t, "01", record[TraceFlagsKey])
+	})
+
+	t.Run("no span in context", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := slog.New(NewHandler(slog.NewJSONHandler(buf, nil)))
+
+		logger.InfoContext(context.Background(), "request processed")
+
+		record := decode(t, buf)
+		assert.NotContains(t, record, TraceIDKey)
+		assert.NotContains(t, record, SpanIDKey)
+	})
+
+	t.Run("groups", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := slog.New(NewHandler(slog.NewJSONHandler(buf, nil))).WithGroup("request")
+
+		ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "span")
+		defer span.End()
+
+		logger.InfoContext(ctx, "request processed", "path", "/test")
+
+		record := decode(t, buf)
+		assert.Equal(t, map[string]interface{}{
+			"path":        "/test",
+			TraceIDKey:    span.SpanContext().TraceID().String(),
+			SpanIDKey:     span.SpanContext().SpanID().String(),
+			TraceFlagsKey: "01",
+		}, record["request"])
+	})
+}
+
+func TestLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewLogger(slog.New(slog.NewJSONHandler(buf, nil)))
+
+	logger.Error("failed to create exporter", errors.New("connection refused"))
+
+	record := decode(t, buf)
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "failed to create exporter", record["msg"])
+	assert.Equal(t, "connection refused", record["error"])
+
+	buf.Reset()
+	logger.Info("Tracer provider initialized successfully")
+
+	record = decode(t, buf)
+	assert.Equal(t, "INFO", record["level"])
+	assert.Equal(t, "Tracer provider initialized successfully", record["msg"])
+
+	buf.Reset()
+	logger.(interface{ Warn(...interface{}) }).Warn("config field is deprecated")
+
+	record = decode(t, buf)
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "config field is deprecated", record["msg"])
+
+	assert.NotNil(t, NewLogger(nil))
+}
@@ -0,0 +1,119 @@
+// Package metric provides metric instruments following the OpenTelemetry semantic conventions,
+// built from the MeterProvider of the caller, such as the GenAI client instruments used by the
+// AI gateways for their usage and billing insight.
+package metric
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+const meterName = "github.com/TykTechnologies/opentelemetry/metric"
+
+const (
+	// GenAITokenUsageName is the name of the histogram with the number of tokens used per operation.
+	GenAITokenUsageName = "gen_ai.client.token.usage"
+	// GenAIOperationDurationName is the name of the histogram with the duration of the operations in seconds.
+	GenAIOperationDurationName = "gen_ai.client.operation.duration"
+
+	// GenAIOperationNameKey is the name of the operation requested, such as "chat" or "embeddings".
+	GenAIOperationNameKey = attribute.Key("gen_ai.operation.name")
+	// GenAIRequestModelKey is the name of the model requested.
+	GenAIRequestModelKey = attribute.Key("gen_ai.request.model")
+	// GenAITokenTypeKey is the type of the tokens counted, GenAITokenTypeInput or GenAITokenTypeOutput.
+	GenAITokenTypeKey = attribute.Key("gen_ai.token.type")
+
+	GenAITokenTypeInput  = "input"
+	GenAITokenTypeOutput = "output"
+)
+
+var (
+	// tokenUsageBuckets and operationDurationBuckets are the bucket boundaries advised
+	// by the semantic conventions.
+	tokenUsageBuckets = []float64{1, 4, 16, 64, 256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304,
+		16777216, 67108864}
+	operationDurationBuckets = []float64{0.01, 0.02, 0.04, 0.08, 0.16, 0.32, 0.64, 1.28, 2.56, 5.12, 10.24,
+		20.48, 40.96, 81.92}
+)
+
+// GenAI records the gen_ai.client.token.usage and gen_ai.client.operation.duration histograms,
+// keyed by the operation name and the requested model.
+type GenAI struct {
+	tokenUsage        otelmetric.Int64Histogram
+	operationDuration otelmetric.Float64Histogram
+}
+
+/*
+	NewGenAI creates the GenAI client instruments with the given MeterProvider,
+	or with the global one if it's nil.
+
+Example
+
+	genAI, err := metric.NewGenAI(meterProvider)
+	if err != nil {
+		panic(err)
+	}
+
+	genAI.RecordTokenUsage(ctx, "chat", "gpt-4o", usage.PromptTokens, usage.CompletionTokens)
+	genAI.RecordOperationDuration(ctx, "chat", "gpt-4o", time.Since(start))
+*/
+func NewGenAI(mp otelmetric.MeterProvider) (*GenAI, error) {
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+
+	meter := mp.Meter(meterName)
+
+	tokenUsage, err := meter.Int64Histogram(GenAITokenUsageName,
+		otelmetric.WithDescription("Measures number of input and output tokens used."),
+		otelmetric.WithUnit("{token}"),
+		otelmetric.WithExplicitBucketBoundaries(tokenUsageBuckets...))
+	if err != nil {
+		return nil, err
+	}
+
+	operationDuration, err := meter.Float64Histogram(GenAIOperationDurationName,
+		otelmetric.WithDescription("GenAI operation duration."),
+		otelmetric.WithUnit("s"),
+		otelmetric.WithExplicitBucketBoundaries(operationDurationBuckets...))
+	if err != nil {
+		return nil, err
+	}
+
+	return &GenAI{
+		tokenUsage:        tokenUsage,
+		operationDuration: operationDuration,
+	}, nil
+}
+
+// RecordTokenUsage records the input and output tokens used by an operation on the model.
+// The attributes are added to the operation and model ones, such as the gen_ai.system.
+func (g *GenAI) RecordTokenUsage(ctx context.Context, operation, model string, inputTokens, outputTokens int64,
+	attrs ...attribute.KeyValue) {
+	g.tokenUsage.Record(ctx, inputTokens,
+		genAIAttributes(operation, model, attrs, GenAITokenTypeKey.String(GenAITokenTypeInput)))
+	g.tokenUsage.Record(ctx, outputTokens,
+		genAIAttributes(operation, model, attrs, GenAITokenTypeKey.String(GenAITokenTypeOutput)))
+}
+
+// RecordOperationDuration records the duration of an operation on the model.
+// The attributes are added to the operation and model ones, such as the error.type of the failed operations.
+func (g *GenAI) RecordOperationDuration(ctx context.Context, operation, model string, duration time.Duration,
+	attrs ...attribute.KeyValue) {
+	g.operationDuration.Record(ctx, duration.Seconds(), genAIAttributes(operation, model, attrs))
+}
+
+// genAIAttributes returns the attributes of a measurement, without modifying the ones of the caller.
+func genAIAttributes(operation, model string, attrs []attribute.KeyValue,
+	extra ...attribute.KeyValue) otelmetric.MeasurementOption {
+	all := make([]attribute.KeyValue, 0, len(attrs)+len(extra)+2)
+	all = append(all, attrs...)
+	all = append(all, extra...)
+	all = append(all, GenAIOperationNameKey.String(operation), GenAIRequestModelKey.String(model))
+
+	return otelmetric.WithAttributes(all...)
+}
@@ -0,0 +1,102 @@
+package metric
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func collect(t *testing.T, reader sdkmetric.Reader) map[string]metricdata.Metrics {
+	t.Helper()
+
+	rm := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	metrics := map[string]metricdata.Metrics{}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			metrics[m.Name] = m
+		}
+	}
+
+	return metrics
+}
+
+func TestGenAI(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	genAI, err := NewGenAI(provider)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	system := attribute.String("gen_ai.system", "openai")
+
+	genAI.RecordTokenUsage(ctx, "chat", "gpt-4o", 100, 20, system)
+	genAI.RecordTokenUsage(ctx, "chat", "gpt-4o", 50, 10, system)
+	genAI.RecordTokenUsage(ctx, "embeddings", "text-embedding-3-small", 30, 0, system)
+	genAI.RecordOperationDuration(ctx, "chat", "gpt-4o", 1500*time.Millisecond, system)
+
+	metrics := collect(t, reader)
+
+	t.Run("token usage", func(t *testing.T) {
+		m, ok := metrics[GenAITokenUsageName]
+		require.True(t, ok)
+		assert.Equal(t, "{token}", m.Unit)
+
+		histogram, ok := m.Data.(metricdata.Histogram[int64])
+		require.True(t, ok)
+
+		sums := map[attribute.Set]int64{}
+		for _, dp := range histogram.DataPoints {
+			sums[dp.Attributes] = dp.Sum
+			assert.Equal(t, tokenUsageBuckets, dp.Bounds)
+		}
+
+		expected := map[attribute.Set]int64{
+			attribute.NewSet(system, GenAIOperationNameKey.String("chat"), GenAIRequestModelKey.String("gpt-4o"),
+				GenAITokenTypeKey.String(GenAITokenTypeInput)): 150,
+			attribute.NewSet(system, GenAIOperationNameKey.String("chat"), GenAIRequestModelKey.String("gpt-4o"),
+				GenAITokenTypeKey.String(GenAITokenTypeOutput)): 30,
+			attribute.NewSet(system, GenAIOperationNameKey.String("embeddings"),
+				GenAIRequestModelKey.String("text-embedding-3-small"), GenAITokenTypeKey.String(GenAITokenTypeInput)): 30,
+			attribute.NewSet(system, GenAIOperationNameKey.String("embeddings"),
+				GenAIRequestModelKey.String("text-embedding-3-small"), GenAITokenTypeKey.String(GenAITokenTypeOutput)): 0,
+		}
+
+		assert.Equal(t, expected, sums)
+	})
+
+	t.Run("operation duration", func(t *testing.T) {
+		m, ok := metrics[GenAIOperationDurationName]
+		require.True(t, ok)
+		assert.Equal(t, "s", m.Unit)
+
+		histogram, ok := m.Data.(metricdata.Histogram[float64])
+		require.True(t, ok)
+		require.Len(t, histogram.DataPoints, 1)
+
+		dp := histogram.DataPoints[0]
+		assert.Equal(t, attribute.NewSet(system, GenAIOperationNameKey.String("chat"),
+			GenAIRequestModelKey.String("gpt-4o")), dp.Attributes)
+		assert.Equal(t, uint64(1), dp.Count)
+		assert.Equal(t, 1.5, dp.Sum)
+		assert.Equal(t, operationDurationBuckets, dp.Bounds)
+	})
+}
+
+func TestGenAIAttributes(t *testing.T) {
+	attrs := make([]attribute.KeyValue, 1, 10)
+	attrs[0] = attribute.String("gen_ai.system", "openai")
+
+	genAIAttributes("chat", "gpt-4o", attrs, GenAITokenTypeKey.String(GenAITokenTypeInput))
+
+	// the spare capacity of the caller slice must not be written to
+	assert.Equal(t, attribute.KeyValue{}, attrs[:2][1], "the attributes of the caller should not be modified")
+}
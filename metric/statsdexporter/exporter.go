@@ -0,0 +1,282 @@
+// Package statsdexporter provides an OpenTelemetry metric exporter mirroring the counters, gauges and
+// histograms to a statsd or DogStatsD endpoint, for the deployments migrating from statsd pipelines.
+// It's meant to be used as a secondary exporter, next to the OTLP one, until the migration is complete.
+package statsdexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// maxPacketSize is the maximum size of the UDP packets, so they fit in the MTU of most networks.
+const maxPacketSize = 1432
+
+var errShutdown = errors.New("statsd exporter is shut down")
+
+type Option interface {
+	apply(*exporter)
+}
+
+type opts struct {
+	fn func(*exporter)
+}
+
+func (o *opts) apply(e *exporter) {
+	o.fn(e)
+}
+
+// WithPrefix sets the prefix of the metric names, e.g. "tyk." turns "http.server.duration"
+// into "tyk.http.server.duration".
+func WithPrefix(prefix string) Option {
+	return &opts{
+		fn: func(e *exporter) {
+			e.prefix = prefix
+		},
+	}
+}
+
+// WithDogStatsDTags sends the attributes of the data points as DogStatsD tags.
+// The plain statsd protocol has no tags, so they're dropped by default.
+func WithDogStatsDTags() Option {
+	return &opts{
+		fn: func(e *exporter) {
+			e.tags = true
+		},
+	}
+}
+
+type exporter struct {
+	prefix string
+	tags   bool
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+var _ sdkmetric.Exporter = &exporter{}
+
+/*
+	New returns a metric exporter sending the metrics to the statsd endpoint over UDP.
+	The counters are sent as statsd counters of their increment since the previous export, the gauges
+	as gauges, and the up-down counters as relative gauges. The statsd histograms are computed from the
+	individual measurements, which aren't available to the exporters, so the OpenTelemetry histograms
+	are sent as the "<name>.count" and "<name>.sum" counters and the "<name>.min" and "<name>.max" gauges.
+
+Example
+
+	exporter, err := statsdexporter.New("localhost:8125", statsdexporter.WithDogStatsDTags())
+	if err != nil {
+		panic(err)
+	}
+
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(otlpExporter)),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)),
+	)
+*/
+func New(address string, opts ...Option) (sdkmetric.Exporter, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to statsd: %w", err)
+	}
+
+	e := &exporter{conn: conn}
+	for _, opt := range opts {
+		opt.apply(e)
+	}
+
+	return e, nil
+}
+
+// Temporality returns the delta temporality, since the statsd counters are increments.
+func (e *exporter) Temporality(sdkmetric.InstrumentKind) metricdata.Temporality {
+	return metricdata.DeltaTemporality
+}
+
+func (e *exporter) Aggregation(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(kind)
+}
+
+func (e *exporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	lines := []string{}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			lines = append(lines, e.format(m)...)
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn == nil {
+		return errShutdown
+	}
+
+	for _, packet := range packets(lines) {
+		if _, err := e.conn.Write([]byte(packet)); err != nil {
+			return fmt.Errorf("failed to send metrics to statsd: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (e *exporter) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (e *exporter) Shutdown(context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn == nil {
+		return nil
+	}
+
+	err := e.conn.Close()
+	e.conn = nil
+
+	return err
+}
+
+// format returns the statsd lines of the data points of the metric.
+func (e *exporter) format(m metricdata.Metrics) []string {
+	name := e.prefix + sanitize(m.Name)
+
+	switch data := m.Data.(type) {
+	case metricdata.Sum[int64]:
+		return formatSum(e, name, data)
+	case metricdata.Sum[float64]:
+		return formatSum(e, name, data)
+	case metricdata.Gauge[int64]:
+		return formatGauge(e, name, data)
+	case metricdata.Gauge[float64]:
+		return formatGauge(e, name, data)
+	case metricdata.Histogram[int64]:
+		return formatHistogram(e, name, data)
+	case metricdata.Histogram[float64]:
+		return formatHistogram(e, name, data)
+	default:
+		// the exponential histograms and summaries have no statsd equivalent
+		return nil
+	}
+}
+
+func formatSum[N int64 | float64](e *exporter, name string, sum metricdata.Sum[N]) []string {
+	lines := make([]string, 0, len(sum.DataPoints))
+
+	for _, dp := range sum.DataPoints {
+		if sum.IsMonotonic {
+			lines = append(lines, e.line(name, formatValue(dp.Value), "c", dp.Attributes))
+			continue
+		}
+
+		// the up-down counters are relative gauges, the sign is required so the value isn't set
+		value := formatValue(dp.Value)
+		if dp.Value >= 0 {
+			value = "+" + value
+		}
+
+		lines = append(lines, e.line(name, value, "g", dp.Attributes))
+	}
+
+	return lines
+}
+
+func formatGauge[N int64 | float64](e *exporter, name string, gauge metricdata.Gauge[N]) []string {
+	lines := make([]string, 0, len(gauge.DataPoints))
+
+	for _, dp := range gauge.DataPoints {
+		lines = append(lines, e.line(name, formatValue(dp.Value), "g", dp.Attributes))
+	}
+
+	return lines
+}
+
+func formatHistogram[N int64 | float64](e *exporter, name string, histogram metricdata.Histogram[N]) []string {
+	lines := make([]string, 0, 4*len(histogram.DataPoints))
+
+	for _, dp := range histogram.DataPoints {
+		lines = append(lines,
+			e.line(name+".count", strconv.FormatUint(dp.Count, 10), "c", dp.Attributes),
+			e.line(name+".sum", formatValue(dp.Sum), "c", dp.Attributes),
+		)
+
+		if min, ok := dp.Min.Value(); ok {
+			lines = append(lines, e.line(name+".min", formatValue(min), "g", dp.Attributes))
+		}
+
+		if max, ok := dp.Max.Value(); ok {
+			lines = append(lines, e.line(name+".max", formatValue(max), "g", dp.Attributes))
+		}
+	}
+
+	return lines
+}
+
+// line returns the statsd line of a value, with the DogStatsD tags if enabled.
+func (e *exporter) line(name, value, metricType string, attrs attribute.Set) string {
+	line := name + ":" + value + "|" + metricType
+
+	if !e.tags || attrs.Len() == 0 {
+		return line
+	}
+
+	tags := make([]string, 0, attrs.Len())
+	for _, kv := range attrs.ToSlice() {
+		tags = append(tags, sanitizeTag(string(kv.Key))+":"+sanitizeTag(kv.Value.Emit()))
+	}
+
+	sort.Strings(tags)
+
+	return line + "|#" + strings.Join(tags, ",")
+}
+
+func formatValue[N int64 | float64](value N) string {
+	return strconv.FormatFloat(float64(value), 'f', -1, 64)
+}
+
+// packets groups the lines into packets up to maxPacketSize, separated by new lines.
+func packets(lines []string) []string {
+	packets := []string{}
+	packet := strings.Builder{}
+
+	for _, line := range lines {
+		if packet.Len() > 0 && packet.Len()+1+len(line) > maxPacketSize {
+			packets = append(packets, packet.String())
+			packet.Reset()
+		}
+
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+
+		packet.WriteString(line)
+	}
+
+	if packet.Len() > 0 {
+		packets = append(packets, packet.String())
+	}
+
+	return packets
+}
+
+// sanitize replaces the characters reserved by the statsd protocol in the metric names.
+var sanitize = strings.NewReplacer(":", "_", "|", "_", "@", "_", "\n", "_").Replace
+
+// sanitizeTag replaces the characters reserved by the DogStatsD tags.
+var sanitizeTag = strings.NewReplacer(":", "_", "|", "_", ",", "_", "#", "_", "\n", "_").Replace
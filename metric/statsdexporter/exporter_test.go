@@ -0,0 +1,189 @@
+package statsdexporter
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// listen returns a UDP listener and a function reading the statsd lines it received.
+func listen(t *testing.T) (string, func() []string) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	read := func() []string {
+		lines := []string{}
+		buf := make([]byte, 65536)
+
+		for {
+			require.NoError(t, conn.SetReadDeadline(time.Now().Add(200*time.Millisecond)))
+
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				break
+			}
+
+			lines = append(lines, strings.Split(string(buf[:n]), "\n")...)
+		}
+
+		sort.Strings(lines)
+
+		return lines
+	}
+
+	return conn.LocalAddr().String(), read
+}
+
+func TestExporter(t *testing.T) {
+	tcs := []struct {
+		name          string
+		opts          []Option
+		expectedLines []string
+	}{
+		{
+			name: "statsd",
+			expectedLines: []string{
+				"http.server.active_requests:+2|g",
+				"http.server.duration.count:2|c",
+				"http.server.duration.max:30|g",
+				"http.server.duration.min:10|g",
+				"http.server.duration.sum:40|c",
+				"http.server.requests:3|c",
+				"process.goroutines:12|g",
+			},
+		},
+		{
+			name: "prefix",
+			opts: []Option{WithPrefix("tyk.")},
+			expectedLines: []string{
+				"tyk.http.server.active_requests:+2|g",
+				"tyk.http.server.duration.count:2|c",
+				"tyk.http.server.duration.max:30|g",
+				"tyk.http.server.duration.min:10|g",
+				"tyk.http.server.duration.sum:40|c",
+				"tyk.http.server.requests:3|c",
+				"tyk.process.goroutines:12|g",
+			},
+		},
+		{
+			name: "dogstatsd tags",
+			opts: []Option{WithDogStatsDTags()},
+			expectedLines: []string{
+				"http.server.active_requests:+2|g",
+				"http.server.duration.count:2|c|#tyk.api.id:api_1",
+				"http.server.duration.max:30|g|#tyk.api.id:api_1",
+				"http.server.duration.min:10|g|#tyk.api.id:api_1",
+				"http.server.duration.sum:40|c|#tyk.api.id:api_1",
+				"http.server.requests:3|c|#http.status_code:200,tyk.api.id:api_1",
+				"process.goroutines:12|g",
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			address, read := listen(t)
+
+			exporter, err := New(address, tc.opts...)
+			require.NoError(t, err)
+
+			reader := sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(time.Hour))
+			provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+			t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+			ctx := context.Background()
+			meter := provider.Meter("test")
+			attrs := metric.WithAttributes(attribute.String("tyk.api.id", "api:1"))
+
+			requests, err := meter.Int64Counter("http.server.requests")
+			require.NoError(t, err)
+			requests.Add(ctx, 3, attrs, metric.WithAttributes(attribute.Int("http.status_code", 200)))
+
+			duration, err := meter.Float64Histogram("http.server.duration")
+			require.NoError(t, err)
+			duration.Record(ctx, 10, attrs)
+			duration.Record(ctx, 30, attrs)
+
+			activeRequests, err := meter.Int64UpDownCounter("http.server.active_requests")
+			require.NoError(t, err)
+			activeRequests.Add(ctx, 2)
+
+			goroutines, err := meter.Int64Gauge("process.goroutines")
+			require.NoError(t, err)
+			goroutines.Record(ctx, 12)
+
+			require.NoError(t, provider.ForceFlush(ctx))
+
+			assert.Equal(t, tc.expectedLines, read())
+		})
+	}
+}
+
+func TestExporter_Delta(t *testing.T) {
+	address, read := listen(t)
+
+	exporter, err := New(address)
+	require.NoError(t, err)
+
+	reader := sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(time.Hour))
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	ctx := context.Background()
+
+	counter, err := provider.Meter("test").Int64Counter("requests")
+	require.NoError(t, err)
+
+	counter.Add(ctx, 3)
+	require.NoError(t, provider.ForceFlush(ctx))
+	assert.Equal(t, []string{"requests:3|c"}, read())
+
+	counter.Add(ctx, 2)
+	require.NoError(t, provider.ForceFlush(ctx))
+	assert.Equal(t, []string{"requests:2|c"}, read(), "only the increment since the previous export should be sent")
+}
+
+func TestExporter_Shutdown(t *testing.T) {
+	address, _ := listen(t)
+
+	exporter, err := New(address)
+	require.NoError(t, err)
+
+	require.NoError(t, exporter.Shutdown(context.Background()))
+	require.NoError(t, exporter.Shutdown(context.Background()), "the shutdown should be idempotent")
+
+	err = exporter.Export(context.Background(), &metricdata.ResourceMetrics{})
+	assert.ErrorIs(t, err, errShutdown)
+}
+
+func TestPackets(t *testing.T) {
+	line := strings.Repeat("a", 600) + ":1|c"
+
+	packets := packets([]string{line, line, line})
+
+	require.Len(t, packets, 2)
+	assert.Equal(t, line+"\n"+line, packets[0])
+	assert.Equal(t, line, packets[1])
+
+	for _, packet := range packets {
+		assert.LessOrEqual(t, len(packet), maxPacketSize)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	assert.Equal(t, "a_b_c_d", sanitize("a:b|c@d"))
+	assert.Equal(t, "a_b_c_d", sanitizeTag("a,b#c|d"))
+}
@@ -0,0 +1,113 @@
+package semconv
+
+import (
+	"github.com/TykTechnologies/opentelemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// TykAuthPrefix is the base prefix for all the Tyk authentication attributes
+	TykAuthPrefix = "tyk.auth."
+)
+
+// Authentication related attributes
+const (
+	// represents the authentication method used by the request
+	TykAuthMethodKey = attribute.Key(TykAuthPrefix + "method")
+
+	// represents the outcome of the authentication
+	TykAuthOutcomeKey = attribute.Key(TykAuthPrefix + "outcome")
+
+	// represents the reason of a failed authentication
+	TykAuthFailureReasonKey = attribute.Key(TykAuthPrefix + "failure_reason")
+)
+
+// AuthMethod is a value of the "tyk.auth.method" semantic convention.
+type AuthMethod string
+
+// Values of the "tyk.auth.method" semantic convention
+const (
+	AuthMethodKeyless   AuthMethod = "keyless"
+	AuthMethodAuthToken AuthMethod = "auth_token"
+	AuthMethodBasic     AuthMethod = "basic"
+	AuthMethodHMAC      AuthMethod = "hmac"
+	AuthMethodJWT       AuthMethod = "jwt"
+	AuthMethodOAuth     AuthMethod = "oauth"
+	AuthMethodOIDC      AuthMethod = "oidc"
+	AuthMethodMTLS      AuthMethod = "mtls"
+	AuthMethodCustom    AuthMethod = "custom"
+	AuthMethodOther     AuthMethod = "other"
+)
+
+// AuthOutcome is a value of the "tyk.auth.outcome" semantic convention.
+type AuthOutcome string
+
+// Values of the "tyk.auth.outcome" semantic convention
+const (
+	AuthOutcomeSuccess AuthOutcome = "success"
+	AuthOutcomeFailure AuthOutcome = "failure"
+	AuthOutcomeOther   AuthOutcome = "other"
+)
+
+// AuthFailureReason is a value of the "tyk.auth.failure_reason" semantic convention.
+type AuthFailureReason string
+
+// Values of the "tyk.auth.failure_reason" semantic convention
+const (
+	AuthFailureMissingCredentials AuthFailureReason = "missing_credentials"
+	AuthFailureInvalidCredentials AuthFailureReason = "invalid_credentials"
+	AuthFailureExpired            AuthFailureReason = "expired"
+	AuthFailureForbidden          AuthFailureReason = "forbidden"
+	AuthFailureInternalError      AuthFailureReason = "internal_error"
+	AuthFailureOther              AuthFailureReason = "other"
+)
+
+var (
+	authMethods = map[AuthMethod]bool{
+		AuthMethodKeyless: true, AuthMethodAuthToken: true, AuthMethodBasic: true, AuthMethodHMAC: true,
+		AuthMethodJWT: true, AuthMethodOAuth: true, AuthMethodOIDC: true, AuthMethodMTLS: true,
+		AuthMethodCustom: true, AuthMethodOther: true,
+	}
+
+	authOutcomes = map[AuthOutcome]bool{
+		AuthOutcomeSuccess: true, AuthOutcomeFailure: true, AuthOutcomeOther: true,
+	}
+
+	authFailureReasons = map[AuthFailureReason]bool{
+		AuthFailureMissingCredentials: true, AuthFailureInvalidCredentials: true, AuthFailureExpired: true,
+		AuthFailureForbidden: true, AuthFailureInternalError: true, AuthFailureOther: true,
+	}
+)
+
+// TykAuthMethod returns an attribute KeyValue conforming to the
+// "tyk.auth.method" semantic convention. It represents the authentication
+// method used by the request. Values outside the vocabulary are reported as "other".
+func TykAuthMethod(method AuthMethod) trace.Attribute {
+	if !authMethods[method] {
+		method = AuthMethodOther
+	}
+
+	return TykAuthMethodKey.String(string(method))
+}
+
+// TykAuthOutcome returns an attribute KeyValue conforming to the
+// "tyk.auth.outcome" semantic convention. It represents the outcome of the
+// authentication. Values outside the vocabulary are reported as "other".
+func TykAuthOutcome(outcome AuthOutcome) trace.Attribute {
+	if !authOutcomes[outcome] {
+		outcome = AuthOutcomeOther
+	}
+
+	return TykAuthOutcomeKey.String(string(outcome))
+}
+
+// TykAuthFailureReason returns an attribute KeyValue conforming to the
+// "tyk.auth.failure_reason" semantic convention. It represents the reason of a
+// failed authentication. Values outside the vocabulary are reported as "other".
+func TykAuthFailureReason(reason AuthFailureReason) trace.Attribute {
+	if !authFailureReasons[reason] {
+		reason = AuthFailureOther
+	}
+
+	return TykAuthFailureReasonKey.String(string(reason))
+}
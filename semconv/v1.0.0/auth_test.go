@@ -0,0 +1,38 @@
+package semconv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestTykAuthMethod(t *testing.T) {
+	expectedAttribute := attribute.Key(TykAuthPrefix + "method").String("jwt")
+	actualAttribute := TykAuthMethod(AuthMethodJWT)
+	assert.Equal(t, expectedAttribute, actualAttribute, "The attributes should be equal")
+
+	expectedAttribute = attribute.Key(TykAuthPrefix + "method").String("other")
+	actualAttribute = TykAuthMethod("saml")
+	assert.Equal(t, expectedAttribute, actualAttribute, "Unknown methods should be reported as other")
+}
+
+func TestTykAuthOutcome(t *testing.T) {
+	expectedAttribute := attribute.Key(TykAuthPrefix + "outcome").String("failure")
+	actualAttribute := TykAuthOutcome(AuthOutcomeFailure)
+	assert.Equal(t, expectedAttribute, actualAttribute, "The attributes should be equal")
+
+	expectedAttribute = attribute.Key(TykAuthPrefix + "outcome").String("other")
+	actualAttribute = TykAuthOutcome("Success")
+	assert.Equal(t, expectedAttribute, actualAttribute, "Unknown outcomes should be reported as other")
+}
+
+func TestTykAuthFailureReason(t *testing.T) {
+	expectedAttribute := attribute.Key(TykAuthPrefix + "failure_reason").String("expired")
+	actualAttribute := TykAuthFailureReason(AuthFailureExpired)
+	assert.Equal(t, expectedAttribute, actualAttribute, "The attributes should be equal")
+
+	expectedAttribute = attribute.Key(TykAuthPrefix + "failure_reason").String("other")
+	actualAttribute = TykAuthFailureReason("key not found in redis")
+	assert.Equal(t, expectedAttribute, actualAttribute, "Unknown reasons should be reported as other")
+}
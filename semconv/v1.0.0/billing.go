@@ -0,0 +1,76 @@
+package semconv
+
+import (
+	"github.com/TykTechnologies/opentelemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// TykBillingPrefix is the base prefix for all the Tyk billing attributes
+	TykBillingPrefix = "tyk.billing."
+)
+
+// Cost attribution related attributes
+const (
+	// represents the organisation the request is charged to
+	TykBillingOrgKey = attribute.Key(TykBillingPrefix + "org")
+
+	// represents the plan the request is charged under
+	TykBillingPlanKey = attribute.Key(TykBillingPrefix + "plan")
+
+	// represents the weight of the request, in billing units
+	TykBillingWeightKey = attribute.Key(TykBillingPrefix + "weight")
+)
+
+// TykBillingOrg returns an attribute KeyValue conforming to the
+// "tyk.billing.org" semantic convention. It represents the organisation
+// the request is charged to.
+func TykBillingOrg(org string) trace.Attribute {
+	return TykBillingOrgKey.String(org)
+}
+
+// TykBillingPlan returns an attribute KeyValue conforming to the
+// "tyk.billing.plan" semantic convention. It represents the plan
+// the request is charged under.
+func TykBillingPlan(plan string) trace.Attribute {
+	return TykBillingPlanKey.String(plan)
+}
+
+// TykBillingWeight returns an attribute KeyValue conforming to the
+// "tyk.billing.weight" semantic convention. It represents the weight
+// of the request, in billing units.
+func TykBillingWeight(weight float64) trace.Attribute {
+	return TykBillingWeightKey.Float64(weight)
+}
+
+/*
+	TykBilling returns all the cost attribution attributes of a request. They can be set on the
+	request span and used as the attributes of the metrics, so both can be aggregated the same way.
+
+Example
+
+	attrs := semconv.TykBilling(orgID, plan, semconv.RequestWeight(1, reqSize, resSize, 1024))
+	span.SetAttributes(attrs...)
+	counter.Add(ctx, 1, metric.WithAttributes(attrs...))
+*/
+func TykBilling(org, plan string, weight float64) []trace.Attribute {
+	return []trace.Attribute{
+		TykBillingOrg(org),
+		TykBillingPlan(plan),
+		TykBillingWeight(weight),
+	}
+}
+
+// RequestWeight computes the weight of a request from its base weight and the size of its payloads:
+// every bytesPerUnit bytes of the request and response bodies add a unit to the base weight.
+// The sizes are ignored if bytesPerUnit isn't positive, and the negative sizes, used for unknown
+// lengths, are ignored too.
+func RequestWeight(base float64, requestSize, responseSize, bytesPerUnit int64) float64 {
+	if bytesPerUnit <= 0 {
+		return base
+	}
+
+	size := max(requestSize, 0) + max(responseSize, 0)
+
+	return base + float64(size)/float64(bytesPerUnit)
+}
@@ -0,0 +1,60 @@
+package semconv
+
+import (
+	"testing"
+
+	"github.com/TykTechnologies/opentelemetry/trace"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestTykBillingOrg(t *testing.T) {
+	expectedAttribute := attribute.Key(TykBillingPrefix + "org").String("org")
+	actualAttribute := TykBillingOrg("org")
+	assert.Equal(t, expectedAttribute, actualAttribute, "The attributes should be equal")
+}
+
+func TestTykBillingPlan(t *testing.T) {
+	expectedAttribute := attribute.Key(TykBillingPrefix + "plan").String("enterprise")
+	actualAttribute := TykBillingPlan("enterprise")
+	assert.Equal(t, expectedAttribute, actualAttribute, "The attributes should be equal")
+}
+
+func TestTykBillingWeight(t *testing.T) {
+	expectedAttribute := attribute.Key(TykBillingPrefix + "weight").Float64(2.5)
+	actualAttribute := TykBillingWeight(2.5)
+	assert.Equal(t, expectedAttribute, actualAttribute, "The attributes should be equal")
+}
+
+func TestTykBilling(t *testing.T) {
+	expectedAttributes := []trace.Attribute{
+		attribute.Key(TykBillingPrefix + "org").String("org"),
+		attribute.Key(TykBillingPrefix + "plan").String("enterprise"),
+		attribute.Key(TykBillingPrefix + "weight").Float64(3),
+	}
+	actualAttributes := TykBilling("org", "enterprise", 3)
+	assert.Equal(t, expectedAttributes, actualAttributes, "The attributes should be equal")
+	assert.Nil(t, Validate(actualAttributes...))
+}
+
+func TestRequestWeight(t *testing.T) {
+	tcs := []struct {
+		name           string
+		base           float64
+		requestSize    int64
+		responseSize   int64
+		bytesPerUnit   int64
+		expectedWeight float64
+	}{
+		{name: "base weight only", base: 1, requestSize: 2048, responseSize: 2048, expectedWeight: 1},
+		{name: "with payloads", base: 1, requestSize: 1024, responseSize: 2048, bytesPerUnit: 1024, expectedWeight: 4},
+		{name: "unknown sizes", base: 2, requestSize: -1, responseSize: 512, bytesPerUnit: 1024, expectedWeight: 2.5},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			weight := RequestWeight(tc.base, tc.requestSize, tc.responseSize, tc.bytesPerUnit)
+			assert.Equal(t, tc.expectedWeight, weight)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package semconv
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/TykTechnologies/opentelemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TykCachePrefix is the base prefix for all the Tyk cache attributes
+	TykCachePrefix = "tyk.cache."
+	// CachePrefix is the base prefix for all the cache operation attributes
+	CachePrefix = "cache."
+)
+
+// Cache related attributes
+const (
+	// represents if the response was served from the cache
+	TykCacheHitKey = attribute.Key(TykCachePrefix + "hit")
+
+	// represents the operation performed on the cache
+	CacheOperationKey = attribute.Key(CachePrefix + "operation")
+
+	// represents the hash of the cache key, the raw key can contain sensitive data
+	CacheKeyHashKey = attribute.Key(CachePrefix + "key.hash")
+)
+
+// Values of the "cache.operation" semantic convention
+const (
+	CacheOperationGet    = "get"
+	CacheOperationSet    = "set"
+	CacheOperationDelete = "delete"
+)
+
+// TykCacheHit returns an attribute KeyValue conforming to the
+// "tyk.cache.hit" semantic convention. It represents if the response
+// was served from the cache.
+func TykCacheHit(hit bool) trace.Attribute {
+	return TykCacheHitKey.Bool(hit)
+}
+
+// CacheOperation returns an attribute KeyValue conforming to the
+// "cache.operation" semantic convention. It represents the operation performed
+// on the cache, one of CacheOperationGet, CacheOperationSet or CacheOperationDelete.
+func CacheOperation(operation string) trace.Attribute {
+	return CacheOperationKey.String(operation)
+}
+
+// CacheKeyHash returns an attribute KeyValue conforming to the
+// "cache.key.hash" semantic convention. It represents the hex encoded SHA-256
+// hash of the cache key, so the keys can be correlated without exposing them.
+func CacheKeyHash(key string) trace.Attribute {
+	hash := sha256.Sum256([]byte(key))
+	return CacheKeyHashKey.String(hex.EncodeToString(hash[:]))
+}
+
+/*
+	NewCacheSpan starts a span named "cache <operation>" with the "cache.operation" and
+	"cache.key.hash" attributes, so all the response cache spans are consistent.
+	The "tyk.cache.hit" attribute can be set once the result of the lookup is known.
+
+Example
+
+	ctx, span := semconv.NewCacheSpan(ctx, tracer, semconv.CacheOperationGet, key)
+	defer span.End()
+
+	_, found := cache.Get(key)
+	span.SetAttributes(semconv.TykCacheHit(found))
+*/
+func NewCacheSpan(ctx context.Context, tracer trace.Tracer, operation, key string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "cache "+operation,
+		oteltrace.WithSpanKind(oteltrace.SpanKindInternal),
+		oteltrace.WithAttributes(CacheOperation(operation), CacheKeyHash(key)),
+	)
+}
@@ -0,0 +1,50 @@
+package semconv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestTykCacheHit(t *testing.T) {
+	expectedAttribute := attribute.Key(TykCachePrefix + "hit").Bool(true)
+	actualAttribute := TykCacheHit(true)
+	assert.Equal(t, expectedAttribute, actualAttribute, "The attributes should be equal")
+}
+
+func TestCacheOperation(t *testing.T) {
+	expectedAttribute := attribute.Key(CachePrefix + "operation").String(CacheOperationGet)
+	actualAttribute := CacheOperation(CacheOperationGet)
+	assert.Equal(t, expectedAttribute, actualAttribute, "The attributes should be equal")
+}
+
+func TestCacheKeyHash(t *testing.T) {
+	expectedAttribute := attribute.Key(CachePrefix + "key.hash").
+		String("2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae")
+	actualAttribute := CacheKeyHash("foo")
+	assert.Equal(t, expectedAttribute, actualAttribute, "The attributes should be equal")
+}
+
+func TestNewCacheSpan(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)).Tracer("test")
+
+	_, span := NewCacheSpan(context.Background(), tracer, CacheOperationGet, "foo")
+	span.SetAttributes(TykCacheHit(false))
+	span.End()
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "cache get", spans[0].Name)
+	assert.Equal(t, oteltrace.SpanKindInternal, spans[0].SpanKind)
+	assert.Equal(t, []attribute.KeyValue{
+		CacheOperation(CacheOperationGet),
+		CacheKeyHash("foo"),
+		TykCacheHit(false),
+	}, spans[0].Attributes)
+}
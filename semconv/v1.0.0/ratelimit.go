@@ -0,0 +1,50 @@
+package semconv
+
+import (
+	"github.com/TykTechnologies/opentelemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// TykRateLimitPrefix is the base prefix for all the Tyk rate limiting attributes
+	TykRateLimitPrefix = "tyk.ratelimit."
+	// TykQuotaPrefix is the base prefix for all the Tyk quota attributes
+	TykQuotaPrefix = "tyk.quota."
+)
+
+// Rate limiting and quota related attributes
+const (
+	// represents if the request was rejected by the rate limiter
+	TykRateLimitLimitedKey = attribute.Key(TykRateLimitPrefix + "limited")
+
+	// represents the number of requests left in the current rate limiting window
+	TykRateLimitRemainingKey = attribute.Key(TykRateLimitPrefix + "remaining")
+
+	// represents if the request was rejected because the quota was exceeded
+	TykQuotaExceededKey = attribute.Key(TykQuotaPrefix + "exceeded")
+)
+
+// TykThrottleEventName is the name of the span event recording the throttling decisions,
+// with the rate limiting and quota attributes.
+const TykThrottleEventName = "tyk.throttle"
+
+// TykRateLimitLimited returns an attribute KeyValue conforming to the
+// "tyk.ratelimit.limited" semantic convention. It represents if the request
+// was rejected by the rate limiter.
+func TykRateLimitLimited(limited bool) trace.Attribute {
+	return TykRateLimitLimitedKey.Bool(limited)
+}
+
+// TykRateLimitRemaining returns an attribute KeyValue conforming to the
+// "tyk.ratelimit.remaining" semantic convention. It represents the number
+// of requests left in the current rate limiting window.
+func TykRateLimitRemaining(remaining int64) trace.Attribute {
+	return TykRateLimitRemainingKey.Int64(remaining)
+}
+
+// TykQuotaExceeded returns an attribute KeyValue conforming to the
+// "tyk.quota.exceeded" semantic convention. It represents if the request
+// was rejected because the quota of the key was exceeded.
+func TykQuotaExceeded(exceeded bool) trace.Attribute {
+	return TykQuotaExceededKey.Bool(exceeded)
+}
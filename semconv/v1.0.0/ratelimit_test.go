@@ -0,0 +1,27 @@
+package semconv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestTykRateLimitLimited(t *testing.T) {
+	expectedAttribute := attribute.Key(TykRateLimitPrefix + "limited").Bool(true)
+	actualAttribute := TykRateLimitLimited(true)
+	assert.Equal(t, expectedAttribute, actualAttribute, "The attributes should be equal")
+}
+
+func TestTykRateLimitRemaining(t *testing.T) {
+	var remaining int64 = 42
+	expectedAttribute := attribute.Key(TykRateLimitPrefix + "remaining").Int64(remaining)
+	actualAttribute := TykRateLimitRemaining(remaining)
+	assert.Equal(t, expectedAttribute, actualAttribute, "The attributes should be equal")
+}
+
+func TestTykQuotaExceeded(t *testing.T) {
+	expectedAttribute := attribute.Key(TykQuotaPrefix + "exceeded").Bool(false)
+	actualAttribute := TykQuotaExceeded(false)
+	assert.Equal(t, expectedAttribute, actualAttribute, "The attributes should be equal")
+}
@@ -0,0 +1,198 @@
+package semconv
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/TykTechnologies/opentelemetry/trace"
+	"go.opentelemetry.io/otel/attribute"
+	otelsemconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+)
+
+// maxTypoDistance is the maximum edit distance between an unknown key and a known one
+// for the unknown key to be reported as a typo.
+const maxTypoDistance = 2
+
+// registry holds the known attribute keys with the type of their values: the Tyk semantic
+// conventions, the attributes set by the trace package and the most common OpenTelemetry ones.
+var registry = map[attribute.Key]attribute.Type{
+	// Tyk APIs
+	TykAPIIDKey:         attribute.STRING,
+	TykAPINameKey:       attribute.STRING,
+	TykAPIOrgIDKey:      attribute.STRING,
+	TykAPITagsKey:       attribute.STRINGSLICE,
+	TykAPIListenPathKey: attribute.STRING,
+	TykAPIVersionKey:    attribute.STRING,
+	TykAPIKeyKey:        attribute.STRING,
+	TykAPIKeyAliasKey:   attribute.STRING,
+	TykOauthIDKey:       attribute.STRING,
+
+	// Tyk Gateway
+	TykGWIDKey:               attribute.STRING,
+	TykGWDataplaneKey:        attribute.BOOL,
+	TykDataplaneGWGroupIDKey: attribute.STRING,
+	TykGWSegmentTagsKey:      attribute.STRINGSLICE,
+
+	// GraphQL
+	GraphQLOperationNameKey: attribute.STRING,
+	GraphQLOperationTypeKey: attribute.STRING,
+	GraphQLDocumentKey:      attribute.STRING,
+
+	// cache
+	TykCacheHitKey:    attribute.BOOL,
+	CacheOperationKey: attribute.STRING,
+	CacheKeyHashKey:   attribute.STRING,
+
+	// rate limiting and quotas
+	TykRateLimitLimitedKey:   attribute.BOOL,
+	TykRateLimitRemainingKey: attribute.INT64,
+	TykQuotaExceededKey:      attribute.BOOL,
+
+	// authentication
+	TykAuthMethodKey:        attribute.STRING,
+	TykAuthOutcomeKey:       attribute.STRING,
+	TykAuthFailureReasonKey: attribute.STRING,
+
+	// billing
+	TykBillingOrgKey:    attribute.STRING,
+	TykBillingPlanKey:   attribute.STRING,
+	TykBillingWeightKey: attribute.FLOAT64,
+
+	// trace package
+	attribute.Key(trace.AttributesTruncatedKey): attribute.BOOL,
+	attribute.Key("http.request.body.size"):     attribute.INT64,
+	attribute.Key("http.response.body.size"):    attribute.INT64,
+	trace.HTTPResendCountKey:                    attribute.INT64,
+	trace.HTTPDNSDurationKey:                    attribute.FLOAT64,
+	trace.HTTPConnectDurationKey:                attribute.FLOAT64,
+	trace.HTTPTLSDurationKey:                    attribute.FLOAT64,
+	trace.HTTPConnectionReusedKey:               attribute.BOOL,
+	trace.HTTPTimeToFirstByteKey:                attribute.FLOAT64,
+	trace.HTTPStreamBytesKey:                    attribute.INT64,
+	trace.HTTPStreamFlushesKey:                  attribute.INT64,
+	trace.BatchIndexKey:                         attribute.INT64,
+	trace.BatchSizeKey:                          attribute.INT64,
+
+	// OpenTelemetry
+	otelsemconv.HTTPMethodKey:                attribute.STRING,
+	otelsemconv.HTTPStatusCodeKey:            attribute.INT64,
+	otelsemconv.HTTPRouteKey:                 attribute.STRING,
+	otelsemconv.HTTPSchemeKey:                attribute.STRING,
+	otelsemconv.HTTPRequestContentLengthKey:  attribute.INT64,
+	otelsemconv.HTTPResponseContentLengthKey: attribute.INT64,
+	otelsemconv.NetPeerNameKey:               attribute.STRING,
+	otelsemconv.NetPeerPortKey:               attribute.INT64,
+	otelsemconv.NetHostNameKey:               attribute.STRING,
+	otelsemconv.NetHostPortKey:               attribute.INT64,
+	otelsemconv.NetProtocolNameKey:           attribute.STRING,
+	otelsemconv.UserAgentOriginalKey:         attribute.STRING,
+	otelsemconv.EnduserIDKey:                 attribute.STRING,
+	otelsemconv.DBSystemKey:                  attribute.STRING,
+	otelsemconv.DBNameKey:                    attribute.STRING,
+	otelsemconv.DBOperationKey:               attribute.STRING,
+	otelsemconv.DBStatementKey:               attribute.STRING,
+	otelsemconv.RPCSystemKey:                 attribute.STRING,
+	otelsemconv.RPCServiceKey:                attribute.STRING,
+	otelsemconv.RPCMethodKey:                 attribute.STRING,
+	otelsemconv.RPCGRPCStatusCodeKey:         attribute.INT64,
+	otelsemconv.ExceptionTypeKey:             attribute.STRING,
+	otelsemconv.ExceptionMessageKey:          attribute.STRING,
+	otelsemconv.ExceptionStacktraceKey:       attribute.STRING,
+	otelsemconv.ServiceNameKey:               attribute.STRING,
+	otelsemconv.ServiceVersionKey:            attribute.STRING,
+}
+
+/*
+	Validate checks the attributes against the known Tyk and OpenTelemetry attributes, so the
+	typos can be caught by the tests of the consuming repositories. It reports:
+	- the empty keys,
+	- the known keys with a value of the wrong type,
+	- the unknown keys close to a known one, which are most likely typos,
+	- the unknown keys in the "tyk." namespace, which is reserved to the Tyk semantic conventions.
+
+	The other unknown keys are accepted as custom attributes. All the problems are returned joined.
+
+Example
+
+	err := semconv.Validate(semconv.TykAPIID("api"), trace.NewAttribute("tyk.api.nmae", "name"))
+	// unknown attribute "tyk.api.nmae", did you mean "tyk.api.name"?
+*/
+func Validate(attrs ...trace.Attribute) error {
+	errs := []error{}
+
+	for _, attr := range attrs {
+		if err := validate(attr); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validate(attr trace.Attribute) error {
+	if attr.Key == "" {
+		return errors.New("empty attribute key")
+	}
+
+	if expectedType, ok := registry[attr.Key]; ok {
+		if attr.Value.Type() != expectedType {
+			return fmt.Errorf("attribute %q must be a %s, got a %s", attr.Key, expectedType, attr.Value.Type())
+		}
+
+		return nil
+	}
+
+	if suggestion, ok := closestKey(attr.Key); ok {
+		return fmt.Errorf("unknown attribute %q, did you mean %q?", attr.Key, suggestion)
+	}
+
+	if strings.HasPrefix(string(attr.Key), "tyk.") {
+		return fmt.Errorf("unknown attribute %q in the reserved tyk namespace", attr.Key)
+	}
+
+	return nil
+}
+
+// closestKey returns the known key closest to the given one, if any is close enough to be a typo.
+func closestKey(key attribute.Key) (attribute.Key, bool) {
+	var closest attribute.Key
+
+	best := maxTypoDistance + 1
+
+	for known := range registry {
+		distance := editDistance(string(key), string(known))
+		if distance < best || (distance == best && known < closest) {
+			closest, best = known, distance
+		}
+	}
+
+	return closest, best <= maxTypoDistance
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
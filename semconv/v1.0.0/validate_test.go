@@ -0,0 +1,85 @@
+package semconv
+
+import (
+	"testing"
+
+	"github.com/TykTechnologies/opentelemetry/trace"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	otelsemconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+)
+
+func TestValidate(t *testing.T) {
+	tcs := []struct {
+		name        string
+		attrs       []trace.Attribute
+		expectedErr string
+	}{
+		{
+			name: "known attributes",
+			attrs: []trace.Attribute{
+				TykAPIID("api"),
+				TykGWDataplane(true),
+				TykAuthOutcome(AuthOutcomeSuccess),
+				otelsemconv.HTTPStatusCode(200),
+			},
+		},
+		{
+			name:  "custom attribute",
+			attrs: []trace.Attribute{attribute.String("customer.segment", "enterprise")},
+		},
+		{
+			name:        "typo",
+			attrs:       []trace.Attribute{attribute.String("tyk.api.nmae", "api")},
+			expectedErr: `unknown attribute "tyk.api.nmae", did you mean "tyk.api.name"?`,
+		},
+		{
+			name:        "typo in an OpenTelemetry attribute",
+			attrs:       []trace.Attribute{attribute.Int("http.status_cod", 200)},
+			expectedErr: `unknown attribute "http.status_cod", did you mean "http.status_code"?`,
+		},
+		{
+			name:        "unknown tyk attribute",
+			attrs:       []trace.Attribute{attribute.String("tyk.plugin.name", "auth")},
+			expectedErr: `unknown attribute "tyk.plugin.name" in the reserved tyk namespace`,
+		},
+		{
+			name:        "wrong type",
+			attrs:       []trace.Attribute{attribute.String("tyk.gw.dataplane", "true")},
+			expectedErr: `attribute "tyk.gw.dataplane" must be a BOOL, got a STRING`,
+		},
+		{
+			name:        "empty key",
+			attrs:       []trace.Attribute{attribute.String("", "value")},
+			expectedErr: "empty attribute key",
+		},
+		{
+			name: "several errors",
+			attrs: []trace.Attribute{
+				attribute.String("tyk.api.nmae", "api"),
+				TykAPIID("api"),
+				attribute.String("", "value"),
+			},
+			expectedErr: "unknown attribute \"tyk.api.nmae\", did you mean \"tyk.api.name\"?\nempty attribute key",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(tc.attrs...)
+			if tc.expectedErr == "" {
+				assert.Nil(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("tyk.api.id", "tyk.api.id"))
+	assert.Equal(t, 2, editDistance("tyk.api.nmae", "tyk.api.name"))
+	assert.Equal(t, 1, editDistance("tyk.api.i", "tyk.api.id"))
+	assert.Equal(t, 3, editDistance("", "abc"))
+}
@@ -0,0 +1,48 @@
+package trace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+/*
+	Go runs fn in a new goroutine with a context that keeps the span context and values of ctx,
+	but is not canceled when ctx is. This prevents losing the trace context in async work,
+	and the async work being canceled when the request that started it finishes.
+
+Example
+
+	trace.Go(r.Context(), func(ctx context.Context) {
+		span := trace.SpanFromContext(ctx)
+		span.AddEvent("async work")
+	})
+*/
+func Go(ctx context.Context, fn func(ctx context.Context)) {
+	ctx = context.WithoutCancel(ctx)
+
+	go fn(ctx)
+}
+
+/*
+	WithSpanAsync works as Go, but runs fn inside a new child span of the span in ctx.
+	The span is ended when fn returns, and if fn returns an error it's recorded in the span.
+	If the tracer name is not provided, the default 'tyk' tracer name will be used.
+
+Example
+
+	trace.WithSpanAsync(r.Context(), "", "send-analytics", func(ctx context.Context) error {
+		return store.Send(ctx, record)
+	})
+*/
+func WithSpanAsync(ctx context.Context, tracerName, spanName string, fn func(ctx context.Context) error) {
+	Go(ctx, func(ctx context.Context) {
+		ctx, span := NewSpanFromContext(ctx, tracerName, spanName)
+		defer span.End()
+
+		if err := fn(ctx); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+	})
+}
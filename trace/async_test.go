@@ -0,0 +1,86 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_Go(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx, span := tp.Tracer("test").Start(ctx, "parent")
+	defer span.End()
+
+	done := make(chan struct{})
+	started := make(chan struct{})
+
+	var asyncSpan Span
+	var asyncErr error
+
+	Go(ctx, func(ctx context.Context) {
+		defer close(done)
+
+		<-started
+		asyncSpan = SpanFromContext(ctx)
+		asyncErr = ctx.Err()
+	})
+
+	// the async work must not be canceled with the parent context
+	cancel()
+	close(started)
+	<-done
+
+	assert.Equal(t, span.SpanContext(), asyncSpan.SpanContext())
+	assert.Nil(t, asyncErr)
+}
+
+func Test_WithSpanAsync(t *testing.T) {
+	tcs := []struct {
+		testName       string
+		fnErr          error
+		expectedStatus codes.Code
+	}{
+		{
+			testName:       "success",
+			expectedStatus: codes.Unset,
+		},
+		{
+			testName:       "error",
+			fnErr:          errors.New("async error"),
+			expectedStatus: codes.Error,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+			parent.End()
+
+			WithSpanAsync(ctx, "", "async", func(ctx context.Context) error {
+				return tc.fnErr
+			})
+
+			// the async span is ended by the goroutine
+			assert.Eventually(t, func() bool {
+				return len(recorder.Ended()) == 2
+			}, time.Second, time.Millisecond)
+
+			async := recorder.Ended()[1]
+			assert.Equal(t, "async", async.Name())
+			assert.Equal(t, parent.SpanContext().SpanID(), async.Parent().SpanID())
+			assert.Equal(t, parent.SpanContext().TraceID(), async.SpanContext().TraceID())
+			assert.Equal(t, tc.expectedStatus, async.Status().Code)
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package trace
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// AttributesTruncatedKey is the attribute added to the spans whose attributes were truncated
+	// or dropped because they exceeded the attribute budget.
+	AttributesTruncatedKey = "tyk.attributes.truncated"
+
+	// maxBudgetTraces is the maximum number of traces tracked for the per-trace budget.
+	// The least recently exported trace is evicted when it's exceeded, to bound the memory used.
+	maxBudgetTraces = 10000
+)
+
+// attributeBudgetSpanLimits returns the span limits of the SDK with the attribute values capped
+// to the span budget, so the spans don't hold values larger than the budget while they're recorded
+// and queued for export. The total size of the attributes can't be capped while they're recorded,
+// since they can be added until the span ends, so it's enforced by the attributeBudgetExporter.
+func attributeBudgetSpanLimits(maxSpanBytes int) sdktrace.SpanLimits {
+	limits := sdktrace.NewSpanLimits()
+
+	if maxSpanBytes > 0 && (limits.AttributeValueLengthLimit < 0 || limits.AttributeValueLengthLimit > maxSpanBytes) {
+		limits.AttributeValueLengthLimit = maxSpanBytes
+	}
+
+	return limits
+}
+
+// attributeBudgetExporter wraps a span exporter and caps the total size in bytes of the span
+// attributes, per span and per trace. The attributes that don't fit in the budget are dropped,
+// string values are truncated when possible, and the AttributesTruncatedKey attribute is added
+// to the affected spans. A zero limit disables the corresponding budget.
+// The per-trace budget is shared by the spans of a trace exported by this process, including the
+// async spans ending after the local root span, since the end of a trace can't be known. The budgets
+// of the least recently exported traces are evicted one at a time once maxTraces traces are tracked.
+type attributeBudgetExporter struct {
+	sdktrace.SpanExporter
+
+	maxSpanBytes  int
+	maxTraceBytes int
+	maxTraces     int
+
+	mu sync.Mutex
+	// traces holds the traceBudget of the tracked traces, the most recently exported first.
+	traces     *list.List
+	traceBytes map[oteltrace.TraceID]*list.Element
+}
+
+// traceBudget is the number of bytes of the trace budget used by the exported spans of a trace.
+type traceBudget struct {
+	traceID oteltrace.TraceID
+	used    int
+}
+
+func newAttributeBudgetExporter(exporter sdktrace.SpanExporter, maxSpanBytes, maxTraceBytes int) *attributeBudgetExporter {
+	return &attributeBudgetExporter{
+		SpanExporter:  exporter,
+		maxSpanBytes:  maxSpanBytes,
+		maxTraceBytes: maxTraceBytes,
+		maxTraces:     maxBudgetTraces,
+		traces:        list.New(),
+		traceBytes:    map[oteltrace.TraceID]*list.Element{},
+	}
+}
+
+func (e *attributeBudgetExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	budgeted := make([]sdktrace.ReadOnlySpan, 0, len(spans))
+
+	e.mu.Lock()
+	for _, span := range spans {
+		budgeted = append(budgeted, e.applyBudget(span))
+	}
+	e.mu.Unlock()
+
+	return e.SpanExporter.ExportSpans(ctx, budgeted)
+}
+
+// applyBudget returns the span with the attributes that fit in the budget. It must be called holding the lock.
+func (e *attributeBudgetExporter) applyBudget(span sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	var trace *traceBudget
+	if e.maxTraceBytes > 0 {
+		trace = e.traceBudget(span.SpanContext().TraceID())
+	}
+
+	budget := -1
+	if e.maxSpanBytes > 0 {
+		budget = e.maxSpanBytes
+	}
+
+	if trace != nil {
+		traceBudget := e.maxTraceBytes - trace.used
+		if traceBudget < 0 {
+			traceBudget = 0
+		}
+
+		if budget < 0 || traceBudget < budget {
+			budget = traceBudget
+		}
+	}
+
+	if budget < 0 {
+		return span
+	}
+
+	attrs, used, truncated := truncateAttributes(span.Attributes(), budget)
+
+	if trace != nil {
+		trace.used += used
+	}
+
+	if !truncated {
+		return span
+	}
+
+	return &budgetedSpan{
+		ReadOnlySpan: span,
+		attributes:   append(attrs, attribute.Bool(AttributesTruncatedKey, true)),
+		dropped:      len(span.Attributes()) - len(attrs),
+	}
+}
+
+// traceBudget returns the budget used by the trace, tracking it as the most recently exported one.
+// It must be called holding the lock.
+func (e *attributeBudgetExporter) traceBudget(traceID oteltrace.TraceID) *traceBudget {
+	if element, ok := e.traceBytes[traceID]; ok {
+		e.traces.MoveToFront(element)
+		return element.Value.(*traceBudget)
+	}
+
+	if e.traces.Len() >= e.maxTraces {
+		oldest := e.traces.Back()
+		e.traces.Remove(oldest)
+		delete(e.traceBytes, oldest.Value.(*traceBudget).traceID)
+	}
+
+	trace := &traceBudget{traceID: traceID}
+	e.traceBytes[traceID] = e.traces.PushFront(trace)
+
+	return trace
+}
+
+// budgetedSpan is a span whose attributes were truncated to fit in the budget.
+type budgetedSpan struct {
+	sdktrace.ReadOnlySpan
+
+	attributes []attribute.KeyValue
+	dropped    int
+}
+
+func (s *budgetedSpan) Attributes() []attribute.KeyValue {
+	return s.attributes
+}
+
+func (s *budgetedSpan) DroppedAttributes() int {
+	return s.ReadOnlySpan.DroppedAttributes() + s.dropped
+}
+
+// truncateAttributes returns the attributes that fit in the budget, in order, and the used bytes.
+// String values are truncated to the remaining budget, while other values are dropped if they don't fit.
+func truncateAttributes(attrs []attribute.KeyValue, budget int) ([]attribute.KeyValue, int, bool) {
+	result := make([]attribute.KeyValue, 0, len(attrs))
+	used := 0
+	truncated := false
+
+	for _, attr := range attrs {
+		size := attributeSize(attr)
+		if used+size <= budget {
+			result = append(result, attr)
+			used += size
+
+			continue
+		}
+
+		truncated = true
+
+		remaining := budget - used - len(attr.Key)
+		if attr.Value.Type() != attribute.STRING || remaining <= 0 {
+			continue
+		}
+
+		value := truncateString(attr.Value.AsString(), remaining)
+		result = append(result, attribute.String(string(attr.Key), value))
+		used += len(attr.Key) + len(value)
+	}
+
+	return result, used, truncated
+}
+
+// truncateString truncates s to at most n bytes, without splitting multi-byte characters.
+func truncateString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n]
+}
+
+// attributeSize returns the approximate size in bytes of the attribute key and value.
+func attributeSize(attr attribute.KeyValue) int {
+	size := len(attr.Key)
+
+	switch attr.Value.Type() {
+	case attribute.BOOL:
+		size++
+	case attribute.INT64, attribute.FLOAT64:
+		size += 8
+	case attribute.STRING:
+		size += len(attr.Value.AsString())
+	case attribute.BOOLSLICE:
+		size += len(attr.Value.AsBoolSlice())
+	case attribute.INT64SLICE:
+		size += 8 * len(attr.Value.AsInt64Slice())
+	case attribute.FLOAT64SLICE:
+		size += 8 * len(attr.Value.AsFloat64Slice())
+	case attribute.STRINGSLICE:
+		for _, v := range attr.Value.AsStringSlice() {
+			size += len(v)
+		}
+	}
+
+	return size
+}
@@ -0,0 +1,241 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	sdktracetest "go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_TruncateAttributes(t *testing.T) {
+	tcs := []struct {
+		testName          string
+		attrs             []attribute.KeyValue
+		budget            int
+		expectedAttrs     []attribute.KeyValue
+		expectedUsed      int
+		expectedTruncated bool
+	}{
+		{
+			testName: "within budget",
+			attrs: []attribute.KeyValue{
+				attribute.String("key", "value"),
+				attribute.Int("int", 1),
+			},
+			budget: 100,
+			expectedAttrs: []attribute.KeyValue{
+				attribute.String("key", "value"),
+				attribute.Int("int", 1),
+			},
+			expectedUsed: 19,
+		},
+		{
+			testName: "string value truncated",
+			attrs: []attribute.KeyValue{
+				attribute.String("key", "value"),
+				attribute.String("body", "large payload"),
+			},
+			budget: 14,
+			expectedAttrs: []attribute.KeyValue{
+				attribute.String("key", "value"),
+				attribute.String("body", "la"),
+			},
+			expectedUsed:      14,
+			expectedTruncated: true,
+		},
+		{
+			testName: "multi-byte characters are not split",
+			attrs: []attribute.KeyValue{
+				attribute.String("k", "añb"),
+			},
+			budget: 3,
+			expectedAttrs: []attribute.KeyValue{
+				attribute.String("k", "a"),
+			},
+			expectedUsed:      2,
+			expectedTruncated: true,
+		},
+		{
+			testName: "non string values are dropped",
+			attrs: []attribute.KeyValue{
+				attribute.IntSlice("ids", []int{1, 2, 3}),
+				attribute.Bool("ok", true),
+			},
+			budget: 10,
+			expectedAttrs: []attribute.KeyValue{
+				attribute.Bool("ok", true),
+			},
+			expectedUsed:      3,
+			expectedTruncated: true,
+		},
+		{
+			testName: "empty budget",
+			attrs: []attribute.KeyValue{
+				attribute.String("key", "value"),
+			},
+			budget:            0,
+			expectedAttrs:     []attribute.KeyValue{},
+			expectedUsed:      0,
+			expectedTruncated: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			attrs, used, truncated := truncateAttributes(tc.attrs, tc.budget)
+
+			assert.Equal(t, tc.expectedAttrs, attrs)
+			assert.Equal(t, tc.expectedUsed, used)
+			assert.Equal(t, tc.expectedTruncated, truncated)
+		})
+	}
+}
+
+func Test_AttributeBudgetExporter(t *testing.T) {
+	t.Run("span budget", func(t *testing.T) {
+		te := &testExporter{}
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(newAttributeBudgetExporter(te, 10, 0)))
+
+		_, span := tp.Tracer("test").Start(context.Background(), "span")
+		span.SetAttributes(attribute.String("key", "value"), attribute.String("body", "large payload"))
+		span.End()
+
+		assert.Len(t, te.spans, 1)
+		assert.Equal(t, []attribute.KeyValue{
+			attribute.String("key", "value"),
+			attribute.Bool(AttributesTruncatedKey, true),
+		}, te.spans[0].Attributes())
+		assert.Equal(t, 1, te.spans[0].DroppedAttributes())
+	})
+
+	t.Run("trace budget", func(t *testing.T) {
+		te := &testExporter{}
+		exporter := newAttributeBudgetExporter(te, 0, 20)
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+		ctx, root := tp.Tracer("test").Start(context.Background(), "root")
+
+		_, first := tp.Tracer("test").Start(ctx, "first")
+		first.SetAttributes(attribute.String("key", "0123456789"))
+		first.End()
+
+		_, second := tp.Tracer("test").Start(ctx, "second")
+		second.SetAttributes(attribute.String("key", "0123456789"))
+		second.End()
+
+		root.SetAttributes(attribute.String("key", "value"))
+		root.End()
+
+		assert.Len(t, te.spans, 3)
+
+		// the first span uses 13 bytes of the trace budget
+		assert.Equal(t, []attribute.KeyValue{attribute.String("key", "0123456789")}, te.spans[0].Attributes())
+		// the second span only has 7 bytes left
+		assert.Equal(t, []attribute.KeyValue{
+			attribute.String("key", "0123"),
+			attribute.Bool(AttributesTruncatedKey, true),
+		}, te.spans[1].Attributes())
+		// the root span has no budget left
+		assert.Equal(t, []attribute.KeyValue{attribute.Bool(AttributesTruncatedKey, true)}, te.spans[2].Attributes())
+
+		// the async spans ending after the root span share the trace budget
+		_, async := tp.Tracer("test").Start(ctx, "async")
+		async.SetAttributes(attribute.String("key", "value"))
+		async.End()
+
+		assert.Len(t, te.spans, 4)
+		assert.Equal(t, []attribute.KeyValue{attribute.Bool(AttributesTruncatedKey, true)}, te.spans[3].Attributes())
+	})
+
+	t.Run("least recently exported traces evicted", func(t *testing.T) {
+		te := &testExporter{}
+		exporter := newAttributeBudgetExporter(te, 0, 20)
+		exporter.maxTraces = 2
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+		traceIDs := []trace.TraceID{}
+		contexts := []context.Context{}
+
+		for i := 0; i < 3; i++ {
+			ctx, span := tp.Tracer("test").Start(context.Background(), "span")
+			span.SetAttributes(attribute.String("key", "0123456789"))
+			span.End()
+
+			traceIDs = append(traceIDs, span.SpanContext().TraceID())
+			contexts = append(contexts, ctx)
+
+			// the first trace is exported again before the third one, so the second one is evicted
+			if i == 1 {
+				_, span := tp.Tracer("test").Start(contexts[0], "span")
+				span.End()
+			}
+		}
+
+		assert.Len(t, exporter.traceBytes, 2)
+		assert.Contains(t, exporter.traceBytes, traceIDs[0])
+		assert.NotContains(t, exporter.traceBytes, traceIDs[1])
+		assert.Contains(t, exporter.traceBytes, traceIDs[2])
+		assert.Equal(t, 13, exporter.traceBytes[traceIDs[0]].Value.(*traceBudget).used)
+	})
+
+	t.Run("within budget", func(t *testing.T) {
+		te := &testExporter{}
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(newAttributeBudgetExporter(te, 100, 100)))
+
+		_, span := tp.Tracer("test").Start(context.Background(), "span")
+		span.SetAttributes(attribute.String("key", "value"))
+		span.End()
+
+		assert.Len(t, te.spans, 1)
+		assert.Equal(t, []attribute.KeyValue{attribute.String("key", "value")}, te.spans[0].Attributes())
+		assert.Equal(t, 0, te.spans[0].DroppedAttributes())
+	})
+}
+
+func Test_AttributeBudgetSpanLimits(t *testing.T) {
+	assert.Equal(t, sdktrace.NewSpanLimits(), attributeBudgetSpanLimits(0))
+	assert.Equal(t, 10, attributeBudgetSpanLimits(10).AttributeValueLengthLimit)
+
+	t.Setenv("OTEL_ATTRIBUTE_VALUE_LENGTH_LIMIT", "5")
+	assert.Equal(t, 5, attributeBudgetSpanLimits(10).AttributeValueLengthLimit, "a lower SDK limit should be kept")
+}
+
+func Test_AttributeBudgetWithProvider(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	recorder := sdktracetest.NewSpanRecorder()
+
+	provider, err := NewProvider(
+		WithConfig(&config.OpenTelemetry{
+			Enabled:           true,
+			Exporter:          "http",
+			Endpoint:          collector.URL,
+			ConnectionTimeout: 1,
+			AttributeBudget:   config.AttributeBudget{MaxSpanBytes: 16},
+		}),
+		WithSpanProcessor(recorder),
+	)
+	assert.Nil(t, err)
+
+	_, span := provider.Tracer().Start(context.Background(), "span")
+	span.SetAttributes(attribute.String("body", strings.Repeat("a", 1024)))
+	span.End()
+
+	// the value is truncated when it's set, before the span is queued for export
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("body", strings.Repeat("a", 16))}, spans[0].Attributes())
+
+	assert.Nil(t, provider.Shutdown(context.Background()))
+}
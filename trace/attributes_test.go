@@ -126,6 +126,14 @@ func TestNewAttribute(t *testing.T) {
 	}
 }
 
+func TestNewJSONAttribute(t *testing.T) {
+	got := NewJSONAttribute("key", map[string]int{"limit": 10}, 100)
+	assert.Equal(t, attribute.Key("key").String(`{"limit":10}`), got)
+
+	got = NewJSONAttribute("key", map[string]int{"limit": 10}, 8)
+	assert.Equal(t, attribute.Key("key").String(`{"lim...`), got)
+}
+
 func ptrStr(s string) *string {
 	return &s
 }
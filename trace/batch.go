@@ -0,0 +1,59 @@
+package trace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// BatchIndexKey is the position of the sub-request in the batch, starting at 0.
+	BatchIndexKey = attribute.Key("tyk.batch.index")
+	// BatchSizeKey is the number of sub-requests of the batch.
+	BatchSizeKey = attribute.Key("tyk.batch.size")
+)
+
+/*
+	NewBatchSubSpan starts the span of a sub-request of a batch, such as the requests of a Tyk batch
+	endpoint or the subrequests of a GraphQL federation query. The span is the root of its own trace,
+	so every sub-request gets a trace of its own size, and it's linked to the batch span found in ctx.
+	The batch span is linked back to the sub-request span, so each one can be reached from the other.
+	The sub-request span is a regular root span if ctx has no batch span.
+
+Example
+
+	for i, req := range batch {
+		subCtx, span := trace.NewBatchSubSpan(ctx, tracer, "batch.subrequest", i, len(batch))
+		handle(subCtx, req)
+		span.End()
+	}
+*/
+func NewBatchSubSpan(ctx context.Context, tracer Tracer, spanName string, index, size int,
+	opts ...trace.SpanStartOption) (context.Context, Span) {
+	batchSpan := trace.SpanFromContext(ctx)
+	batchSpanCtx := batchSpan.SpanContext()
+
+	// the options are copied, so the backing array of the caller isn't modified
+	all := make([]trace.SpanStartOption, 0, len(opts)+3)
+	all = append(all, opts...)
+	all = append(all,
+		trace.WithNewRoot(),
+		trace.WithAttributes(BatchIndexKey.Int(index), BatchSizeKey.Int(size)),
+	)
+
+	if batchSpanCtx.IsValid() {
+		all = append(all, trace.WithLinks(trace.Link{SpanContext: batchSpanCtx}))
+	}
+
+	subCtx, span := tracer.Start(ctx, spanName, all...)
+
+	if batchSpanCtx.IsValid() && span.SpanContext().IsValid() {
+		batchSpan.AddLink(trace.Link{
+			SpanContext: span.SpanContext(),
+			Attributes:  []attribute.KeyValue{BatchIndexKey.Int(index)},
+		})
+	}
+
+	return subCtx, span
+}
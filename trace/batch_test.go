@@ -0,0 +1,78 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestNewBatchSubSpan(t *testing.T) {
+	t.Run("linked to the batch span", func(t *testing.T) {
+		exporter := tracetest.NewInMemoryExporter()
+		tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)).Tracer("test")
+
+		ctx, batchSpan := tracer.Start(context.Background(), "batch")
+
+		for i := 0; i < 2; i++ {
+			_, span := NewBatchSubSpan(ctx, tracer, "subrequest", i, 2)
+
+			assert.NotEqual(t, batchSpan.SpanContext().TraceID(), span.SpanContext().TraceID())
+			span.End()
+		}
+
+		batchSpan.End()
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 3)
+
+		for i, sub := range spans[:2] {
+			assert.Equal(t, "subrequest", sub.Name)
+			assert.False(t, sub.Parent.IsValid())
+			index, ok := attributeValue(sub, BatchIndexKey)
+			assert.True(t, ok)
+			assert.Equal(t, int64(i), index.AsInt64())
+
+			size, ok := attributeValue(sub, BatchSizeKey)
+			assert.True(t, ok)
+			assert.Equal(t, int64(2), size.AsInt64())
+
+			assert.Len(t, sub.Links, 1)
+			assert.Equal(t, batchSpan.SpanContext(), sub.Links[0].SpanContext)
+		}
+
+		batch := spans[2]
+		assert.Len(t, batch.Links, 2)
+		assert.Equal(t, spans[0].SpanContext, batch.Links[0].SpanContext)
+		assert.Equal(t, spans[1].SpanContext, batch.Links[1].SpanContext)
+	})
+
+	t.Run("without batch span", func(t *testing.T) {
+		exporter := tracetest.NewInMemoryExporter()
+		tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)).Tracer("test")
+
+		_, span := NewBatchSubSpan(context.Background(), tracer, "subrequest", 0, 1)
+		span.End()
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 1)
+		assert.Empty(t, spans[0].Links)
+	})
+	t.Run("options of the caller not modified", func(t *testing.T) {
+		tracer := sdktrace.NewTracerProvider().Tracer("test")
+
+		ctx, batchSpan := tracer.Start(context.Background(), "batch")
+		defer batchSpan.End()
+
+		opts := make([]oteltrace.SpanStartOption, 1, 4)
+		opts[0] = oteltrace.WithSpanKind(oteltrace.SpanKindServer)
+
+		_, span := NewBatchSubSpan(ctx, tracer, "subrequest", 0, 1, opts...)
+		span.End()
+
+		assert.Equal(t, []oteltrace.SpanStartOption{nil, nil, nil}, opts[1:cap(opts)])
+	})
+}
@@ -0,0 +1,146 @@
+package trace
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	_ propagation.TextMapCarrier = MetadataCarrier{}
+	_ propagation.TextMapCarrier = MultiMapCarrier{}
+)
+
+// MapCarrier is a propagation.TextMapCarrier that uses a map[string]string as storage.
+// It can be used for transports with single valued headers, such as Kafka record headers.
+type MapCarrier = propagation.MapCarrier
+
+/*
+	MetadataCarrier adapts gRPC metadata to be used as a propagation.TextMapCarrier.
+	Keys are lower-cased, following the gRPC metadata conventions.
+
+Example
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = trace.Extract(ctx, trace.MetadataCarrier(md))
+*/
+type MetadataCarrier metadata.MD
+
+// Get returns the first value associated with the passed key.
+func (c MetadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// Set stores the key-value pair, replacing any existing value.
+func (c MetadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys lists the keys stored in this carrier.
+func (c MetadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+/*
+	MultiMapCarrier is a propagation.TextMapCarrier that uses a map[string][]string as storage.
+	Unlike propagation.HeaderCarrier, keys are not canonicalized, which makes it suitable for
+	case-sensitive headers such as NATS message headers.
+
+Example
+
+	ctx = trace.Extract(ctx, trace.MultiMapCarrier(msg.Header))
+*/
+type MultiMapCarrier map[string][]string
+
+// Get returns the first value associated with the passed key.
+// If there is no exact match, the key is looked up case-insensitively.
+func (c MultiMapCarrier) Get(key string) string {
+	values, ok := c[key]
+	if !ok {
+		for k, v := range c {
+			if strings.EqualFold(k, key) {
+				values = v
+				break
+			}
+		}
+	}
+
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// Set stores the key-value pair, replacing any existing value.
+func (c MultiMapCarrier) Set(key, value string) {
+	c[key] = []string{value}
+}
+
+// Keys lists the keys stored in this carrier.
+func (c MultiMapCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+/*
+	Inject injects the span context and baggage of ctx into the carrier,
+	using the globally registered context propagator.
+
+Example
+
+	headers := trace.MapCarrier{}
+	trace.Inject(ctx, headers)
+*/
+func Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+}
+
+// Extract returns a copy of ctx with the span context and baggage read from the carrier,
+// using the globally registered context propagator.
+func Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
+
+// ExtractGRPCMetadata returns a copy of ctx with the span context read from the incoming gRPC metadata.
+// It's meant to be used by gRPC servers.
+func ExtractGRPCMetadata(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	return Extract(ctx, MetadataCarrier(md))
+}
+
+// InjectGRPCMetadata returns a copy of ctx with the span context added to the outgoing gRPC metadata.
+// It's meant to be used by gRPC clients.
+func InjectGRPCMetadata(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+
+	Inject(ctx, MetadataCarrier(md))
+
+	return metadata.NewOutgoingContext(ctx, md)
+}
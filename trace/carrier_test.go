@@ -0,0 +1,111 @@
+package trace
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+func Test_MetadataCarrier(t *testing.T) {
+	md := metadata.MD{}
+	carrier := MetadataCarrier(md)
+
+	assert.Empty(t, carrier.Get("traceparent"))
+
+	carrier.Set("Traceparent", "value")
+	carrier.Set("tracestate", "state")
+
+	assert.Equal(t, "value", carrier.Get("traceparent"))
+	assert.Equal(t, "value", carrier.Get("TraceParent"))
+	assert.Equal(t, []string{"value"}, md["traceparent"])
+
+	keys := carrier.Keys()
+	sort.Strings(keys)
+	assert.Equal(t, []string{"traceparent", "tracestate"}, keys)
+}
+
+func Test_MultiMapCarrier(t *testing.T) {
+	headers := map[string][]string{}
+	carrier := MultiMapCarrier(headers)
+
+	assert.Empty(t, carrier.Get("traceparent"))
+
+	carrier.Set("traceparent", "value")
+	assert.Equal(t, "value", carrier.Get("traceparent"))
+	assert.Equal(t, "value", carrier.Get("Traceparent"))
+	assert.Equal(t, []string{"value"}, headers["traceparent"])
+
+	headers["X-Multi"] = []string{"first", "second"}
+	assert.Equal(t, "first", carrier.Get("X-Multi"))
+
+	keys := carrier.Keys()
+	sort.Strings(keys)
+	assert.Equal(t, []string{"X-Multi", "traceparent"}, keys)
+}
+
+func Test_InjectExtract(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	tcs := []struct {
+		testName string
+		carrier  propagation.TextMapCarrier
+	}{
+		{testName: "map carrier", carrier: MapCarrier{}},
+		{testName: "multi map carrier", carrier: MultiMapCarrier{}},
+		{testName: "metadata carrier", carrier: MetadataCarrier{}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			Inject(ctx, tc.carrier)
+			assert.NotEmpty(t, tc.carrier.Get("traceparent"))
+
+			extracted := trace.SpanContextFromContext(Extract(context.Background(), tc.carrier))
+			assert.Equal(t, sc.TraceID(), extracted.TraceID())
+			assert.Equal(t, sc.SpanID(), extracted.SpanID())
+			assert.True(t, extracted.IsRemote())
+		})
+	}
+}
+
+func Test_GRPCMetadata(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	ctx = metadata.AppendToOutgoingContext(ctx, "key", "value")
+
+	// client side
+	ctx = InjectGRPCMetadata(ctx)
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"value"}, md.Get("key"))
+	assert.NotEmpty(t, md.Get("traceparent"))
+
+	// server side
+	serverCtx := metadata.NewIncomingContext(context.Background(), md)
+	extracted := trace.SpanContextFromContext(ExtractGRPCMetadata(serverCtx))
+	assert.Equal(t, sc.TraceID(), extracted.TraceID())
+	assert.Equal(t, sc.SpanID(), extracted.SpanID())
+
+	// no incoming metadata
+	assert.False(t, trace.SpanContextFromContext(ExtractGRPCMetadata(context.Background())).IsValid())
+}
@@ -0,0 +1,74 @@
+package trace
+
+import (
+	"context"
+	"time"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Clock is the source of the timestamps used by the spans.
+// It allows deterministic timestamps in tests and high-resolution clocks on platforms with coarse timers.
+type Clock interface {
+	Now() time.Time
+}
+
+// clockTracerProvider wraps a tracer provider so the spans use the timestamps of the given clock.
+// Timestamps explicitly passed with trace.WithTimestamp take precedence over the clock.
+type clockTracerProvider struct {
+	oteltrace.TracerProvider
+	clock Clock
+}
+
+var _ oteltrace.TracerProvider = &clockTracerProvider{}
+
+func newClockTracerProvider(tp oteltrace.TracerProvider, clock Clock) oteltrace.TracerProvider {
+	return &clockTracerProvider{
+		TracerProvider: tp,
+		clock:          clock,
+	}
+}
+
+func (tp *clockTracerProvider) Tracer(name string, opts ...oteltrace.TracerOption) oteltrace.Tracer {
+	return &clockTracer{
+		Tracer:   tp.TracerProvider.Tracer(name, opts...),
+		provider: tp,
+	}
+}
+
+type clockTracer struct {
+	oteltrace.Tracer
+	provider *clockTracerProvider
+}
+
+func (t *clockTracer) Start(ctx context.Context, spanName string, opts ...oteltrace.SpanStartOption) (context.Context, oteltrace.Span) {
+	// the clock timestamp goes first, so it's overridden by any explicit timestamp
+	opts = append([]oteltrace.SpanStartOption{oteltrace.WithTimestamp(t.provider.clock.Now())}, opts...)
+
+	ctx, span := t.Tracer.Start(ctx, spanName, opts...)
+	span = &clockSpan{Span: span, provider: t.provider}
+
+	// store the wrapped span, so the spans ended from the context also use the clock
+	return oteltrace.ContextWithSpan(ctx, span), span
+}
+
+type clockSpan struct {
+	oteltrace.Span
+	provider *clockTracerProvider
+}
+
+func (s *clockSpan) End(opts ...oteltrace.SpanEndOption) {
+	s.Span.End(append([]oteltrace.SpanEndOption{oteltrace.WithTimestamp(s.provider.clock.Now())}, opts...)...)
+}
+
+func (s *clockSpan) AddEvent(name string, opts ...oteltrace.EventOption) {
+	s.Span.AddEvent(name, append([]oteltrace.EventOption{oteltrace.WithTimestamp(s.provider.clock.Now())}, opts...)...)
+}
+
+func (s *clockSpan) RecordError(err error, opts ...oteltrace.EventOption) {
+	s.Span.RecordError(err, append([]oteltrace.EventOption{oteltrace.WithTimestamp(s.provider.clock.Now())}, opts...)...)
+}
+
+func (s *clockSpan) TracerProvider() oteltrace.TracerProvider {
+	return s.provider
+}
@@ -0,0 +1,65 @@
+package trace
+
+import (
+	"context"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// clockOffsetExporter wraps a span exporter and shifts the timestamps of the exported spans by an offset,
+// so gateways with skewed clocks don't produce negative-duration traces in the backend.
+// The offset is read on every export, so it can be updated at runtime, e.g. from the control plane or NTP.
+type clockOffsetExporter struct {
+	sdktrace.SpanExporter
+
+	offset func() time.Duration
+}
+
+func newClockOffsetExporter(exporter sdktrace.SpanExporter, offset func() time.Duration) *clockOffsetExporter {
+	return &clockOffsetExporter{
+		SpanExporter: exporter,
+		offset:       offset,
+	}
+}
+
+func (e *clockOffsetExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	offset := e.offset()
+	if offset == 0 {
+		return e.SpanExporter.ExportSpans(ctx, spans)
+	}
+
+	shifted := make([]sdktrace.ReadOnlySpan, 0, len(spans))
+	for _, span := range spans {
+		shifted = append(shifted, &offsetSpan{ReadOnlySpan: span, offset: offset})
+	}
+
+	return e.SpanExporter.ExportSpans(ctx, shifted)
+}
+
+// offsetSpan is a span whose timestamps are shifted by an offset.
+type offsetSpan struct {
+	sdktrace.ReadOnlySpan
+
+	offset time.Duration
+}
+
+func (s *offsetSpan) StartTime() time.Time {
+	return s.ReadOnlySpan.StartTime().Add(s.offset)
+}
+
+func (s *offsetSpan) EndTime() time.Time {
+	return s.ReadOnlySpan.EndTime().Add(s.offset)
+}
+
+func (s *offsetSpan) Events() []sdktrace.Event {
+	events := s.ReadOnlySpan.Events()
+
+	shifted := make([]sdktrace.Event, 0, len(events))
+	for _, event := range events {
+		event.Time = event.Time.Add(s.offset)
+		shifted = append(shifted, event)
+	}
+
+	return shifted
+}
@@ -0,0 +1,51 @@
+package trace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func Test_ClockOffsetExporter(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tcs := []struct {
+		testName string
+		offset   time.Duration
+	}{
+		{
+			testName: "no offset",
+			offset:   0,
+		},
+		{
+			testName: "positive offset",
+			offset:   time.Minute,
+		},
+		{
+			testName: "negative offset",
+			offset:   -time.Minute,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			te := &testExporter{}
+			exporter := newClockOffsetExporter(te, func() time.Duration { return tc.offset })
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+			_, span := tp.Tracer("test").Start(context.Background(), "span", oteltrace.WithTimestamp(start))
+			span.AddEvent("event", oteltrace.WithTimestamp(start.Add(time.Second)))
+			span.End(oteltrace.WithTimestamp(start.Add(2 * time.Second)))
+
+			assert.Len(t, te.spans, 1)
+			assert.Equal(t, start.Add(tc.offset), te.spans[0].StartTime())
+			assert.Equal(t, start.Add(2*time.Second+tc.offset), te.spans[0].EndTime())
+			assert.Equal(t, start.Add(time.Second+tc.offset), te.spans[0].Events()[0].Time)
+			assert.Equal(t, "event", te.spans[0].Events()[0].Name)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// testClock is a manual clock that only moves forward when advanced.
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func Test_ClockTracerProvider(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &testClock{now: start}
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := newClockTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), clock)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+
+	clock.advance(time.Second)
+	SpanFromContext(ctx).AddEvent("event")
+	SpanFromContext(ctx).RecordError(errors.New("error"))
+
+	// child spans created from the context use the clock too
+	_, child := NewSpanFromContext(ctx, "", "child")
+	clock.advance(time.Second)
+	child.End()
+
+	clock.advance(time.Second)
+	// ending the span from the context must use the clock
+	SpanFromContext(ctx).End()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, start.Add(time.Second), spans[0].StartTime())
+	assert.Equal(t, start.Add(2*time.Second), spans[0].EndTime())
+
+	assert.Equal(t, "parent", spans[1].Name())
+	assert.Equal(t, start, spans[1].StartTime())
+	assert.Equal(t, start.Add(3*time.Second), spans[1].EndTime())
+	assert.Len(t, spans[1].Events(), 2)
+	assert.Equal(t, start.Add(time.Second), spans[1].Events()[0].Time)
+	assert.Equal(t, start.Add(time.Second), spans[1].Events()[1].Time)
+
+	assert.Equal(t, parent.SpanContext(), spans[1].SpanContext())
+}
+
+func Test_ClockTracerProviderExplicitTimestamp(t *testing.T) {
+	clock := &testClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	explicit := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := newClockTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), clock)
+
+	_, span := tp.Tracer("test").Start(context.Background(), "span", oteltrace.WithTimestamp(explicit))
+	span.End(oteltrace.WithTimestamp(explicit.Add(time.Minute)))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, explicit, spans[0].StartTime())
+	assert.Equal(t, explicit.Add(time.Minute), spans[0].EndTime())
+}
+
+func Test_ProviderWithClock(t *testing.T) {
+	clock := &testClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	provider, err := NewProvider(WithContext(context.Background()), WithClock(clock), WithConfig(&config.OpenTelemetry{
+		Enabled:  true,
+		Exporter: config.STDOUTEXPORTER,
+	}))
+	assert.Nil(t, err)
+
+	assert.IsType(t, &clockTracerProvider{}, provider.TracerProvider())
+	assert.Nil(t, provider.Shutdown(context.Background()))
+}
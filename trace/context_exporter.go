@@ -0,0 +1,36 @@
+package trace
+
+import (
+	"context"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// contextExporter wraps a span exporter and cancels its exports when the root context of the
+// provider is done, so a cancelled application doesn't wait for the exports in flight.
+type contextExporter struct {
+	sdktrace.SpanExporter
+
+	root context.Context
+}
+
+func newContextExporter(exporter sdktrace.SpanExporter, root context.Context) *contextExporter {
+	return &contextExporter{
+		SpanExporter: exporter,
+		root:         root,
+	}
+}
+
+func (e *contextExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if err := e.root.Err(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := context.AfterFunc(e.root, cancel)
+	defer stop()
+
+	return e.SpanExporter.ExportSpans(ctx, spans)
+}
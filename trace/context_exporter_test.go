@@ -0,0 +1,174 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// blockingExporter blocks the exports until their context is done.
+type blockingExporter struct {
+	testExporter
+	started chan struct{}
+}
+
+func (b *blockingExporter) ExportSpans(ctx context.Context, _ []sdktrace.ReadOnlySpan) error {
+	close(b.started)
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+func Test_ContextExporter(t *testing.T) {
+	spans := tracetest.SpanStubs{{Name: "span"}}.Snapshots()
+
+	t.Run("exports in flight are cancelled with the root context", func(t *testing.T) {
+		root, cancel := context.WithCancel(context.Background())
+		blocking := &blockingExporter{started: make(chan struct{})}
+		exporter := newContextExporter(blocking, root)
+
+		exported := make(chan error)
+		go func() {
+			exported <- exporter.ExportSpans(context.Background(), spans)
+		}()
+
+		<-blocking.started
+		cancel()
+
+		select {
+		case err := <-exported:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(time.Second):
+			t.Fatal("the export wasn't cancelled")
+		}
+	})
+
+	t.Run("exports fail once the root context is done", func(t *testing.T) {
+		root, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		fe := &failingExporter{}
+		exporter := newContextExporter(fe, root)
+
+		assert.ErrorIs(t, exporter.ExportSpans(context.Background(), spans), context.Canceled)
+		assert.Equal(t, 0, fe.calls)
+	})
+
+	t.Run("exports go through while the root context is active", func(t *testing.T) {
+		fe := &failingExporter{}
+		exporter := newContextExporter(fe, context.Background())
+
+		assert.Nil(t, exporter.ExportSpans(context.Background(), spans))
+		assert.Len(t, fe.spans, 1)
+	})
+}
+
+func Test_ShutdownOnContextDone(t *testing.T) {
+	t.Run("provider is shut down when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		provider, err := NewProvider(
+			WithContext(ctx),
+			WithConfig(&config.OpenTelemetry{Enabled: false}),
+			WithShutdownOnContextDone(),
+		)
+		assert.Nil(t, err)
+
+		lifecycle := provider.(ProviderLifecycle)
+
+		hookCalled := make(chan struct{})
+		lifecycle.OnShutdown(func(context.Context) error {
+			close(hookCalled)
+			return nil
+		})
+
+		assert.False(t, lifecycle.Closed())
+
+		cancel()
+
+		select {
+		case <-hookCalled:
+		case <-time.After(time.Second):
+			t.Fatal("the provider wasn't shut down")
+		}
+
+		assert.Eventually(t, lifecycle.Closed, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("provider isn't shut down without the option", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		provider, err := NewProvider(WithContext(ctx), WithConfig(&config.OpenTelemetry{Enabled: false}))
+		assert.Nil(t, err)
+
+		cancel()
+		time.Sleep(20 * time.Millisecond)
+
+		assert.False(t, provider.(ProviderLifecycle).Closed())
+	})
+}
+
+func Test_ShutdownAfterContextDone(t *testing.T) {
+	newServer := func(exported *atomic.Int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			exported.Add(1)
+			w.WriteHeader(http.StatusOK)
+		}))
+	}
+
+	t.Run("spans flushed by Shutdown after the context is done", func(t *testing.T) {
+		exported := &atomic.Int32{}
+		server := newServer(exported)
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		provider, err := NewProvider(WithContext(ctx), WithConfig(&config.OpenTelemetry{
+			Enabled:           true,
+			Exporter:          "http",
+			Endpoint:          server.URL,
+			ConnectionTimeout: 1,
+		}))
+		assert.Nil(t, err)
+
+		_, span := provider.Tracer().Start(context.Background(), "span")
+		span.End()
+
+		cancel()
+
+		assert.Nil(t, provider.Shutdown(context.Background()))
+		assert.Equal(t, int32(1), exported.Load())
+	})
+
+	t.Run("exports cancelled with WithShutdownOnContextDone", func(t *testing.T) {
+		exported := &atomic.Int32{}
+		server := newServer(exported)
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		provider, err := NewProvider(WithContext(ctx), WithShutdownOnContextDone(), WithConfig(&config.OpenTelemetry{
+			Enabled:           true,
+			Exporter:          "http",
+			Endpoint:          server.URL,
+			ConnectionTimeout: 1,
+		}))
+		assert.Nil(t, err)
+
+		_, span := provider.Tracer().Start(context.Background(), "span")
+		span.End()
+
+		cancel()
+
+		assert.Eventually(t, provider.(ProviderLifecycle).Closed, time.Second, 5*time.Millisecond)
+		assert.Equal(t, int32(0), exported.Load())
+	})
+}
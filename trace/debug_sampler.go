@@ -0,0 +1,98 @@
+package trace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// DebugTraceHeader is the header used by callers to force the sampling of a request.
+	DebugTraceHeader = "X-Tyk-Debug-Trace"
+	// DebugBaggageKey is the baggage entry used by callers to force the sampling of a request.
+	DebugBaggageKey = "tyk.debug"
+
+	// debugTraceStateKey and debugTraceStateValue are the tracestate entry used to propagate
+	// the debug decision downstream.
+	debugTraceStateKey   = "tyk"
+	debugTraceStateValue = "debug"
+)
+
+type debugContextKey struct{}
+
+// debugSampler wraps a sampler and always records and samples the spans marked as debug,
+// regardless of the decision of the wrapped sampler.
+type debugSampler struct {
+	sampler sdktrace.Sampler
+}
+
+var _ sdktrace.Sampler = &debugSampler{}
+
+func newDebugSampler(sampler sdktrace.Sampler) sdktrace.Sampler {
+	return &debugSampler{sampler: sampler}
+}
+
+func (s *debugSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	parentState := oteltrace.SpanContextFromContext(p.ParentContext).TraceState()
+
+	if !isDebugContext(p.ParentContext) {
+		return s.sampler.ShouldSample(p)
+	}
+
+	// the tracestate entry flows to the child spans and downstream services
+	state, err := parentState.Insert(debugTraceStateKey, debugTraceStateValue)
+	if err != nil {
+		state = parentState
+	}
+
+	return sdktrace.SamplingResult{
+		Decision:   sdktrace.RecordAndSample,
+		Tracestate: state,
+	}
+}
+
+func (s *debugSampler) Description() string {
+	return "DebugSampler{" + s.sampler.Description() + "}"
+}
+
+// isDebugContext checks if the context was marked as debug by the header, the baggage or the tracestate.
+func isDebugContext(ctx context.Context) bool {
+	if debug, ok := ctx.Value(debugContextKey{}).(bool); ok && debug {
+		return true
+	}
+
+	if baggage.FromContext(ctx).Member(DebugBaggageKey).Value() == "1" {
+		return true
+	}
+
+	return oteltrace.SpanContextFromContext(ctx).TraceState().Get(debugTraceStateKey) == debugTraceStateValue
+}
+
+// debugPropagator extracts the debug header into the context, and injects it
+// for the spans marked as debug. This way the debug decision is also propagated
+// when using propagators that don't support tracestate, such as b3.
+type debugPropagator struct{}
+
+var _ propagation.TextMapPropagator = debugPropagator{}
+
+func (debugPropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := oteltrace.SpanContextFromContext(ctx)
+	if sc.IsValid() && sc.TraceState().Get(debugTraceStateKey) == debugTraceStateValue {
+		carrier.Set(DebugTraceHeader, "1")
+	}
+}
+
+func (debugPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	if carrier.Get(DebugTraceHeader) == "1" {
+		return context.WithValue(ctx, debugContextKey{}, true)
+	}
+
+	return ctx
+}
+
+func (debugPropagator) Fields() []string {
+	return []string{DebugTraceHeader}
+}
@@ -0,0 +1,210 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	sdktracetest "go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func Test_DebugSampler(t *testing.T) {
+	debugState, err := oteltrace.TraceState{}.Insert(debugTraceStateKey, debugTraceStateValue)
+	assert.NoError(t, err)
+
+	debugBaggage, err := baggage.NewMemberRaw(DebugBaggageKey, "1")
+	assert.NoError(t, err)
+	bag, err := baggage.New(debugBaggage)
+	assert.NoError(t, err)
+
+	tcs := []struct {
+		testName         string
+		ctx              context.Context
+		expectedDecision sdktrace.SamplingDecision
+	}{
+		{
+			testName:         "no debug marker",
+			ctx:              context.Background(),
+			expectedDecision: sdktrace.Drop,
+		},
+		{
+			testName:         "debug header",
+			ctx:              context.WithValue(context.Background(), debugContextKey{}, true),
+			expectedDecision: sdktrace.RecordAndSample,
+		},
+		{
+			testName:         "debug baggage",
+			ctx:              baggage.ContextWithBaggage(context.Background(), bag),
+			expectedDecision: sdktrace.RecordAndSample,
+		},
+		{
+			testName: "debug tracestate",
+			ctx: oteltrace.ContextWithRemoteSpanContext(context.Background(), oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+				TraceID:    oteltrace.TraceID{0x01},
+				SpanID:     oteltrace.SpanID{0x01},
+				TraceState: debugState,
+			})),
+			expectedDecision: sdktrace.RecordAndSample,
+		},
+		{
+			testName: "not sampled parent without debug marker",
+			ctx: oteltrace.ContextWithRemoteSpanContext(context.Background(), oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+				TraceID: oteltrace.TraceID{0x01},
+				SpanID:  oteltrace.SpanID{0x01},
+			})),
+			expectedDecision: sdktrace.Drop,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			sampler := newDebugSampler(sdktrace.ParentBased(sdktrace.NeverSample()))
+
+			result := sampler.ShouldSample(sdktrace.SamplingParameters{
+				ParentContext: tc.ctx,
+				TraceID:       oteltrace.TraceID{0x01},
+			})
+
+			assert.Equal(t, tc.expectedDecision, result.Decision)
+
+			if tc.expectedDecision == sdktrace.RecordAndSample {
+				assert.Equal(t, debugTraceStateValue, result.Tracestate.Get(debugTraceStateKey))
+			}
+		})
+	}
+}
+
+func Test_DebugSamplerDescription(t *testing.T) {
+	sampler := newDebugSampler(sdktrace.NeverSample())
+
+	assert.Equal(t, "DebugSampler{AlwaysOffSampler}", sampler.Description())
+}
+
+func Test_DebugPropagator(t *testing.T) {
+	prop := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, debugPropagator{})
+
+	t.Run("extract debug header", func(t *testing.T) {
+		header := http.Header{}
+		header.Set(DebugTraceHeader, "1")
+
+		ctx := prop.Extract(context.Background(), propagation.HeaderCarrier(header))
+		assert.True(t, isDebugContext(ctx))
+	})
+
+	t.Run("extract without debug header", func(t *testing.T) {
+		header := http.Header{}
+		header.Set(DebugTraceHeader, "0")
+
+		ctx := prop.Extract(context.Background(), propagation.HeaderCarrier(header))
+		assert.False(t, isDebugContext(ctx))
+	})
+
+	t.Run("inject debug span", func(t *testing.T) {
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(newDebugSampler(sdktrace.NeverSample())))
+		defer provider.Shutdown(context.Background())
+
+		ctx := context.WithValue(context.Background(), debugContextKey{}, true)
+		ctx, span := provider.Tracer("test").Start(ctx, "debug")
+		defer span.End()
+
+		assert.True(t, span.SpanContext().IsSampled())
+
+		header := http.Header{}
+		prop.Inject(ctx, propagation.HeaderCarrier(header))
+
+		assert.Equal(t, "1", header.Get(DebugTraceHeader))
+		assert.Contains(t, header.Get("tracestate"), "tyk=debug")
+	})
+
+	t.Run("inject non debug span", func(t *testing.T) {
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(newDebugSampler(sdktrace.AlwaysSample())))
+		defer provider.Shutdown(context.Background())
+
+		ctx, span := provider.Tracer("test").Start(context.Background(), "regular")
+		defer span.End()
+
+		header := http.Header{}
+		prop.Inject(ctx, propagation.HeaderCarrier(header))
+
+		assert.Empty(t, header.Get(DebugTraceHeader))
+		assert.NotEmpty(t, header.Get("traceparent"))
+	})
+}
+
+// handledRequestSpans sends a request with the given headers through a handler created with NewHTTPHandler,
+// using a provider with the given sampling config, and returns the spans it sampled.
+func handledRequestSpans(t *testing.T, sampling config.Sampling, header http.Header) []sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	recorder := sdktracetest.NewSpanRecorder()
+
+	provider, err := NewProvider(
+		WithConfig(&config.OpenTelemetry{
+			Enabled:           true,
+			Exporter:          "http",
+			Endpoint:          collector.URL,
+			ConnectionTimeout: 1,
+			Sampling:          sampling,
+		}),
+		WithSpanProcessor(recorder),
+	)
+	assert.Nil(t, err)
+
+	defer provider.Shutdown(context.Background())
+
+	server := httptest.NewServer(NewHTTPHandler("test", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), provider))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	assert.Nil(t, err)
+
+	req.Header = header
+
+	res, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	assert.Nil(t, res.Body.Close())
+
+	return recorder.Ended()
+}
+
+func Test_DebugSamplingThroughHTTPHandler(t *testing.T) {
+	// the sampler drops every span, as a ratio of 0 would
+	sampling := config.Sampling{Type: config.ALWAYSOFF, Debug: true}
+
+	t.Run("debug baggage", func(t *testing.T) {
+		spans := handledRequestSpans(t, sampling, http.Header{"Baggage": []string{DebugBaggageKey + "=1"}})
+
+		assert.Len(t, spans, 1)
+
+		if len(spans) == 1 {
+			assert.True(t, spans[0].SpanContext().IsSampled())
+			assert.Equal(t, debugTraceStateValue, spans[0].SpanContext().TraceState().Get(debugTraceStateKey))
+		}
+	})
+
+	t.Run("debug header", func(t *testing.T) {
+		spans := handledRequestSpans(t, sampling, http.Header{DebugTraceHeader: []string{"1"}})
+
+		assert.Len(t, spans, 1)
+	})
+
+	t.Run("no debug marker", func(t *testing.T) {
+		spans := handledRequestSpans(t, sampling, http.Header{"Baggage": []string{DebugBaggageKey + "=0"}})
+
+		assert.Empty(t, spans)
+	})
+}
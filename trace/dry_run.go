@@ -0,0 +1,92 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+)
+
+// DryRunReport is the result of the validation performed by a provider created with WithDryRun.
+type DryRunReport struct {
+	// Pipelines holds the validation of every exporter, starting with the main one
+	// and followed by the additional pipelines in the same order they were configured.
+	Pipelines []PipelineReport
+	// Sampler is the description of the sampler that would be used.
+	Sampler string
+	// Propagator is the context propagation that would be used.
+	Propagator string
+	// Errors holds all the validation errors, including the ones of the pipelines.
+	Errors []error
+}
+
+// PipelineReport is the validation of an exporter.
+type PipelineReport struct {
+	Exporter string
+	Endpoint string
+	TLS      bool
+	// Err is the validation error of the exporter, if any.
+	Err error
+}
+
+// Err returns all the validation errors joined, or nil if the configuration is valid.
+func (r *DryRunReport) Err() error {
+	return errors.Join(r.Errors...)
+}
+
+// dryRun validates the configuration of the provider without opening connections nor setting the
+// globals, filling the report. It returns the validation errors joined.
+func (tp *traceProvider) dryRun(ctx context.Context, report *DryRunReport) error {
+	*report = DryRunReport{
+		Pipelines:  []PipelineReport{},
+		Errors:     []error{},
+		Propagator: tp.cfg.ContextPropagation,
+	}
+
+	addErr := func(err error) {
+		report.Errors = append(report.Errors, err)
+	}
+
+	if _, err := resourceFactory(ctx, tp.cfg.ResourceName, tp.resources); err != nil {
+		addErr(fmt.Errorf("failed to create resource: %w", err))
+	}
+
+	if len(tp.cfg.SpanNameReplacements) > 0 {
+		if _, err := newSpanNameProcessor(tp.cfg.SpanNameReplacements); err != nil {
+			addErr(fmt.Errorf("failed to create span name processor: %w", err))
+		}
+	}
+
+	pipelineCfgs := []*config.OpenTelemetry{tp.cfg}
+	for _, pipeline := range tp.cfg.Pipelines {
+		pipelineCfgs = append(pipelineCfgs, pipelineConfig(tp.cfg, pipeline))
+	}
+
+	for i, pipelineCfg := range pipelineCfgs {
+		pipelineReport := PipelineReport{
+			Exporter: pipelineCfg.Exporter,
+			Endpoint: pipelineCfg.Redacted().Endpoint,
+			TLS:      pipelineCfg.TLS.Enabled,
+			Err:      errors.Join(validateExporter(ctx, pipelineCfg, tp.grpcDialer), validateSpanProcessor(pipelineCfg)),
+		}
+
+		if pipelineReport.Err != nil {
+			if i == 0 {
+				addErr(fmt.Errorf("failed to create exporter: %w", pipelineReport.Err))
+			} else {
+				addErr(fmt.Errorf("failed to create exporter: pipeline %d: %w", i-1, pipelineReport.Err))
+			}
+		}
+
+		report.Pipelines = append(report.Pipelines, pipelineReport)
+	}
+
+	report.Sampler = samplerFactory(tp.cfg).Description()
+
+	if _, err := propagatorFactory(tp.cfg); err != nil {
+		addErr(fmt.Errorf("failed to create context propagator: %w", err))
+	}
+
+	return report.Err()
+}
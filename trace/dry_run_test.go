@@ -0,0 +1,122 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+)
+
+func Test_DryRun(t *testing.T) {
+	t.Run("valid config", func(t *testing.T) {
+		globalProvider := otel.GetTracerProvider()
+		report := &DryRunReport{}
+
+		provider, err := NewProvider(WithConfig(&config.OpenTelemetry{
+			Enabled:  true,
+			Exporter: config.GRPCEXPORTER,
+			// nothing listens there, the dry run must not connect
+			Endpoint: "localhost:1",
+			Pipelines: []config.Pipeline{
+				{Exporter: config.STDOUTEXPORTER},
+			},
+		}), WithDryRun(report))
+
+		assert.Nil(t, err)
+		assert.False(t, provider.Enabled())
+		assert.Equal(t, globalProvider, otel.GetTracerProvider())
+
+		assert.Empty(t, report.Errors)
+		assert.Equal(t, []PipelineReport{
+			{Exporter: config.GRPCEXPORTER, Endpoint: "localhost:1"},
+			{Exporter: config.STDOUTEXPORTER},
+		}, report.Pipelines)
+		assert.Equal(t, config.PROPAGATOR_TRACECONTEXT, report.Propagator)
+		assert.Equal(t, "AlwaysOnSampler", report.Sampler)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		report := &DryRunReport{}
+
+		_, err := NewProvider(WithConfig(&config.OpenTelemetry{
+			Enabled:            true,
+			Exporter:           config.HTTPEXPORTER,
+			Endpoint:           "localhost:4318",
+			ContextPropagation: "invalid",
+			TLS: config.TLS{
+				Enabled: true,
+				CAFile:  "missing-ca.pem",
+			},
+			Pipelines: []config.Pipeline{
+				{Exporter: config.GRPCEXPORTER, Endpoint: "http://:4317"},
+			},
+		}), WithDryRun(report))
+
+		assert.NotNil(t, err)
+		assert.Len(t, report.Errors, 3)
+		assert.Equal(t, report.Err().Error(), err.Error())
+
+		assert.Len(t, report.Pipelines, 2)
+		assert.ErrorContains(t, report.Pipelines[0].Err, "missing-ca.pem")
+		assert.True(t, report.Pipelines[0].TLS)
+		assert.EqualError(t, report.Pipelines[1].Err, "invalid endpoint: missing host in http://:4317")
+
+		assert.ErrorContains(t, err, "failed to create exporter: pipeline 0: invalid endpoint")
+		assert.ErrorContains(t, err, "failed to create context propagator: invalid context propagation type: invalid")
+	})
+
+	t.Run("invalid batch config", func(t *testing.T) {
+		report := &DryRunReport{}
+
+		_, err := NewProvider(WithConfig(&config.OpenTelemetry{
+			Enabled:  true,
+			Exporter: config.STDOUTEXPORTER,
+			Batch:    config.Batch{Size: 4096},
+		}), WithDryRun(report))
+
+		assert.EqualError(t, err, "failed to create exporter: invalid batch config: size 4096 exceeds the queue size 2048")
+		assert.EqualError(t, report.Pipelines[0].Err, "invalid batch config: size 4096 exceeds the queue size 2048")
+	})
+
+	t.Run("unregistered span processor", func(t *testing.T) {
+		report := &DryRunReport{}
+
+		_, err := NewProvider(WithConfig(&config.OpenTelemetry{
+			Enabled:  true,
+			Exporter: config.STDOUTEXPORTER,
+			Pipelines: []config.Pipeline{
+				{Exporter: config.STDOUTEXPORTER, SpanProcessorType: "mpsc"},
+			},
+		}), WithDryRun(report))
+
+		assert.EqualError(t, err, "failed to create exporter: pipeline 0: invalid span processor type: mpsc")
+		assert.Nil(t, report.Pipelines[0].Err)
+		assert.EqualError(t, report.Pipelines[1].Err, "invalid span processor type: mpsc")
+	})
+}
+
+func Test_ValidateEndpoint(t *testing.T) {
+	tcs := []struct {
+		endpoint    string
+		expectedErr string
+	}{
+		{endpoint: "localhost:4317"},
+		{endpoint: "https://collector.example.com/v1/traces"},
+		{endpoint: "", expectedErr: "missing endpoint"},
+		{endpoint: "http://:4317", expectedErr: "invalid endpoint: missing host in http://:4317"},
+		{endpoint: "http://local host", expectedErr: "invalid endpoint: parse \"http://local host\": invalid character \" \" in host name"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.endpoint, func(t *testing.T) {
+			err := validateEndpoint(tc.endpoint)
+			if tc.expectedErr == "" {
+				assert.Nil(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package trace
+
+import (
+	"context"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// enrichProcessor is a span processor that calls a function with every started span.
+type enrichProcessor struct {
+	fn func(ctx context.Context, span sdktrace.ReadWriteSpan)
+}
+
+var _ sdktrace.SpanProcessor = &enrichProcessor{}
+
+/*
+	EnrichOnStart returns a span processor that calls fn with every started span and the context
+	it was started with, so attributes can be set at span start, e.g. from context values,
+	without writing a full processor type. fn is called synchronously by the span starts,
+	so it must be fast and safe for concurrent use.
+
+Example
+
+	processor := trace.EnrichOnStart(func(ctx context.Context, span sdktrace.ReadWriteSpan) {
+		if orgID, ok := ctx.Value(orgIDKey{}).(string); ok {
+			span.SetAttributes(semconv.TykAPIOrgID(orgID))
+		}
+	})
+
+	provider, err := trace.NewProvider(trace.WithSpanProcessor(processor))
+	if err != nil {
+		panic(err)
+	}
+*/
+func EnrichOnStart(fn func(ctx context.Context, span sdktrace.ReadWriteSpan)) sdktrace.SpanProcessor {
+	return &enrichProcessor{fn: fn}
+}
+
+func (p *enrichProcessor) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	p.fn(ctx, s)
+}
+
+func (p *enrichProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (p *enrichProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+func (p *enrichProcessor) ForceFlush(context.Context) error {
+	return nil
+}
@@ -0,0 +1,69 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type orgIDKey struct{}
+
+func TestEnrichOnStart(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+
+	processor := EnrichOnStart(func(ctx context.Context, span sdktrace.ReadWriteSpan) {
+		if orgID, ok := ctx.Value(orgIDKey{}).(string); ok {
+			span.SetAttributes(attribute.String("tyk.api.orgid", orgID))
+		}
+	})
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithSyncer(exporter),
+	)
+
+	ctx := context.WithValue(context.Background(), orgIDKey{}, "org-1")
+	_, span := tracerProvider.Tracer("test").Start(ctx, "span")
+	span.End()
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes, attribute.String("tyk.api.orgid", "org-1"))
+	assert.Nil(t, processor.ForceFlush(context.Background()))
+	assert.Nil(t, processor.Shutdown(context.Background()))
+}
+
+func TestEnrichOnStartWithProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var started []string
+
+	provider, err := NewProvider(
+		WithConfig(&config.OpenTelemetry{
+			Enabled:           true,
+			Exporter:          "http",
+			Endpoint:          server.URL,
+			ConnectionTimeout: 1,
+		}),
+		WithSpanProcessor(EnrichOnStart(func(_ context.Context, span sdktrace.ReadWriteSpan) {
+			started = append(started, span.Name())
+		})),
+	)
+	assert.Nil(t, err)
+
+	_, span := provider.Tracer().Start(context.Background(), "span")
+	span.End()
+
+	assert.Equal(t, []string{"span"}, started)
+	assert.Nil(t, provider.Shutdown(context.Background()))
+}
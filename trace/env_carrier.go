@@ -0,0 +1,111 @@
+package trace
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+var _ propagation.TextMapCarrier = EnvCarrier{}
+
+// EnvCarrier is a propagation.TextMapCarrier that uses environment variables as storage,
+// to propagate the span context to subprocesses such as plugin runners. Following the
+// environment variables convention, keys are upper-cased and any character other than
+// letters, digits and underscores is replaced by an underscore: "traceparent" is stored as "TRACEPARENT".
+type EnvCarrier map[string]string
+
+// EnvCarrierFromEnviron creates an EnvCarrier from a list of "KEY=value" entries, like the ones returned by os.Environ.
+func EnvCarrierFromEnviron(environ []string) EnvCarrier {
+	carrier := EnvCarrier{}
+
+	for _, entry := range environ {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			continue
+		}
+
+		carrier[key] = value
+	}
+
+	return carrier
+}
+
+// Get returns the value of the environment variable for the passed key.
+func (c EnvCarrier) Get(key string) string {
+	return c[envKey(key)]
+}
+
+// Set stores the key-value pair as an environment variable.
+func (c EnvCarrier) Set(key, value string) {
+	c[envKey(key)] = value
+}
+
+// Keys lists the environment variables stored in this carrier.
+func (c EnvCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// Environ returns the carrier content as a sorted list of "KEY=value" entries, like the ones used by exec.Cmd.Env.
+func (c EnvCarrier) Environ() []string {
+	environ := make([]string, 0, len(c))
+	for k, v := range c {
+		environ = append(environ, k+"="+v)
+	}
+
+	sort.Strings(environ)
+
+	return environ
+}
+
+/*
+	InjectEnv returns a copy of environ with the span context of ctx added as environment variables
+	(e.g. TRACEPARENT and TRACESTATE), replacing any existing value for them.
+
+Example
+
+	cmd := exec.CommandContext(ctx, "python", "plugin.py")
+	cmd.Env = trace.InjectEnv(ctx, os.Environ())
+*/
+func InjectEnv(ctx context.Context, environ []string) []string {
+	carrier := EnvCarrier{}
+	Inject(ctx, carrier)
+
+	result := make([]string, 0, len(environ)+len(carrier))
+	for _, entry := range environ {
+		key, _, _ := strings.Cut(entry, "=")
+		if _, ok := carrier[key]; ok {
+			continue
+		}
+
+		result = append(result, entry)
+	}
+
+	return append(result, carrier.Environ()...)
+}
+
+// ExtractEnv returns a copy of ctx with the span context read from the environment variables of the
+// current process. It's meant to be used by subprocesses started with InjectEnv.
+func ExtractEnv(ctx context.Context) context.Context {
+	return Extract(ctx, EnvCarrierFromEnviron(os.Environ()))
+}
+
+func envKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
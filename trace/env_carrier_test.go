@@ -0,0 +1,62 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_EnvCarrier(t *testing.T) {
+	carrier := EnvCarrierFromEnviron([]string{"PATH=/usr/bin", "EMPTY=", "INVALID", "KEY=a=b"})
+
+	assert.Equal(t, "/usr/bin", carrier.Get("path"))
+	assert.Equal(t, "", carrier.Get("EMPTY"))
+	assert.Equal(t, "a=b", carrier.Get("KEY"))
+
+	carrier.Set("traceparent", "value")
+	carrier.Set("x-b3-traceid", "id")
+
+	assert.Equal(t, "value", carrier["TRACEPARENT"])
+	assert.Equal(t, "id", carrier["X_B3_TRACEID"])
+	assert.Equal(t, "id", carrier.Get("X-B3-TraceId"))
+	assert.Len(t, carrier.Keys(), 5)
+
+	assert.Equal(t, []string{"EMPTY=", "KEY=a=b", "PATH=/usr/bin", "TRACEPARENT=value", "X_B3_TRACEID=id"}, carrier.Environ())
+}
+
+func Test_InjectExtractEnv(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	state, err := trace.ParseTraceState("vendor=value")
+	assert.NoError(t, err)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+		TraceState: state,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	environ := InjectEnv(ctx, []string{"PATH=/usr/bin", "TRACEPARENT=stale"})
+
+	carrier := EnvCarrierFromEnviron(environ)
+	assert.Equal(t, "/usr/bin", carrier["PATH"])
+	assert.Equal(t, "00-01000000000000000000000000000000-0200000000000000-01", carrier["TRACEPARENT"])
+	assert.Equal(t, "vendor=value", carrier["TRACESTATE"])
+	assert.Len(t, environ, 3)
+
+	// simulate the subprocess environment
+	t.Setenv("TRACEPARENT", carrier["TRACEPARENT"])
+	t.Setenv("TRACESTATE", carrier["TRACESTATE"])
+
+	extracted := trace.SpanContextFromContext(ExtractEnv(context.Background()))
+	assert.Equal(t, sc.TraceID(), extracted.TraceID())
+	assert.Equal(t, sc.SpanID(), extracted.SpanID())
+	assert.Equal(t, "value", extracted.TraceState().Get("vendor"))
+	assert.True(t, extracted.IsRemote())
+}
@@ -2,6 +2,8 @@ package trace
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"go.opentelemetry.io/otel/codes"
 )
@@ -12,22 +14,148 @@ const (
 	SPAN_STATUS_OK    = codes.Ok
 )
 
+const (
+	// defaultErrorLogInterval is the interval during which the repeated errors are suppressed from the logs.
+	defaultErrorLogInterval = 5 * time.Minute
+	// maxTrackedErrors bounds the memory used to deduplicate the errors, since the messages
+	// can contain variable data.
+	maxTrackedErrors = 100
+)
+
+// errHandler logs the errors reported by the OpenTelemetry SDK, such as the failed exports.
+// The first occurrence of an error is logged, and the repeated occurrences within the interval
+// are suppressed and logged as a summary, to avoid flooding the logs during collector outages.
 type errHandler struct {
 	logger Logger
+	// interval during which the repeated errors are suppressed. It defaults to
+	// defaultErrorLogInterval, and a negative value logs every error.
+	interval time.Duration
+
+	mu     sync.Mutex
+	errors map[string]*errorOccurrences
+}
+
+type errorOccurrences struct {
+	since      time.Time
+	suppressed int
 }
 
 func (eh *errHandler) Handle(err error) {
-	if eh.logger != nil && err != nil {
+	if eh.logger == nil || err == nil {
+		return
+	}
+
+	interval := eh.interval
+	if interval == 0 {
+		interval = defaultErrorLogInterval
+	}
+
+	if interval < 0 {
 		eh.logger.Error(fmt.Sprintf("error: %v", err.Error()))
+		return
+	}
+
+	for _, line := range eh.track(err.Error(), interval, time.Now()) {
+		eh.logger.Error(line)
+	}
+}
+
+// track records an occurrence of the error, returning the lines to log.
+func (eh *errHandler) track(msg string, interval time.Duration, now time.Time) []string {
+	eh.mu.Lock()
+	defer eh.mu.Unlock()
+
+	occurrences, ok := eh.errors[msg]
+
+	switch {
+	case !ok:
+		var lines []string
+
+		if eh.errors == nil || len(eh.errors) >= maxTrackedErrors {
+			lines = eh.summaries(interval)
+			eh.errors = map[string]*errorOccurrences{}
+		}
+
+		eh.errors[msg] = &errorOccurrences{since: now}
+
+		return append(lines, fmt.Sprintf("error: %v", msg))
+	case now.Sub(occurrences.since) < interval:
+		occurrences.suppressed++
+
+		return nil
+	default:
+		line := fmt.Sprintf("error: %v", msg)
+		if occurrences.suppressed > 0 {
+			line = fmt.Sprintf("error: %v (%d similar errors suppressed in the last %s)", msg, occurrences.suppressed, interval)
+		}
+
+		occurrences.since = now
+		occurrences.suppressed = 0
+
+		return []string{line}
+	}
+}
+
+// summaries returns the summaries of the suppressed errors. It must be called with the lock held.
+func (eh *errHandler) summaries(interval time.Duration) []string {
+	lines := []string{}
+
+	for msg, occurrences := range eh.errors {
+		if occurrences.suppressed > 0 {
+			lines = append(lines, fmt.Sprintf("error: %v (%d similar errors suppressed in the last %s)",
+				msg, occurrences.suppressed, interval))
+		}
+	}
+
+	return lines
+}
+
+// flush logs the summaries of the suppressed errors, so they're not lost when the provider shuts down.
+func (eh *errHandler) flush() {
+	if eh.logger == nil {
+		return
+	}
+
+	interval := eh.interval
+	if interval == 0 {
+		interval = defaultErrorLogInterval
+	}
+
+	eh.mu.Lock()
+
+	lines := eh.summaries(interval)
+	eh.errors = nil
+
+	eh.mu.Unlock()
+
+	for _, line := range lines {
+		eh.logger.Error(line)
 	}
 }
 
-// Logger represents the internal library logger used for error and info messages
+// Logger represents the internal library logger used for error and info messages.
+// The loggers also having a Warn(args ...interface{}) method, like logrus, receive the warnings
+// at that level, e.g. the deprecated config fields in use. The other loggers receive them as info.
 type Logger interface {
 	Info(args ...interface{})
 	Error(args ...interface{})
 }
 
+// warnLogger is implemented by the loggers supporting the warning level.
+type warnLogger interface {
+	Warn(args ...interface{})
+}
+
+// logWarn logs the warning at the warn level if the logger supports it, or at the info level otherwise.
+func logWarn(logger Logger, args ...interface{}) {
+	if l, ok := logger.(warnLogger); ok {
+		l.Warn(args...)
+		return
+	}
+
+	logger.Info(args...)
+}
+
 type noopLogger struct{}
 
 func (n *noopLogger) Error(args ...interface{}) {}
@@ -1,8 +1,10 @@
 package trace
 
 import (
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -44,3 +46,83 @@ func TestErrHandler_Handle(t *testing.T) {
 		})
 	}
 }
+
+func TestErrHandler_Suppression(t *testing.T) {
+	t.Run("repeated errors are suppressed within the interval", func(t *testing.T) {
+		logger := &recordingLogger{}
+		eh := &errHandler{logger: logger, interval: time.Hour}
+
+		for i := 0; i < 5; i++ {
+			eh.Handle(errors.New("export failed"))
+		}
+
+		eh.Handle(errors.New("another error"))
+
+		assert.Equal(t, []string{"error: export failed", "error: another error"}, logger.errors)
+
+		eh.flush()
+
+		assert.Equal(t, []string{
+			"error: export failed",
+			"error: another error",
+			"error: export failed (4 similar errors suppressed in the last 1h0m0s)",
+		}, logger.errors)
+
+		// the suppressed errors are only reported once
+		eh.flush()
+		assert.Len(t, logger.errors, 3)
+	})
+
+	t.Run("summary is logged with the next error after the interval", func(t *testing.T) {
+		eh := &errHandler{}
+		now := time.Now()
+
+		assert.Equal(t, []string{"error: export failed"}, eh.track("export failed", time.Minute, now))
+		assert.Empty(t, eh.track("export failed", time.Minute, now.Add(time.Second)))
+		assert.Empty(t, eh.track("export failed", time.Minute, now.Add(2*time.Second)))
+
+		assert.Equal(t, []string{"error: export failed (2 similar errors suppressed in the last 1m0s)"},
+			eh.track("export failed", time.Minute, now.Add(time.Minute)))
+		assert.Equal(t, []string{"error: export failed"},
+			eh.track("export failed", time.Minute, now.Add(3*time.Minute)))
+	})
+
+	t.Run("negative interval logs every error", func(t *testing.T) {
+		logger := &recordingLogger{}
+		eh := &errHandler{logger: logger, interval: -1}
+
+		for i := 0; i < 3; i++ {
+			eh.Handle(errors.New("export failed"))
+		}
+
+		assert.Len(t, logger.errors, 3)
+	})
+
+	t.Run("tracked errors are bounded", func(t *testing.T) {
+		eh := &errHandler{}
+		now := time.Now()
+
+		for i := 0; i < maxTrackedErrors; i++ {
+			eh.track(fmt.Sprintf("error %d", i), time.Minute, now)
+		}
+
+		eh.track("error 0", time.Minute, now)
+
+		lines := eh.track("new error", time.Minute, now)
+
+		assert.Equal(t, []string{
+			"error: error 0 (1 similar errors suppressed in the last 1m0s)",
+			"error: new error",
+		}, lines)
+		assert.Len(t, eh.errors, 1)
+	})
+}
+
+func Test_LogWarn(t *testing.T) {
+	// the loggers without a warn level receive the warnings as info
+	logger := &recordingLogger{}
+	logWarn(logger, "config field is deprecated")
+
+	assert.Equal(t, []string{"config field is deprecated"}, logger.infos)
+	assert.Empty(t, logger.errors)
+}
@@ -0,0 +1,98 @@
+package trace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// EventsDroppedEventName is the name of the event summarising the events dropped by the event limit.
+	EventsDroppedEventName = "tyk.events.dropped"
+	// EventsDroppedCountKey is the number of events dropped by the event limit.
+	EventsDroppedCountKey = attribute.Key("tyk.events.dropped_count")
+	// EventsDroppedNamesKey holds the distinct names of the events dropped by the event limit.
+	EventsDroppedNamesKey = attribute.Key("tyk.events.dropped_names")
+
+	// maxDroppedEventNames bounds the names listed in the summary event.
+	maxDroppedEventNames = 10
+)
+
+// eventLimitExporter wraps a span exporter and caps the number of events of the spans.
+// The first maxEvents events are kept, and the dropped ones are summarised by a final event.
+type eventLimitExporter struct {
+	sdktrace.SpanExporter
+
+	maxEvents int
+}
+
+func newEventLimitExporter(exporter sdktrace.SpanExporter, maxEvents int) *eventLimitExporter {
+	return &eventLimitExporter{
+		SpanExporter: exporter,
+		maxEvents:    maxEvents,
+	}
+}
+
+func (e *eventLimitExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	limited := make([]sdktrace.ReadOnlySpan, 0, len(spans))
+
+	for _, span := range spans {
+		limited = append(limited, e.applyLimit(span))
+	}
+
+	return e.SpanExporter.ExportSpans(ctx, limited)
+}
+
+// applyLimit returns the span with the events that fit in the limit and the summary of the dropped ones.
+func (e *eventLimitExporter) applyLimit(span sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	events := span.Events()
+	if len(events) <= e.maxEvents {
+		return span
+	}
+
+	dropped := events[e.maxEvents:]
+	names := []string{}
+	seen := map[string]bool{}
+
+	for _, event := range dropped {
+		if !seen[event.Name] && len(names) < maxDroppedEventNames {
+			seen[event.Name] = true
+			names = append(names, event.Name)
+		}
+	}
+
+	kept := make([]sdktrace.Event, e.maxEvents, e.maxEvents+1)
+	copy(kept, events)
+
+	kept = append(kept, sdktrace.Event{
+		Name: EventsDroppedEventName,
+		Attributes: []attribute.KeyValue{
+			EventsDroppedCountKey.Int(len(dropped)),
+			EventsDroppedNamesKey.StringSlice(names),
+		},
+		Time: dropped[len(dropped)-1].Time,
+	})
+
+	return &limitedEventsSpan{
+		ReadOnlySpan: span,
+		events:       kept,
+		dropped:      len(dropped),
+	}
+}
+
+// limitedEventsSpan is a span whose events were capped by the event limit.
+type limitedEventsSpan struct {
+	sdktrace.ReadOnlySpan
+
+	events  []sdktrace.Event
+	dropped int
+}
+
+func (s *limitedEventsSpan) Events() []sdktrace.Event {
+	return s.events
+}
+
+func (s *limitedEventsSpan) DroppedEvents() int {
+	return s.ReadOnlySpan.DroppedEvents() + s.dropped
+}
@@ -0,0 +1,57 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_EventLimitExporter(t *testing.T) {
+	now := time.Now()
+
+	events := []sdktrace.Event{}
+	for i := 0; i < 5; i++ {
+		events = append(events, sdktrace.Event{
+			Name: fmt.Sprintf("event-%d", i%3),
+			Time: now.Add(time.Duration(i) * time.Second),
+		})
+	}
+
+	spans := tracetest.SpanStubs{
+		{Name: "within limit", Events: events[:2]},
+		{Name: "over limit", Events: events, DroppedEvents: 1},
+	}.Snapshots()
+
+	te := &testExporter{}
+	exporter := newEventLimitExporter(te, 2)
+
+	assert.Nil(t, exporter.ExportSpans(context.Background(), spans))
+	assert.Len(t, te.spans, 2)
+
+	assert.Equal(t, spans[0], te.spans[0])
+
+	limited := te.spans[1]
+	assert.Equal(t, "over limit", limited.Name())
+	assert.Equal(t, 4, limited.DroppedEvents())
+	assert.Equal(t, []sdktrace.Event{
+		events[0],
+		events[1],
+		{
+			Name: EventsDroppedEventName,
+			Attributes: []attribute.KeyValue{
+				EventsDroppedCountKey.Int(3),
+				EventsDroppedNamesKey.StringSlice([]string{"event-2", "event-0", "event-1"}),
+			},
+			Time: events[4].Time,
+		},
+	}, limited.Events())
+
+	// the events of the original span are left untouched
+	assert.Len(t, spans[1].Events(), 5)
+}
@@ -0,0 +1,121 @@
+package trace
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// EventType is the type of a state change of the provider.
+type EventType string
+
+const (
+	// ExporterHealthy is emitted when an export succeeds for the first time or after failures.
+	ExporterHealthy EventType = "exporter_healthy"
+	// ExporterUnhealthy is emitted when an export fails for the first time or after successes.
+	ExporterUnhealthy EventType = "exporter_unhealthy"
+	// ProviderShutdown is emitted once the provider has been shut down.
+	ProviderShutdown EventType = "provider_shutdown"
+	// ConfigReloaded is emitted by the new provider once a SwappableProvider has swapped to it.
+	ConfigReloaded EventType = "config_reloaded"
+)
+
+// Event is a state change of the provider.
+type Event struct {
+	Type EventType
+	// Pipeline is the index of the exporter for the exporter events: 0 is the main exporter
+	// and the next ones are the additional pipelines in the same order they were configured.
+	Pipeline int
+	// Err is the error of the ExporterUnhealthy and ProviderShutdown events, if any.
+	Err  error
+	Time time.Time
+}
+
+// EventListener receives the events of the provider. It's called synchronously from the
+// exporters, so it must return quickly and be safe for concurrent use.
+type EventListener func(Event)
+
+// eventBus dispatches the events to the registered listeners.
+type eventBus struct {
+	mu        sync.RWMutex
+	listeners []EventListener
+}
+
+func (b *eventBus) subscribe(listener EventListener) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.listeners = append(b.listeners, listener)
+}
+
+func (b *eventBus) publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	// the listeners are called without the lock, so they can subscribe or publish events themselves
+	b.mu.RLock()
+	listeners := make([]EventListener, len(b.listeners))
+	copy(listeners, b.listeners)
+	b.mu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+// eventPublisher is implemented by the providers emitting events.
+type eventPublisher interface {
+	publish(Event)
+}
+
+// healthExporter wraps a span exporter and emits an event every time its health changes.
+type healthExporter struct {
+	sdktrace.SpanExporter
+
+	pipeline int
+	events   *eventBus
+
+	mu      sync.Mutex
+	healthy *bool
+}
+
+func newHealthExporter(exporter sdktrace.SpanExporter, pipeline int, events *eventBus) *healthExporter {
+	return &healthExporter{
+		SpanExporter: exporter,
+		pipeline:     pipeline,
+		events:       events,
+	}
+}
+
+func (e *healthExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	healthy := err == nil
+
+	e.mu.Lock()
+
+	changed := e.healthy == nil || *e.healthy != healthy
+	e.healthy = &healthy
+
+	e.mu.Unlock()
+
+	if !changed {
+		return err
+	}
+
+	event := Event{Type: ExporterHealthy, Pipeline: e.pipeline}
+	if !healthy {
+		event.Type = ExporterUnhealthy
+		event.Err = err
+	}
+
+	e.events.publish(event)
+
+	return err
+}
@@ -0,0 +1,112 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type recordingListener struct {
+	events []Event
+}
+
+func (l *recordingListener) listen(event Event) {
+	l.events = append(l.events, event)
+}
+
+func (l *recordingListener) types() []EventType {
+	types := []EventType{}
+	for _, event := range l.events {
+		types = append(types, event.Type)
+	}
+
+	return types
+}
+
+func Test_HealthExporter(t *testing.T) {
+	ctx := context.Background()
+	spans := tracetest.SpanStubs{{Name: "span"}}.Snapshots()
+
+	listener := &recordingListener{}
+	events := &eventBus{}
+	events.subscribe(listener.listen)
+
+	fe := &failingExporter{}
+	exporter := newHealthExporter(fe, 1, events)
+
+	// the first export reports the initial health
+	assert.Nil(t, exporter.ExportSpans(ctx, spans))
+	assert.Nil(t, exporter.ExportSpans(ctx, spans))
+	assert.Equal(t, []EventType{ExporterHealthy}, listener.types())
+
+	// only the changes are reported
+	fe.err = errors.New("collector unavailable")
+
+	assert.Equal(t, fe.err, exporter.ExportSpans(ctx, spans))
+	assert.Equal(t, fe.err, exporter.ExportSpans(ctx, spans))
+	assert.Equal(t, []EventType{ExporterHealthy, ExporterUnhealthy}, listener.types())
+	assert.Equal(t, fe.err, listener.events[1].Err)
+	assert.Equal(t, 1, listener.events[1].Pipeline)
+	assert.False(t, listener.events[1].Time.IsZero())
+
+	fe.err = nil
+
+	assert.Nil(t, exporter.ExportSpans(ctx, spans))
+	assert.Equal(t, []EventType{ExporterHealthy, ExporterUnhealthy, ExporterHealthy}, listener.types())
+}
+
+func Test_EventBus(t *testing.T) {
+	t.Run("listeners called without the lock", func(t *testing.T) {
+		events := &eventBus{}
+		listener := &recordingListener{}
+
+		// subscribing while holding the read lock would deadlock
+		events.subscribe(func(event Event) {
+			if event.Type == ExporterUnhealthy {
+				events.subscribe(listener.listen)
+			}
+		})
+
+		events.publish(Event{Type: ExporterUnhealthy})
+		events.publish(Event{Type: ExporterHealthy})
+
+		assert.Equal(t, []EventType{ExporterHealthy}, listener.types())
+	})
+}
+
+func Test_ProviderEvents(t *testing.T) {
+	t.Run("shutdown", func(t *testing.T) {
+		listener := &recordingListener{}
+		provider, _ := newInMemoryProvider()
+		WithEventListener(listener.listen).apply(provider)
+
+		assert.Nil(t, provider.Shutdown(context.Background()))
+		assert.Equal(t, []EventType{ProviderShutdown}, listener.types())
+		assert.Nil(t, listener.events[0].Err)
+	})
+
+	t.Run("config reloaded", func(t *testing.T) {
+		oldListener, newListener := &recordingListener{}, &recordingListener{}
+
+		oldProvider, _ := newInMemoryProvider()
+		WithEventListener(oldListener.listen).apply(oldProvider)
+
+		newProvider, _ := newInMemoryProvider()
+		WithEventListener(newListener.listen).apply(newProvider)
+
+		swappable := NewSwappableProvider(oldProvider)
+
+		assert.Nil(t, swappable.Swap(context.Background(), newProvider))
+		assert.Equal(t, []EventType{ProviderShutdown}, oldListener.types())
+		assert.Equal(t, []EventType{ConfigReloaded}, newListener.types())
+	})
+
+	t.Run("no listeners", func(t *testing.T) {
+		provider, _ := newInMemoryProvider()
+
+		assert.Nil(t, provider.Shutdown(context.Background()))
+	})
+}
@@ -14,26 +14,34 @@ import (
 	"time"
 
 	"github.com/TykTechnologies/opentelemetry/config"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 
+	"golang.org/x/net/proxy"
+
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/metric"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"software.sslmate.com/src/go-pkcs12"
 )
 
-func exporterFactory(ctx context.Context, cfg *config.OpenTelemetry) (sdktrace.SpanExporter, error) {
-	var client otlptrace.Client
-	var err error
-	switch cfg.Exporter {
-	case config.GRPCEXPORTER:
-		client, err = newGRPCClient(ctx, cfg)
-	case config.HTTPEXPORTER:
-		client, err = newHTTPClient(ctx, cfg)
-	default:
-		err = fmt.Errorf("invalid exporter type: %s", cfg.Exporter)
+// Dialer opens the connections of the "grpc" exporters to the collector, for example
+// through a SOCKS5 proxy or a bastion tunnel. The address is the configured endpoint.
+type Dialer func(ctx context.Context, address string) (net.Conn, error)
+
+func exporterFactory(ctx context.Context, cfg *config.OpenTelemetry, dialer Dialer,
+	retries metric.Int64Counter) (sdktrace.SpanExporter, error) {
+	if cfg.Exporter == config.STDOUTEXPORTER {
+		// The stdout exporter does not use an OTLP client, it's mostly used for debugging
+		return stdouttrace.New()
 	}
 
+	client, err := newClient(ctx, cfg, dialer, retries)
 	if err != nil {
 		return nil, err
 	}
@@ -44,14 +52,129 @@ func exporterFactory(ctx context.Context, cfg *config.OpenTelemetry) (sdktrace.S
 	return otlptrace.New(ctx, client)
 }
 
-func newGRPCClient(ctx context.Context, cfg *config.OpenTelemetry) (otlptrace.Client, error) {
+// newClient creates the OTLP client of the configured exporter, without connecting it.
+// It returns a nil client for the stdout exporter. The retries of the JSON client are counted with
+// the given counter, if set, the other clients retry within the OpenTelemetry SDK.
+func newClient(ctx context.Context, cfg *config.OpenTelemetry, dialer Dialer,
+	retries metric.Int64Counter) (otlptrace.Client, error) {
+	switch cfg.Exporter {
+	case config.GRPCEXPORTER:
+		return newGRPCClient(ctx, cfg, dialer)
+	case config.HTTPEXPORTER:
+		if cfg.HTTPEncoding == config.JSONENCODING {
+			return newHTTPJSONClient(cfg, retries)
+		}
+
+		return newHTTPClient(ctx, cfg)
+	case config.STDOUTEXPORTER:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("invalid exporter type: %s", cfg.Exporter)
+	}
+}
+
+// warmUpExporter performs a test export of an empty resource-only payload,
+// to verify the connection to the collector. It's a noop for the stdout exporter.
+func warmUpExporter(ctx context.Context, cfg *config.OpenTelemetry, dialer Dialer) error {
+	client, err := newClient(ctx, cfg, dialer, nil)
+	if err != nil || client == nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(cfg.ConnectionTimeout)*time.Second)
+	defer cancel()
+
+	if err := client.Start(ctx); err != nil {
+		return err
+	}
+
+	uploadErr := client.UploadTraces(ctx, []*tracepb.ResourceSpans{{Resource: &resourcepb.Resource{}}})
+	stopErr := client.Stop(ctx)
+
+	return errors.Join(uploadErr, stopErr)
+}
+
+// validateExporter checks the exporter configuration, loading the TLS files and
+// parsing the endpoint, without opening any connection.
+func validateExporter(ctx context.Context, cfg *config.OpenTelemetry, dialer Dialer) error {
+	if cfg.Exporter != config.STDOUTEXPORTER {
+		if err := validateEndpoint(cfg.Endpoint); err != nil {
+			return err
+		}
+	}
+
+	_, err := newClient(ctx, cfg, dialer, nil)
+
+	return err
+}
+
+// validateEndpoint checks the endpoint has a host, with or without a scheme.
+func validateEndpoint(endpoint string) error {
+	if endpoint == "" {
+		return errors.New("missing endpoint")
+	}
+
+	rawURL := endpoint
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint: %w", err)
+	}
+
+	if u.Hostname() == "" {
+		return fmt.Errorf("invalid endpoint: missing host in %s", endpoint)
+	}
+
+	return nil
+}
+
+func newGRPCClient(ctx context.Context, cfg *config.OpenTelemetry, dialer Dialer) (otlptrace.Client, error) {
+	target, dialOptions, err := grpcDialOptions(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	clientOptions := []otlptracegrpc.Option{
-		otlptracegrpc.WithEndpoint(cfg.Endpoint),
+		otlptracegrpc.WithEndpoint(target),
 		otlptracegrpc.WithTimeout(time.Duration(cfg.ConnectionTimeout) * time.Second),
 		otlptracegrpc.WithHeaders(cfg.Headers),
+		otlptracegrpc.WithDialOption(append(dialOptions, grpc.WithUserAgent(userAgent(cfg)))...),
+	}
+
+	// the custom dialer takes precedence over the SOCKS5 proxy
+	if dialer == nil && cfg.SOCKS5Proxy != "" {
+		dialer, err = socks5Dialer(cfg.SOCKS5Proxy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if dialer != nil {
+		clientOptions = append(clientOptions, otlptracegrpc.WithDialOption(grpc.WithContextDialer(dialer)))
+	}
+
+	gzipCompression, err := isGzipCompression(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if gzipCompression {
+		clientOptions = append(clientOptions, otlptracegrpc.WithCompressor(config.GZIPCOMPRESSION))
+	}
+
+	if policy := newRetryPolicy(&cfg.Retry); policy.enabled {
+		clientOptions = append(clientOptions, otlptracegrpc.WithRetry(otlptracegrpc.RetryConfig{
+			Enabled:         true,
+			InitialInterval: policy.initialInterval,
+			MaxInterval:     policy.maxInterval,
+			MaxElapsedTime:  policy.maxElapsedTime,
+		}))
 	}
 
-	isTLSDisabled := !cfg.TLS.Enable
+	isTLSDisabled := !cfg.TLS.Enabled
 
 	if isTLSDisabled {
 		clientOptions = append(clientOptions, otlptracegrpc.WithInsecure())
@@ -66,6 +189,43 @@ func newGRPCClient(ctx context.Context, cfg *config.OpenTelemetry) (otlptrace.Cl
 	return otlptracegrpc.NewClient(clientOptions...), nil
 }
 
+// socks5Dialer returns a dialer connecting through the given SOCKS5 proxy.
+func socks5Dialer(proxyURL string) (Dialer, error) {
+	if !strings.Contains(proxyURL, "://") {
+		proxyURL = "socks5://" + proxyURL
+	}
+
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SOCKS5 proxy: %w", err)
+	}
+
+	if u.Scheme != "socks5" && u.Scheme != "socks5h" {
+		return nil, fmt.Errorf("invalid SOCKS5 proxy scheme: %s", u.Scheme)
+	}
+
+	var auth *proxy.Auth
+	if u.User != nil {
+		password, _ := u.User.Password()
+		auth = &proxy.Auth{User: u.User.Username(), Password: password}
+	}
+
+	socks5, err := proxy.SOCKS5("tcp", u.Host, auth, &net.Dialer{})
+	if err != nil {
+		return nil, fmt.Errorf("invalid SOCKS5 proxy: %w", err)
+	}
+
+	// the SOCKS5 dialer of x/net always implements proxy.ContextDialer
+	contextDialer, ok := socks5.(proxy.ContextDialer)
+	if !ok {
+		return nil, errors.New("invalid SOCKS5 proxy: the dialer doesn't support contexts")
+	}
+
+	return func(ctx context.Context, address string) (net.Conn, error) {
+		return contextDialer.DialContext(ctx, "tcp", address)
+	}, nil
+}
+
 func newHTTPClient(ctx context.Context, cfg *config.OpenTelemetry) (otlptrace.Client, error) {
 	// OTel SDK does not support URL with scheme nor path, so we need to parse it
 	// The scheme will be added automatically, depending on the TLSInsure setting
@@ -74,9 +234,27 @@ func newHTTPClient(ctx context.Context, cfg *config.OpenTelemetry) (otlptrace.Cl
 	var clientOptions []otlptracehttp.Option
 	clientOptions = append(clientOptions, otlptracehttp.WithEndpoint(endpoint),
 		otlptracehttp.WithTimeout(time.Duration(cfg.ConnectionTimeout)*time.Second),
-		otlptracehttp.WithHeaders(cfg.Headers))
+		otlptracehttp.WithHeaders(httpHeaders(cfg)))
+
+	gzipCompression, err := isGzipCompression(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if gzipCompression {
+		clientOptions = append(clientOptions, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+	}
+
+	if policy := newRetryPolicy(&cfg.Retry); policy.enabled {
+		clientOptions = append(clientOptions, otlptracehttp.WithRetry(otlptracehttp.RetryConfig{
+			Enabled:         true,
+			InitialInterval: policy.initialInterval,
+			MaxInterval:     policy.maxInterval,
+			MaxElapsedTime:  policy.maxElapsedTime,
+		}))
+	}
 
-	isTLSDisabled := !cfg.TLS.Enable
+	isTLSDisabled := !cfg.TLS.Enabled
 
 	if isTLSDisabled {
 		clientOptions = append(clientOptions, otlptracehttp.WithInsecure())
@@ -91,6 +269,63 @@ func newHTTPClient(ctx context.Context, cfg *config.OpenTelemetry) (otlptrace.Cl
 	return otlptracehttp.NewClient(clientOptions...), nil
 }
 
+// isGzipCompression reports whether the payloads must be compressed with gzip,
+// returning an error for unknown compressions.
+func isGzipCompression(cfg *config.OpenTelemetry) (bool, error) {
+	switch cfg.Compression {
+	case "", config.NOCOMPRESSION:
+		return false, nil
+	case config.GZIPCOMPRESSION:
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid compression: %s", cfg.Compression)
+	}
+}
+
+// retryPolicy is the retry configuration of the exporters, with the intervals as durations.
+type retryPolicy struct {
+	enabled         bool
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	maxElapsedTime  time.Duration
+}
+
+func newRetryPolicy(cfg *config.Retry) retryPolicy {
+	return retryPolicy{
+		enabled:         cfg.Enabled,
+		initialInterval: time.Duration(cfg.InitialInterval) * time.Second,
+		maxInterval:     time.Duration(cfg.MaxInterval) * time.Second,
+		maxElapsedTime:  time.Duration(cfg.MaxElapsedTime) * time.Second,
+	}
+}
+
+// userAgent returns the configured User-Agent of the exporters, or the library default.
+func userAgent(cfg *config.OpenTelemetry) string {
+	if cfg.UserAgent != "" {
+		return cfg.UserAgent
+	}
+
+	return defaultUserAgent()
+}
+
+// httpHeaders returns the configured headers with the User-Agent of the exporters.
+// A User-Agent set in the headers takes precedence.
+func httpHeaders(cfg *config.OpenTelemetry) map[string]string {
+	headers := make(map[string]string, len(cfg.Headers)+1)
+
+	for k, v := range cfg.Headers {
+		if strings.EqualFold(k, "User-Agent") {
+			return cfg.Headers
+		}
+
+		headers[k] = v
+	}
+
+	headers["User-Agent"] = userAgent(cfg)
+
+	return headers
+}
+
 func parseEndpoint(cfg *config.OpenTelemetry) string {
 	endpoint := cfg.Endpoint
 	// Temporary adding scheme to get the host and port
@@ -118,6 +353,10 @@ func handleTLS(cfg *config.TLS) (*tls.Config, error) {
 		InsecureSkipVerify: cfg.InsecureSkipVerify,
 	}
 
+	if cfg.PKCS12File != "" && (cfg.CertFile != "" || cfg.KeyFile != "") {
+		return nil, errors.New("pkcs12_file can't be used together with cert_file and key_file")
+	}
+
 	if cfg.CertFile != "" && cfg.KeyFile != "" {
 		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
 		if err != nil {
@@ -127,6 +366,15 @@ func handleTLS(cfg *config.TLS) (*tls.Config, error) {
 		TLSConf.Certificates = []tls.Certificate{cert}
 	}
 
+	if cfg.PKCS12File != "" {
+		cert, err := loadPKCS12(cfg.PKCS12File, cfg.PKCS12Password)
+		if err != nil {
+			return nil, err
+		}
+
+		TLSConf.Certificates = []tls.Certificate{cert}
+	}
+
 	if cfg.CAFile != "" {
 		caPem, err := os.ReadFile(cfg.CAFile)
 		if err != nil {
@@ -152,6 +400,31 @@ func handleTLS(cfg *config.TLS) (*tls.Config, error) {
 	return TLSConf, nil
 }
 
+// loadPKCS12 loads the client certificate, its private key and its chain from a PKCS#12 bundle.
+func loadPKCS12(path, password string) (tls.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	key, cert, chain, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to decode PKCS#12 bundle %s: %w", path, err)
+	}
+
+	certificate := tls.Certificate{
+		Certificate: [][]byte{cert.Raw},
+		PrivateKey:  key,
+		Leaf:        cert,
+	}
+
+	for _, chainCert := range chain {
+		certificate.Certificate = append(certificate.Certificate, chainCert.Raw)
+	}
+
+	return certificate, nil
+}
+
 func handleTLSVersion(cfg *config.TLS) (minVersion, maxVersion int, err error) {
 	validVersions := map[string]int{
 		"1.0": tls.VersionTLS10,
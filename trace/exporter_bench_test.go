@@ -0,0 +1,224 @@
+package trace
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/proto"
+)
+
+// Benchmark_Exporter compares the gRPC and HTTP OTLP exporters with and without gzip compression and TLS.
+// Each iteration exports a batch with the default batch size of the SDK batch span processor.
+//
+// Run it with:
+//
+//	go test -run=^$ -bench=Benchmark_Exporter -benchmem ./trace
+func Benchmark_Exporter(b *testing.B) {
+	spans := benchmarkSpans(512)
+
+	for _, exporterType := range []string{config.GRPCEXPORTER, config.HTTPEXPORTER} {
+		for _, withTLS := range []bool{false, true} {
+			for _, withGzip := range []bool{false, true} {
+				name := exporterType
+				if withTLS {
+					name += "/tls"
+				} else {
+					name += "/plain"
+				}
+
+				if withGzip {
+					name += "/gzip"
+				} else {
+					name += "/none"
+				}
+
+				b.Run(name, func(b *testing.B) {
+					exporter := benchmarkExporter(b, exporterType, withTLS, withGzip)
+
+					ctx := context.Background()
+					defer exporter.Shutdown(ctx)
+
+					b.ReportAllocs()
+					b.ResetTimer()
+
+					for i := 0; i < b.N; i++ {
+						if err := exporter.ExportSpans(ctx, spans); err != nil {
+							b.Fatal(err)
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
+type benchmarkTraceService struct {
+	coltracepb.UnimplementedTraceServiceServer
+}
+
+func (s *benchmarkTraceService) Export(context.Context, *coltracepb.ExportTraceServiceRequest,
+) (*coltracepb.ExportTraceServiceResponse, error) {
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+// benchmarkExporter starts a collector stub and returns an exporter connected to it.
+func benchmarkExporter(b *testing.B, exporterType string, withTLS, withGzip bool) sdktrace.SpanExporter {
+	b.Helper()
+
+	// the httptest TLS server provides a self-signed certificate that we also reuse for the gRPC server
+	// the HTTP stub decodes the payload like the gRPC server does, so both exporters are compared fairly
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body io.Reader = r.Body
+
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			defer gz.Close()
+
+			body = gz
+		}
+
+		data, err := io.ReadAll(body)
+		if err != nil || proto.Unmarshal(data, &coltracepb.ExportTraceServiceRequest{}) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.StartTLS()
+	b.Cleanup(server.Close)
+
+	cert := server.TLS.Certificates[0]
+	clientTLS := &tls.Config{InsecureSkipVerify: true} //nolint:gosec // self-signed test certificate
+
+	var client otlptrace.Client
+
+	switch exporterType {
+	case config.GRPCEXPORTER:
+		lis, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		serverOpts := []grpc.ServerOption{}
+		if withTLS {
+			serverOpts = append(serverOpts, grpc.Creds(credentials.NewServerTLSFromCert(&cert)))
+		}
+
+		s := grpc.NewServer(serverOpts...)
+		coltracepb.RegisterTraceServiceServer(s, &benchmarkTraceService{})
+
+		go func() {
+			_ = s.Serve(lis)
+		}()
+
+		b.Cleanup(s.Stop)
+
+		opts := []otlptracegrpc.Option{
+			otlptracegrpc.WithEndpoint(lis.Addr().String()),
+			otlptracegrpc.WithTimeout(10 * time.Second),
+		}
+
+		if withTLS {
+			opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(clientTLS)))
+		} else {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		if withGzip {
+			opts = append(opts, otlptracegrpc.WithCompressor("gzip"))
+		}
+
+		client = otlptracegrpc.NewClient(opts...)
+	case config.HTTPEXPORTER:
+		endpoint := server.Listener.Addr().String()
+
+		if !withTLS {
+			plainServer := httptest.NewServer(server.Config.Handler)
+			b.Cleanup(plainServer.Close)
+
+			endpoint = plainServer.Listener.Addr().String()
+		}
+
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(endpoint),
+			otlptracehttp.WithTimeout(10 * time.Second),
+		}
+
+		if withTLS {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(clientTLS))
+		} else {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		if withGzip {
+			opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+		}
+
+		client = otlptracehttp.NewClient(opts...)
+	}
+
+	exporter, err := otlptrace.New(context.Background(), client)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return exporter
+}
+
+// benchmarkSpans returns spans similar to the ones generated by NewHTTPHandler in the Tyk gateway.
+func benchmarkSpans(n int) []sdktrace.ReadOnlySpan {
+	stubs := make(tracetest.SpanStubs, 0, n)
+	now := time.Now()
+
+	for i := 0; i < n; i++ {
+		stubs = append(stubs, tracetest.SpanStub{
+			Name: "GET /api/v1/users/" + strconv.Itoa(i),
+			SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    trace.TraceID{0x01, byte(i)},
+				SpanID:     trace.SpanID{0x01, byte(i)},
+				TraceFlags: trace.FlagsSampled,
+			}),
+			SpanKind:  trace.SpanKindServer,
+			StartTime: now,
+			EndTime:   now.Add(25 * time.Millisecond),
+			Attributes: []Attribute{
+				NewAttribute("http.method", "GET"),
+				NewAttribute("http.scheme", "https"),
+				NewAttribute("http.status_code", 200),
+				NewAttribute("http.target", "/api/v1/users/"+strconv.Itoa(i)),
+				NewAttribute("http.user_agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36"),
+				NewAttribute("http.request.body.size", 128),
+				NewAttribute("http.response.body.size", 2048),
+				NewAttribute("net.host.name", "gateway.tyk.io"),
+				NewAttribute("tyk.api.id", "b84fe1a04e5648927971c0557971565c"),
+				NewAttribute("tyk.api.name", "users-api"),
+				NewAttribute("tyk.api.orgid", "5e9d9544a1dcd60001d0ed20"),
+			},
+		})
+	}
+
+	return stubs.Snapshots()
+}
@@ -0,0 +1,118 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/resolver"
+)
+
+// dnsScheme is the gRPC resolver scheme resolving the endpoints through DNS.
+const dnsScheme = "dns"
+
+// grpcDialOptions returns the dial options of the gRPC connection settings, and the target to dial,
+// which goes through the DNS resolver when the load balancing or the DNS refresh are enabled.
+// The periodic DNS refresh stops when ctx is done.
+func grpcDialOptions(ctx context.Context, cfg *config.OpenTelemetry) (string, []grpc.DialOption, error) {
+	target := cfg.Endpoint
+	dialOptions := []grpc.DialOption{}
+
+	switch cfg.GRPC.LoadBalancing {
+	case "", config.PICKFIRSTBALANCING:
+	case config.ROUNDROBINBALANCING:
+		dialOptions = append(dialOptions,
+			grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"loadBalancingConfig":[{%q:{}}]}`, config.ROUNDROBINBALANCING)))
+	default:
+		return "", nil, fmt.Errorf("invalid load balancing policy: %s", cfg.GRPC.LoadBalancing)
+	}
+
+	if cfg.GRPC.LoadBalancing == config.ROUNDROBINBALANCING || cfg.GRPC.DNSRefreshInterval > 0 {
+		// the default target scheme of gRPC passes the endpoint as is to the dialer, without resolving it
+		if !strings.Contains(target, "://") {
+			target = dnsScheme + ":///" + target
+		}
+	}
+
+	if cfg.GRPC.DNSRefreshInterval > 0 {
+		dialOptions = append(dialOptions, grpc.WithResolvers(&dnsRefreshBuilder{
+			ctx:      ctx,
+			interval: time.Duration(cfg.GRPC.DNSRefreshInterval) * time.Second,
+		}))
+	}
+
+	if cfg.GRPC.Keepalive.Time > 0 {
+		// a zero timeout is replaced by the gRPC default
+		dialOptions = append(dialOptions, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                time.Duration(cfg.GRPC.Keepalive.Time) * time.Second,
+			Timeout:             time.Duration(cfg.GRPC.Keepalive.Timeout) * time.Second,
+			PermitWithoutStream: cfg.GRPC.Keepalive.PermitWithoutStream,
+		}))
+	}
+
+	return target, dialOptions, nil
+}
+
+// dnsRefreshBuilder builds DNS resolvers re-resolving the target periodically. The gRPC DNS resolver
+// only re-resolves it when a connection fails, so the new addresses are never used while the
+// connections to the previous ones are healthy.
+type dnsRefreshBuilder struct {
+	ctx      context.Context
+	interval time.Duration
+}
+
+func (b *dnsRefreshBuilder) Build(target resolver.Target, cc resolver.ClientConn,
+	opts resolver.BuildOptions) (resolver.Resolver, error) {
+	dnsBuilder := resolver.Get(dnsScheme)
+	if dnsBuilder == nil {
+		return nil, fmt.Errorf("resolver %s is not registered", dnsScheme)
+	}
+
+	r, err := dnsBuilder.Build(target, cc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh := &dnsRefreshResolver{
+		Resolver: r,
+		done:     make(chan struct{}),
+	}
+
+	go refresh.refresh(b.ctx, b.interval)
+
+	return refresh, nil
+}
+
+func (b *dnsRefreshBuilder) Scheme() string {
+	return dnsScheme
+}
+
+type dnsRefreshResolver struct {
+	resolver.Resolver
+	done chan struct{}
+}
+
+func (r *dnsRefreshResolver) refresh(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.ResolveNow(resolver.ResolveNowOptions{})
+		}
+	}
+}
+
+func (r *dnsRefreshResolver) Close() {
+	close(r.done)
+	r.Resolver.Close()
+}
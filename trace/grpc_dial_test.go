@@ -0,0 +1,161 @@
+package trace
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/resolver"
+)
+
+func Test_GRPCDialOptions(t *testing.T) {
+	tcs := []struct {
+		name            string
+		givenGRPC       config.GRPC
+		givenEndpoint   string
+		expectedTarget  string
+		expectedOptions int
+		expectedErr     string
+	}{
+		{
+			name:           "defaults",
+			givenEndpoint:  "localhost:4317",
+			expectedTarget: "localhost:4317",
+		},
+		{
+			name:            "round robin",
+			givenGRPC:       config.GRPC{LoadBalancing: "round_robin"},
+			givenEndpoint:   "collector.observability.svc:4317",
+			expectedTarget:  "dns:///collector.observability.svc:4317",
+			expectedOptions: 1,
+		},
+		{
+			name:            "dns refresh",
+			givenGRPC:       config.GRPC{LoadBalancing: "pick_first", DNSRefreshInterval: 60},
+			givenEndpoint:   "collector:4317",
+			expectedTarget:  "dns:///collector:4317",
+			expectedOptions: 1,
+		},
+		{
+			name:            "target with scheme",
+			givenGRPC:       config.GRPC{LoadBalancing: "round_robin", DNSRefreshInterval: 60},
+			givenEndpoint:   "dns://8.8.8.8/collector:4317",
+			expectedTarget:  "dns://8.8.8.8/collector:4317",
+			expectedOptions: 2,
+		},
+		{
+			name: "keepalive",
+			givenGRPC: config.GRPC{Keepalive: config.GRPCKeepalive{
+				Time:                30,
+				Timeout:             5,
+				PermitWithoutStream: true,
+			}},
+			givenEndpoint:   "localhost:4317",
+			expectedTarget:  "localhost:4317",
+			expectedOptions: 1,
+		},
+		{
+			name:          "invalid load balancing",
+			givenGRPC:     config.GRPC{LoadBalancing: "least_request"},
+			givenEndpoint: "localhost:4317",
+			expectedErr:   "invalid load balancing policy: least_request",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			target, options, err := grpcDialOptions(context.Background(), &config.OpenTelemetry{
+				Endpoint: tc.givenEndpoint,
+				GRPC:     tc.givenGRPC,
+			})
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedTarget, target)
+			assert.Len(t, options, tc.expectedOptions)
+		})
+	}
+}
+
+func Test_GRPCConnectionSettings(t *testing.T) {
+	collector := startTraceCollector(t)
+
+	_, port, err := net.SplitHostPort(collector)
+	assert.Nil(t, err)
+
+	err = warmUpExporter(context.Background(), &config.OpenTelemetry{
+		Exporter:          "grpc",
+		Endpoint:          net.JoinHostPort("localhost", port),
+		ConnectionTimeout: 1,
+		GRPC: config.GRPC{
+			LoadBalancing:      "round_robin",
+			DNSRefreshInterval: 30,
+			Keepalive: config.GRPCKeepalive{
+				Time:                10,
+				PermitWithoutStream: true,
+			},
+		},
+	}, nil)
+	assert.Nil(t, err)
+}
+
+type countingResolver struct {
+	resolveNow atomic.Int64
+	closed     atomic.Bool
+}
+
+func (r *countingResolver) ResolveNow(resolver.ResolveNowOptions) {
+	r.resolveNow.Add(1)
+}
+
+func (r *countingResolver) Close() {
+	r.closed.Store(true)
+}
+
+func Test_DNSRefreshResolver(t *testing.T) {
+	counting := &countingResolver{}
+	refresh := &dnsRefreshResolver{
+		Resolver: counting,
+		done:     make(chan struct{}),
+	}
+
+	go refresh.refresh(context.Background(), 10*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		return counting.resolveNow.Load() >= 2
+	}, time.Second, 5*time.Millisecond)
+
+	refresh.Close()
+	assert.True(t, counting.closed.Load())
+}
+
+func Test_DNSRefreshResolverContextDone(t *testing.T) {
+	counting := &countingResolver{}
+	refresh := &dnsRefreshResolver{
+		Resolver: counting,
+		done:     make(chan struct{}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+
+	go func() {
+		refresh.refresh(ctx, 10*time.Millisecond)
+		close(stopped)
+	}()
+
+	cancel()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("the DNS refresh didn't stop when the context was done")
+	}
+}
@@ -0,0 +1,238 @@
+// Package grpctrace provides gRPC stats handlers that instrument the RPCs with spans,
+// recording the message sizes and a span event for every message sent and received.
+// Stats handlers are set when creating the server or dialing the client, so they suit
+// the transports where interceptors can't be easily injected.
+package grpctrace
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"github.com/TykTechnologies/opentelemetry/trace"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+const instrumentationName = "github.com/TykTechnologies/opentelemetry/trace/grpctrace"
+
+const (
+	// RequestSizeKey is the total uncompressed size of the messages received by the
+	// servers or sent by the clients.
+	RequestSizeKey = attribute.Key("rpc.request.size")
+	// ResponseSizeKey is the total uncompressed size of the messages sent by the
+	// servers or received by the clients.
+	ResponseSizeKey = attribute.Key("rpc.response.size")
+)
+
+type Option interface {
+	apply(*handler)
+}
+
+type opts struct {
+	fn func(*handler)
+}
+
+func (o *opts) apply(h *handler) {
+	o.fn(h)
+}
+
+// WithTracerProvider sets the tracer provider used to create the RPC spans.
+// Defaults to the global tracer provider, which is the one set by trace.NewProvider.
+func WithTracerProvider(tp oteltrace.TracerProvider) Option {
+	return &opts{
+		fn: func(h *handler) {
+			h.tracer = tp.Tracer(instrumentationName)
+		},
+	}
+}
+
+// WithAttributes adds the given attributes to all the RPC spans.
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return &opts{
+		fn: func(h *handler) {
+			h.attrs = append(h.attrs, attrs...)
+		},
+	}
+}
+
+// WithoutMessageEvents disables the span events recorded for every message, keeping
+// only the total sizes. It's useful to limit the size of long-lived streaming spans.
+func WithoutMessageEvents() Option {
+	return &opts{
+		fn: func(h *handler) {
+			h.messageEvents = false
+		},
+	}
+}
+
+type handler struct {
+	tracer        oteltrace.Tracer
+	kind          oteltrace.SpanKind
+	attrs         []attribute.KeyValue
+	messageEvents bool
+}
+
+var _ stats.Handler = &handler{}
+
+/*
+	NewServerHandler returns a gRPC stats handler that creates a server span for every RPC,
+	continuing the trace propagated in the incoming metadata.
+
+Example
+
+	server := grpc.NewServer(grpc.StatsHandler(grpctrace.NewServerHandler()))
+*/
+func NewServerHandler(options ...Option) stats.Handler {
+	return newHandler(oteltrace.SpanKindServer, options)
+}
+
+/*
+	NewClientHandler returns a gRPC stats handler that creates a client span for every RPC,
+	propagating the span context in the outgoing metadata.
+
+Example
+
+	conn, err := grpc.Dial(target, grpc.WithStatsHandler(grpctrace.NewClientHandler()))
+*/
+func NewClientHandler(options ...Option) stats.Handler {
+	return newHandler(oteltrace.SpanKindClient, options)
+}
+
+func newHandler(kind oteltrace.SpanKind, options []Option) *handler {
+	h := &handler{
+		tracer:        otel.GetTracerProvider().Tracer(instrumentationName),
+		kind:          kind,
+		attrs:         []attribute.KeyValue{semconv.RPCSystemGRPC},
+		messageEvents: true,
+	}
+
+	for _, opt := range options {
+		opt.apply(h)
+	}
+
+	return h
+}
+
+type rpcContextKey struct{}
+
+// rpcInfo keeps the counters of an RPC, it's shared by the stats of the RPC through the context.
+type rpcInfo struct {
+	sentID        atomic.Int64
+	receivedID    atomic.Int64
+	sentBytes     atomic.Int64
+	receivedBytes atomic.Int64
+}
+
+func (h *handler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	if h.kind == oteltrace.SpanKindServer {
+		ctx = trace.ExtractGRPCMetadata(ctx)
+	}
+
+	name, attrs := parseFullMethod(info.FullMethodName)
+
+	ctx, _ = h.tracer.Start(ctx, name,
+		oteltrace.WithSpanKind(h.kind),
+		oteltrace.WithAttributes(append(attrs, h.attrs...)...))
+
+	if h.kind == oteltrace.SpanKindClient {
+		ctx = trace.InjectGRPCMetadata(ctx)
+	}
+
+	return context.WithValue(ctx, rpcContextKey{}, &rpcInfo{})
+}
+
+func (h *handler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	info, ok := ctx.Value(rpcContextKey{}).(*rpcInfo)
+	if !ok {
+		return
+	}
+
+	span := oteltrace.SpanFromContext(ctx)
+
+	switch rs := rs.(type) {
+	case *stats.InPayload:
+		info.receivedBytes.Add(int64(rs.Length))
+		h.messageEvent(span, semconv.MessageTypeReceived, info.receivedID.Add(1), rs.Length, rs.CompressedLength)
+	case *stats.OutPayload:
+		info.sentBytes.Add(int64(rs.Length))
+		h.messageEvent(span, semconv.MessageTypeSent, info.sentID.Add(1), rs.Length, rs.CompressedLength)
+	case *stats.End:
+		requestSize, responseSize := info.receivedBytes.Load(), info.sentBytes.Load()
+		if h.kind == oteltrace.SpanKindClient {
+			requestSize, responseSize = responseSize, requestSize
+		}
+
+		code := status.Code(rs.Error)
+
+		span.SetAttributes(
+			RequestSizeKey.Int64(requestSize),
+			ResponseSizeKey.Int64(responseSize),
+			semconv.RPCGRPCStatusCodeKey.Int(int(code)),
+		)
+
+		if h.isError(code) {
+			span.SetStatus(codes.Error, status.Convert(rs.Error).Message())
+		}
+
+		span.End(oteltrace.WithTimestamp(rs.EndTime))
+	}
+}
+
+func (h *handler) messageEvent(span oteltrace.Span, messageType attribute.KeyValue, id int64, size, compressedSize int) {
+	if !h.messageEvents {
+		return
+	}
+
+	span.AddEvent("message", oteltrace.WithAttributes(
+		messageType,
+		semconv.MessageIDKey.Int64(id),
+		semconv.MessageUncompressedSizeKey.Int(size),
+		semconv.MessageCompressedSizeKey.Int(compressedSize),
+	))
+}
+
+// isError reports whether the status code is an error for the span kind, following the semantic
+// conventions: all the non OK codes are errors for the clients, while the servers only flag the
+// codes caused by the server itself.
+func (h *handler) isError(code grpccodes.Code) bool {
+	if h.kind == oteltrace.SpanKindClient {
+		return code != grpccodes.OK
+	}
+
+	switch code {
+	case grpccodes.Unknown, grpccodes.DeadlineExceeded, grpccodes.Unimplemented,
+		grpccodes.Internal, grpccodes.Unavailable, grpccodes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
+
+func (h *handler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h *handler) HandleConn(context.Context, stats.ConnStats) {}
+
+// parseFullMethod returns the span name and the rpc attributes of a full method name
+// in the "/package.service/method" format.
+func parseFullMethod(fullMethod string) (string, []attribute.KeyValue) {
+	name := strings.TrimPrefix(fullMethod, "/")
+
+	service, method, found := strings.Cut(name, "/")
+	if !found {
+		return name, []attribute.KeyValue{}
+	}
+
+	return name, []attribute.KeyValue{
+		semconv.RPCService(service),
+		semconv.RPCMethod(method),
+	}
+}
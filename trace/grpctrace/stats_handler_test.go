@@ -0,0 +1,170 @@
+package grpctrace
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+type traceServer struct {
+	coltracepb.UnimplementedTraceServiceServer
+	err error
+}
+
+func (s *traceServer) Export(context.Context, *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	return &coltracepb.ExportTraceServiceResponse{}, s.err
+}
+
+func setup(t *testing.T, serverErr error, opts ...Option) (coltracepb.TraceServiceClient, *tracetest.InMemoryExporter) {
+	t.Helper()
+
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	opts = append(opts, WithTracerProvider(tp))
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	assert.Nil(t, err)
+
+	server := grpc.NewServer(grpc.StatsHandler(NewServerHandler(opts...)))
+	coltracepb.RegisterTraceServiceServer(server, &traceServer{err: serverErr})
+
+	go func() {
+		if err := server.Serve(lis); err != nil {
+			t.Logf("failed to serve: %v", err)
+		}
+	}()
+
+	conn, err := grpc.Dial(lis.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(NewClientHandler(opts...)))
+	assert.Nil(t, err)
+
+	t.Cleanup(func() {
+		assert.Nil(t, conn.Close())
+		server.Stop()
+	})
+
+	return coltracepb.NewTraceServiceClient(conn), exporter
+}
+
+func spansByKind(spans tracetest.SpanStubs) (client, server tracetest.SpanStub) {
+	for _, span := range spans {
+		if span.SpanKind == oteltrace.SpanKindClient {
+			client = span
+		} else {
+			server = span
+		}
+	}
+
+	return client, server
+}
+
+func Test_StatsHandler(t *testing.T) {
+	client, exporter := setup(t, nil)
+
+	req := &coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{}},
+	}
+
+	_, err := client.Export(context.Background(), req)
+	assert.Nil(t, err)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+
+	clientSpan, serverSpan := spansByKind(spans)
+
+	for _, span := range []tracetest.SpanStub{clientSpan, serverSpan} {
+		assert.Equal(t, "opentelemetry.proto.collector.trace.v1.TraceService/Export", span.Name)
+		assert.Contains(t, span.Attributes, semconv.RPCSystemGRPC)
+		assert.Contains(t, span.Attributes, semconv.RPCService("opentelemetry.proto.collector.trace.v1.TraceService"))
+		assert.Contains(t, span.Attributes, semconv.RPCMethod("Export"))
+		assert.Contains(t, span.Attributes, semconv.RPCGRPCStatusCodeKey.Int(int(grpccodes.OK)))
+		assert.Contains(t, span.Attributes, RequestSizeKey.Int64(int64(2)))
+		assert.Contains(t, span.Attributes, ResponseSizeKey.Int64(int64(0)))
+		assert.Equal(t, codes.Unset, span.Status.Code)
+		assert.Len(t, span.Events, 2)
+	}
+
+	// the server span continues the client trace
+	assert.Equal(t, clientSpan.SpanContext.TraceID(), serverSpan.SpanContext.TraceID())
+	assert.Equal(t, clientSpan.SpanContext.SpanID(), serverSpan.Parent.SpanID())
+
+	assert.Equal(t, "message", clientSpan.Events[0].Name)
+	assert.Contains(t, clientSpan.Events[0].Attributes, semconv.MessageTypeSent)
+	assert.Contains(t, clientSpan.Events[0].Attributes, semconv.MessageIDKey.Int64(1))
+	assert.Contains(t, clientSpan.Events[1].Attributes, semconv.MessageTypeReceived)
+	assert.Contains(t, serverSpan.Events[0].Attributes, semconv.MessageTypeReceived)
+	assert.Contains(t, serverSpan.Events[1].Attributes, semconv.MessageTypeSent)
+}
+
+func Test_StatsHandlerErrors(t *testing.T) {
+	tcs := []struct {
+		name                 string
+		serverErr            error
+		expectedClientStatus codes.Code
+		expectedServerStatus codes.Code
+	}{
+		{
+			name:                 "client error",
+			serverErr:            status.Error(grpccodes.InvalidArgument, "invalid spans"),
+			expectedClientStatus: codes.Error,
+			expectedServerStatus: codes.Unset,
+		},
+		{
+			name:                 "server error",
+			serverErr:            status.Error(grpccodes.Internal, "storage unavailable"),
+			expectedClientStatus: codes.Error,
+			expectedServerStatus: codes.Error,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			client, exporter := setup(t, tc.serverErr, WithoutMessageEvents())
+
+			_, err := client.Export(context.Background(), &coltracepb.ExportTraceServiceRequest{})
+			assert.NotNil(t, err)
+
+			clientSpan, serverSpan := spansByKind(exporter.GetSpans())
+			assert.Equal(t, tc.expectedClientStatus, clientSpan.Status.Code)
+			assert.Equal(t, tc.expectedServerStatus, serverSpan.Status.Code)
+
+			code := int(status.Code(tc.serverErr))
+			assert.Contains(t, clientSpan.Attributes, semconv.RPCGRPCStatusCodeKey.Int(code))
+			assert.Contains(t, serverSpan.Attributes, semconv.RPCGRPCStatusCodeKey.Int(code))
+
+			assert.Empty(t, clientSpan.Events)
+			assert.Empty(t, serverSpan.Events)
+		})
+	}
+}
+
+func Test_ParseFullMethod(t *testing.T) {
+	name, attrs := parseFullMethod("/tyk.Dispatcher/Dispatch")
+	assert.Equal(t, "tyk.Dispatcher/Dispatch", name)
+	assert.Equal(t, []attribute.KeyValue{semconv.RPCService("tyk.Dispatcher"), semconv.RPCMethod("Dispatch")}, attrs)
+
+	name, attrs = parseFullMethod("invalid")
+	assert.Equal(t, "invalid", name)
+	assert.Empty(t, attrs)
+}
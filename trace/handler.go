@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -30,20 +31,68 @@ func (rw *responseWriterWithSize) Flush() {
 	rw.ResponseWriter.(http.Flusher).Flush()
 }
 
+// RouteResolver returns the route template matched by the request, for example "/users/{id}",
+// or an empty string if no template is available. It can be called more than once per request.
+type RouteResolver func(r *http.Request) string
+
+// DefaultRoutePlaceholder is the http.route used when the route template of the request is not available.
+const DefaultRoutePlaceholder = "unknown_route"
+
 // NewHTTPHandler wraps the provided http.Handler with one that starts a span
 // and injects the span context into the outbound request headers.
 // You need to initialize the TracerProvider first since it utilizes the underlying
-// TracerProvider and propagators.
+// TracerProvider of tp and the global propagators. If tp is nil, the global TracerProvider is used.
 // It also utilizes a spanNameFormatter to format the span name r.Method + " " + r.URL.Path.
 func NewHTTPHandler(name string, handler http.Handler, tp Provider, attr ...Attribute) http.Handler {
+	return newHTTPHandler(name, handler, tp, nil, attr...)
+}
+
+// NewHTTPHandlerWithRoutes works like NewHTTPHandler, but it uses the route template returned by
+// the resolver instead of the raw request path, which has an unbounded cardinality.
+// The span name is formatted as r.Method + " " + route and the route is set as the http.route attribute.
+// If the resolver returns an empty string, the placeholder is used, or DefaultRoutePlaceholder if it's empty.
+func NewHTTPHandlerWithRoutes(name string, handler http.Handler, tp Provider, resolver RouteResolver,
+	placeholder string, attr ...Attribute) http.Handler {
+	if placeholder == "" {
+		placeholder = DefaultRoutePlaceholder
+	}
+
+	route := func(r *http.Request) string {
+		if resolver != nil {
+			if route := resolver(r); route != "" {
+				return route
+			}
+		}
+
+		return placeholder
+	}
+
+	return newHTTPHandler(name, handler, tp, route, attr...)
+}
+
+// newHTTPHandler wraps the handler, naming the spans after the route if route is not nil
+// or after the request path otherwise.
+func newHTTPHandler(name string, handler http.Handler, tp Provider, route RouteResolver, attr ...Attribute) http.Handler {
+	spanNameFormatter := httpSpanNameFormatter
+	if route != nil {
+		spanNameFormatter = func(operation string, r *http.Request) string {
+			return r.Method + " " + route(r)
+		}
+	}
+
 	opts := []otelhttp.Option{
-		otelhttp.WithSpanNameFormatter(httpSpanNameFormatter),
+		otelhttp.WithSpanNameFormatter(spanNameFormatter),
 	}
 
 	opts = append(opts, otelhttp.WithSpanOptions(
 		trace.WithAttributes(attr...),
 	))
 
+	// use the given provider instead of the global one, if any
+	if tp != nil {
+		opts = append(opts, otelhttp.WithTracerProvider(tp.TracerProvider()))
+	}
+
 	return otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		span := trace.SpanFromContext(r.Context())
 		// Wrap response writer to capture the response size
@@ -55,6 +104,10 @@ func NewHTTPHandler(name string, handler http.Handler, tp Provider, attr ...Attr
 			rw.Hijacker = h
 		}
 
+		if route != nil {
+			span.SetAttributes(semconv.HTTPRoute(route(r)))
+		}
+
 		span.SetAttributes(NewAttribute("http.request.body.size", r.ContentLength))
 		handler.ServeHTTP(rw, r)
 		span.SetAttributes(NewAttribute("http.response.body.size", rw.size))
@@ -67,6 +120,20 @@ var httpSpanNameFormatter = func(operation string, r *http.Request) string {
 
 // NewHTTPTransport wraps the provided http.RoundTripper with one that
 // starts a span and injects the span context into the outbound request headers.
-func NewHTTPTransport(base http.RoundTripper) http.RoundTripper {
+// The options enable the tracing of the retries, the connection timings and the client events.
+func NewHTTPTransport(base http.RoundTripper, opts ...TransportOption) http.RoundTripper {
+	cfg := transportConfig{}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+
+	if cfg.retrySpans || cfg.connectionTimings || cfg.clientTrace {
+		if base == nil {
+			base = http.DefaultTransport
+		}
+
+		base = &attemptTransport{base: base, cfg: cfg}
+	}
+
 	return otelhttp.NewTransport(base)
 }
@@ -6,11 +6,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -155,3 +157,68 @@ func Test_NewHTTPHandler(t *testing.T) {
 	// check if the response is the same as the content
 	assert.Equal(t, body, content)
 }
+
+func Test_NewHTTPHandlerWithRoutes(t *testing.T) {
+	resolver := func(r *http.Request) string {
+		if strings.HasPrefix(r.URL.Path, "/users/") {
+			return "/users/{id}"
+		}
+
+		return ""
+	}
+
+	tcs := []struct {
+		name          string
+		path          string
+		resolver      RouteResolver
+		placeholder   string
+		expectedName  string
+		expectedRoute string
+	}{
+		{
+			name:          "resolved route",
+			path:          "/users/123",
+			resolver:      resolver,
+			expectedName:  "GET /users/{id}",
+			expectedRoute: "/users/{id}",
+		},
+		{
+			name:          "unresolved route with default placeholder",
+			path:          "/orders/123",
+			resolver:      resolver,
+			expectedName:  "GET unknown_route",
+			expectedRoute: DefaultRoutePlaceholder,
+		},
+		{
+			name:          "unresolved route with configured placeholder",
+			path:          "/orders/123",
+			resolver:      resolver,
+			placeholder:   "/*",
+			expectedName:  "GET /*",
+			expectedRoute: "/*",
+		},
+		{
+			name:          "nil resolver",
+			path:          "/users/123",
+			expectedName:  "GET unknown_route",
+			expectedRoute: DefaultRoutePlaceholder,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, exporter := newInMemoryProvider()
+
+			handler := NewHTTPHandlerWithRoutes("test", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}), provider, tc.resolver, tc.placeholder)
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			spans := exporter.GetSpans()
+			assert.Len(t, spans, 1)
+			assert.Equal(t, tc.expectedName, spans[0].Name)
+			assert.Contains(t, spans[0].Attributes, semconv.HTTPRoute(tc.expectedRoute))
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package trace
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// HeartbeatEventName is the name of the events recorded periodically on the long-running spans.
+	HeartbeatEventName = "heartbeat"
+	// SpanSnapshotName is the name of the spans emitted as intermediate snapshots of the long-running spans.
+	SpanSnapshotName = "span.snapshot"
+
+	// HeartbeatCountKey is the number of heartbeats recorded so far, starting at 1.
+	HeartbeatCountKey = attribute.Key("heartbeat.count")
+	// HeartbeatElapsedKey is the time since the heartbeat started, in milliseconds.
+	HeartbeatElapsedKey = attribute.Key("heartbeat.elapsed")
+	// HeartbeatBytesKey is the number of bytes transferred so far, as reported by the bytes function.
+	HeartbeatBytesKey = attribute.Key("heartbeat.bytes_transferred")
+)
+
+// HeartbeatOption configures the heartbeat started with StartHeartbeat.
+type HeartbeatOption interface {
+	apply(*heartbeatConfig)
+}
+
+type heartbeatOpts struct {
+	fn func(*heartbeatConfig)
+}
+
+func (o *heartbeatOpts) apply(cfg *heartbeatConfig) {
+	o.fn(cfg)
+}
+
+type heartbeatConfig struct {
+	bytes          func() int64
+	snapshotTracer Tracer
+}
+
+/*
+	WithHeartbeatBytes adds the number of bytes transferred so far to the heartbeats.
+	The function is called on every heartbeat, so it must be safe for concurrent use.
+
+Example
+
+	var transferred atomic.Int64
+	stop := trace.StartHeartbeat(ctx, span, 30*time.Second, trace.WithHeartbeatBytes(transferred.Load))
+	defer stop()
+*/
+func WithHeartbeatBytes(bytes func() int64) HeartbeatOption {
+	return &heartbeatOpts{
+		fn: func(cfg *heartbeatConfig) {
+			cfg.bytes = bytes
+		},
+	}
+}
+
+/*
+	WithHeartbeatSnapshots emits a short child span on every heartbeat, linked to the long-running span.
+	It's useful for backends that drop or hide the spans exceeding a maximum age, since the
+	long-running span is only exported when it ends.
+
+Example
+
+	stop := trace.StartHeartbeat(ctx, span, 30*time.Second, trace.WithHeartbeatSnapshots(provider.Tracer()))
+	defer stop()
+*/
+func WithHeartbeatSnapshots(tracer Tracer) HeartbeatOption {
+	return &heartbeatOpts{
+		fn: func(cfg *heartbeatConfig) {
+			cfg.snapshotTracer = tracer
+		},
+	}
+}
+
+// StartHeartbeat records a heartbeat event on the span every interval, for streaming,
+// websocket or SSE spans that live for minutes. The heartbeat stops when the returned
+// function is called or the context is done. The returned function waits for the
+// heartbeat goroutine to finish, so it must be called before ending the span.
+// It's a noop if the span is not recording or the interval is not positive.
+func StartHeartbeat(ctx context.Context, span Span, interval time.Duration, opts ...HeartbeatOption) func() {
+	cfg := heartbeatConfig{}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+
+	if !span.IsRecording() || interval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		start := time.Now()
+
+		for count := 1; ; count++ {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				beat(ctx, span, &cfg, count, now.Sub(start))
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			close(done)
+		})
+
+		<-stopped
+	}
+}
+
+func beat(ctx context.Context, span Span, cfg *heartbeatConfig, count int, elapsed time.Duration) {
+	attrs := []attribute.KeyValue{
+		HeartbeatCountKey.Int(count),
+		HeartbeatElapsedKey.Float64(float64(elapsed) / float64(time.Millisecond)),
+	}
+
+	if cfg.bytes != nil {
+		attrs = append(attrs, HeartbeatBytesKey.Int64(cfg.bytes()))
+	}
+
+	span.AddEvent(HeartbeatEventName, oteltrace.WithAttributes(attrs...))
+
+	if cfg.snapshotTracer != nil {
+		_, snapshot := cfg.snapshotTracer.Start(oteltrace.ContextWithSpan(ctx, span), SpanSnapshotName,
+			oteltrace.WithAttributes(attrs...),
+			oteltrace.WithLinks(oteltrace.Link{SpanContext: span.SpanContext()}),
+		)
+		snapshot.End()
+	}
+}
@@ -0,0 +1,83 @@
+package trace
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestStartHeartbeat(t *testing.T) {
+	t.Run("records heartbeat events", func(t *testing.T) {
+		provider, exporter := newInMemoryProvider()
+
+		var transferred atomic.Int64
+
+		ctx, span := provider.Tracer().Start(context.Background(), "stream")
+		stop := StartHeartbeat(ctx, span, 5*time.Millisecond, WithHeartbeatBytes(transferred.Load))
+
+		transferred.Store(1024)
+		time.Sleep(30 * time.Millisecond)
+
+		stop()
+		// stopping twice is safe
+		stop()
+		span.End()
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 1)
+		assert.NotEmpty(t, spans[0].Events)
+
+		for i, event := range spans[0].Events {
+			assert.Equal(t, HeartbeatEventName, event.Name)
+			assert.Contains(t, event.Attributes, HeartbeatCountKey.Int(i+1))
+			assert.Contains(t, event.Attributes, HeartbeatBytesKey.Int64(1024))
+		}
+	})
+
+	t.Run("emits span snapshots", func(t *testing.T) {
+		provider, exporter := newInMemoryProvider()
+
+		ctx, span := provider.Tracer().Start(context.Background(), "stream")
+		stop := StartHeartbeat(ctx, span, 5*time.Millisecond, WithHeartbeatSnapshots(provider.Tracer()))
+
+		time.Sleep(30 * time.Millisecond)
+		stop()
+
+		snapshots := exporter.GetSpans()
+		assert.NotEmpty(t, snapshots)
+
+		for _, snapshot := range snapshots {
+			assert.Equal(t, SpanSnapshotName, snapshot.Name)
+			assert.Equal(t, span.SpanContext().SpanID(), snapshot.Parent.SpanID())
+			assert.Equal(t, span.SpanContext(), snapshot.Links[0].SpanContext)
+		}
+
+		span.End()
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		provider, exporter := newInMemoryProvider()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		ctx, span := provider.Tracer().Start(ctx, "stream")
+		stop := StartHeartbeat(ctx, span, 5*time.Millisecond)
+
+		cancel()
+		stop()
+		span.End()
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 1)
+	})
+
+	t.Run("noop for non recording spans", func(t *testing.T) {
+		span := oteltrace.SpanFromContext(context.Background())
+
+		stop := StartHeartbeat(context.Background(), span, time.Millisecond)
+		stop()
+	})
+}
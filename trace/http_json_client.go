@@ -0,0 +1,330 @@
+package trace
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/metric"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// httpJSONTracesPath is the default OTLP/HTTP path for traces.
+const httpJSONTracesPath = "/v1/traces"
+
+const (
+	httpJSONRetriesMeterName = "github.com/TykTechnologies/opentelemetry/trace/httpjson"
+
+	// HTTPJSONRetriesName is the name of the counter with the number of export retries of the "http"
+	// exporters with the "json" encoding. The other exporters retry within the OpenTelemetry SDK,
+	// which doesn't expose its retries.
+	HTTPJSONRetriesName = "tyk.otel.export.http_json.retries"
+)
+
+// otlpJSONIDFields are the fields that OTLP/JSON encodes as hex strings instead
+// of the base64 strings used by the standard protobuf JSON mapping.
+var otlpJSONIDFields = map[string]bool{
+	"traceId":      true,
+	"spanId":       true,
+	"parentSpanId": true,
+}
+
+// httpJSONClient is an otlptrace.Client that sends the spans to the collector
+// using the JSON encoding of OTLP/HTTP, since otlptracehttp only supports protobuf.
+type httpJSONClient struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+	gzip    bool
+	retry   retryPolicy
+	// retries counts the retry attempts, if set.
+	retries metric.Int64Counter
+}
+
+var _ otlptrace.Client = &httpJSONClient{}
+
+// newHTTPJSONRetriesCounter creates the counter of the JSON export retries with the given meter provider.
+func newHTTPJSONRetriesCounter(mp metric.MeterProvider) (metric.Int64Counter, error) {
+	return mp.Meter(httpJSONRetriesMeterName).Int64Counter(HTTPJSONRetriesName,
+		metric.WithDescription("Number of export retries of the OTLP/HTTP JSON exporters."),
+		metric.WithUnit("{retry}"))
+}
+
+func newHTTPJSONClient(cfg *config.OpenTelemetry, retries metric.Int64Counter) (otlptrace.Client, error) {
+	scheme := "http"
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.TLS.Enabled {
+		TLSConf, err := handleTLS(&cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+
+		scheme = "https"
+		transport.TLSClientConfig = TLSConf
+	}
+
+	gzipCompression, err := isGzipCompression(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpJSONClient{
+		gzip:    gzipCompression,
+		retry:   newRetryPolicy(&cfg.Retry),
+		retries: retries,
+		url:     scheme + "://" + parseEndpoint(cfg) + httpJSONTracesPath,
+		headers: httpHeaders(cfg),
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   time.Duration(cfg.ConnectionTimeout) * time.Second,
+		},
+	}, nil
+}
+
+func (c *httpJSONClient) Start(ctx context.Context) error {
+	return nil
+}
+
+func (c *httpJSONClient) Stop(ctx context.Context) error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+func (c *httpJSONClient) UploadTraces(ctx context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	body, err := marshalOTLPJSON(&coltracepb.ExportTraceServiceRequest{ResourceSpans: protoSpans})
+	if err != nil {
+		return err
+	}
+
+	// the payload is encoded and compressed once, and reused by the retries
+	if c.gzip {
+		body, err = gzipPayload(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	start := time.Now()
+	interval := c.retry.initialInterval
+
+	for {
+		err := c.send(ctx, body)
+
+		var retryable *retryableExportError
+		if !c.retry.enabled || !errors.As(err, &retryable) {
+			return err
+		}
+
+		// the Retry-After header takes precedence over the backoff if it's longer
+		wait := interval
+		if retryable.retryAfter > wait {
+			wait = retryable.retryAfter
+		}
+
+		if time.Since(start)+wait > c.retry.maxElapsedTime {
+			return fmt.Errorf("max retry time elapsed: %w", err)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+
+		if c.retries != nil {
+			c.retries.Add(ctx, 1)
+		}
+
+		interval = min(interval*2, c.retry.maxInterval)
+	}
+}
+
+// send posts the payload to the collector, returning a retryableExportError for the responses
+// that must be retried according to the OTLP specification.
+func (c *httpJSONClient) send(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if c.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		// drain the body so the connection can be reused
+		_, _ = io.Copy(io.Discard, resp.Body)
+
+		err := fmt.Errorf("failed to send spans to %s: %s", c.url, resp.Status)
+
+		switch resp.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return &retryableExportError{err: err, retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+		default:
+			return err
+		}
+	}
+
+	respData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if err := partialSuccessError(respData); err != nil {
+		// the export is not retried, the rejected spans are reported through the
+		// global error handler as the OTLP exporters do.
+		otel.Handle(err)
+	}
+
+	return nil
+}
+
+// retryableExportError is returned by the exports that can be retried, with the wait requested by the collector.
+type retryableExportError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *retryableExportError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableExportError) Unwrap() error {
+	return e.err
+}
+
+// parseRetryAfter returns the wait of a Retry-After header, either in seconds or as an HTTP date.
+// It returns 0 if the header is missing or invalid.
+func parseRetryAfter(retryAfter string) time.Duration {
+	if retryAfter == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+
+	if date, err := http.ParseTime(retryAfter); err == nil {
+		return max(time.Until(date), 0)
+	}
+
+	return 0
+}
+
+// gzipPayload compresses the payload with gzip.
+func gzipPayload(payload []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(payload); err != nil {
+		return nil, err
+	}
+
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// partialSuccessError returns an error if the OTLP/JSON response reports
+// rejected spans or a warning message, nil otherwise.
+func partialSuccessError(respData []byte) error {
+	if len(bytes.TrimSpace(respData)) == 0 {
+		return nil
+	}
+
+	var resp coltracepb.ExportTraceServiceResponse
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(respData, &resp); err != nil {
+		return fmt.Errorf("failed to decode the export response: %w", err)
+	}
+
+	msg := resp.GetPartialSuccess().GetErrorMessage()
+	rejected := resp.GetPartialSuccess().GetRejectedSpans()
+
+	if rejected == 0 && msg == "" {
+		return nil
+	}
+
+	return fmt.Errorf("OTLP partial success: %s (%d spans rejected)", msg, rejected)
+}
+
+// marshalOTLPJSON encodes the request following the OTLP/JSON rules: enums
+// are encoded as numbers and trace and span IDs as hex strings.
+func marshalOTLPJSON(req *coltracepb.ExportTraceServiceRequest) ([]byte, error) {
+	data, err := protojson.MarshalOptions{UseEnumNumbers: true}.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+
+	if err := hexEncodeIDs(payload); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(payload)
+}
+
+// hexEncodeIDs replaces the base64 encoded IDs of the payload by their hex representation.
+func hexEncodeIDs(v interface{}) error {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, field := range value {
+			if id, ok := field.(string); ok && otlpJSONIDFields[k] {
+				raw, err := base64.StdEncoding.DecodeString(id)
+				if err != nil {
+					return err
+				}
+
+				value[k] = hex.EncodeToString(raw)
+
+				continue
+			}
+
+			if err := hexEncodeIDs(field); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if err := hexEncodeIDs(item); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// loadSheddingExporter wraps a span exporter and drops the spans for a cool-down period
+// after the exporter failed maxFailures consecutive times.
+// Once the cool-down period is over, the next export is attempted and a single failure
+// starts a new cool-down period, while a successful export resumes the normal behaviour.
+type loadSheddingExporter struct {
+	sdktrace.SpanExporter
+
+	maxFailures int
+	coolDown    time.Duration
+	logger      Logger
+
+	mu            sync.Mutex
+	failures      int
+	sheddingUntil time.Time
+	dropped       int
+
+	// now is used to get the current time, it's replaced in tests
+	now func() time.Time
+}
+
+func newLoadSheddingExporter(exporter sdktrace.SpanExporter, maxFailures int, coolDown time.Duration,
+	logger Logger) *loadSheddingExporter {
+	return &loadSheddingExporter{
+		SpanExporter: exporter,
+		maxFailures:  maxFailures,
+		coolDown:     coolDown,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+func (e *loadSheddingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	if e.now().Before(e.sheddingUntil) {
+		e.dropped += len(spans)
+		e.mu.Unlock()
+
+		return nil
+	}
+	e.mu.Unlock()
+
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err == nil {
+		if e.failures >= e.maxFailures {
+			e.logger.Info(fmt.Sprintf("exporter recovered, %d spans were dropped during the outage", e.dropped))
+		}
+
+		e.failures = 0
+		e.dropped = 0
+
+		return nil
+	}
+
+	e.failures++
+
+	if e.failures >= e.maxFailures {
+		e.sheddingUntil = e.now().Add(e.coolDown)
+		e.logger.Error(fmt.Sprintf("exporter failed %d consecutive times, dropping spans for %s", e.failures, e.coolDown))
+	}
+
+	return err
+}
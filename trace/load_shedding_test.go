@@ -0,0 +1,68 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type failingExporter struct {
+	testExporter
+	err   error
+	calls int
+}
+
+func (f *failingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	f.calls++
+	if f.err != nil {
+		return f.err
+	}
+
+	return f.testExporter.ExportSpans(ctx, spans)
+}
+
+func Test_LoadSheddingExporter(t *testing.T) {
+	ctx := context.Background()
+	spans := tracetest.SpanStubs{{Name: "span"}}.Snapshots()
+
+	now := time.Now()
+	fe := &failingExporter{err: errors.New("collector unavailable")}
+
+	exporter := newLoadSheddingExporter(fe, 3, time.Minute, &noopLogger{})
+	exporter.now = func() time.Time { return now }
+
+	// the first failures are returned to the span processor
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, fe.err, exporter.ExportSpans(ctx, spans))
+	}
+
+	assert.Equal(t, 3, fe.calls)
+
+	// spans are dropped during the cool-down period without calling the exporter
+	assert.Nil(t, exporter.ExportSpans(ctx, spans))
+	assert.Nil(t, exporter.ExportSpans(ctx, spans))
+	assert.Equal(t, 3, fe.calls)
+	assert.Equal(t, 2, exporter.dropped)
+
+	// after the cool-down period, a single failure starts a new cool-down period
+	now = now.Add(time.Minute)
+
+	assert.Equal(t, fe.err, exporter.ExportSpans(ctx, spans))
+	assert.Nil(t, exporter.ExportSpans(ctx, spans))
+	assert.Equal(t, 4, fe.calls)
+
+	// a successful export resumes the normal behaviour
+	now = now.Add(time.Minute)
+	fe.err = nil
+
+	assert.Nil(t, exporter.ExportSpans(ctx, spans))
+	assert.Equal(t, 5, fe.calls)
+	assert.Len(t, fe.spans, 1)
+	assert.Equal(t, 0, exporter.failures)
+	assert.Equal(t, 0, exporter.dropped)
+}
@@ -2,8 +2,12 @@ package trace
 
 import (
 	"context"
+	"time"
 
 	"github.com/TykTechnologies/opentelemetry/config"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 type Option interface {
@@ -62,7 +66,10 @@ func WithLogger(logger Logger) Option {
 }
 
 /*
-	WithContext sets the context for the tracer provider
+	WithContext sets the context for the tracer provider.
+	The background DNS refresh is stopped when the context is done, but the provider keeps exporting
+	the spans, so they can still be flushed by Shutdown. Use WithShutdownOnContextDone to shut the
+	provider down and cancel the exports in flight when the context is done.
 
 Example
 
@@ -118,6 +125,26 @@ func WithServiceVersion(version string) Option {
 	}
 }
 
+/*
+	WithSchemaURL sets the schema URL of the configured resource.
+	It defaults to the URL of the OpenTelemetry semantic conventions version used by this library.
+	If the resource detectors report a different schema URL, the one set here takes precedence.
+
+Example
+
+	provider, err := trace.NewProvider(trace.WithSchemaURL("https://opentelemetry.io/schemas/1.20.0"))
+	if err != nil {
+		panic(err)
+	}
+*/
+func WithSchemaURL(schemaURL string) Option {
+	return &opts{
+		fn: func(tp *traceProvider) {
+			tp.resources.schemaURL = schemaURL
+		},
+	}
+}
+
 /*
 	WithHostDetector adds attributes from the host to the configured resource.
 
@@ -175,6 +202,7 @@ func WithProcessDetector() Option {
 
 /*
 	WithCustomResourceAttributes adds custom attributes to the configured resource.
+	The custom attributes override the telemetry.sdk.* and telemetry.distro.* attributes set by default.
 
 Example
 
@@ -191,3 +219,262 @@ func WithCustomResourceAttributes(attrs ...Attribute) Option {
 		},
 	}
 }
+
+/*
+	WithResourceDetector adds a custom resource detector to the configured resource.
+	The attributes returned by the detector are merged with the rest of the resource attributes.
+	It can be used multiple times to add several detectors.
+
+Example
+
+	detector := resource.StringDetector(semconv.SchemaURL, "tyk.cluster.id", func() (string, error) {
+		return clusterID, nil
+	})
+	provider, err := trace.NewProvider(trace.WithResourceDetector(detector))
+	if err != nil {
+		panic(err)
+	}
+*/
+func WithResourceDetector(detector resource.Detector) Option {
+	return &opts{
+		fn: func(tp *traceProvider) {
+			tp.resources.detectors = append(tp.resources.detectors, detector)
+		},
+	}
+}
+
+/*
+	WithSpanMetrics derives RED metrics from the ended spans and records them with the given meter provider:
+	the number of spans, the number of spans with error status and a duration histogram.
+	The metrics are keyed by span name, kind and status code, plus the given span attribute keys as dimensions.
+	This gives service metrics even when only tracing is instrumented.
+	Only the sampled spans are recorded by the SDK, so the metrics only cover them: with a ratio-based
+	sampler, the rate and errors are undercounted by the sampling rate and must be scaled accordingly,
+	and the spans dropped by the sampler are missing from the duration histogram.
+	Use the AlwaysOn sampler for exact metrics, and WithSpanVolumeMetrics to count the started spans.
+
+Example
+
+	provider, err := trace.NewProvider(trace.WithSpanMetrics(meterProvider, "tyk.api.id", "http.status_code"))
+	if err != nil {
+		panic(err)
+	}
+*/
+func WithSpanMetrics(mp metric.MeterProvider, dimensions ...string) Option {
+	return &opts{
+		fn: func(tp *traceProvider) {
+			tp.spanMetrics = &spanMetricsConfig{
+				meterProvider: mp,
+				dimensions:    dimensions,
+			}
+		},
+	}
+}
+
+/*
+	WithSpanVolumeMetrics counts the started, ended and sampled spans and records them with the given meter provider,
+	as the tyk.otel.spans.started, tyk.otel.spans.ended and tyk.otel.spans.sampled counters.
+	This gives operators visibility into the span production rate of each gateway.
+
+Example
+
+	provider, err := trace.NewProvider(trace.WithSpanVolumeMetrics(meterProvider))
+	if err != nil {
+		panic(err)
+	}
+*/
+func WithSpanVolumeMetrics(mp metric.MeterProvider) Option {
+	return &opts{
+		fn: func(tp *traceProvider) {
+			tp.spanVolumeMeterProvider = mp
+		},
+	}
+}
+
+/*
+	WithHTTPJSONRetryMetrics counts the export retries of the "http" exporters with the "json" encoding, and
+	records them with the given meter provider as the tyk.otel.export.http_json.retries counter. Only these
+	exporters are covered: the "grpc" exporters and the "http" exporters with the "protobuf" encoding retry
+	within the OpenTelemetry SDK, which doesn't expose its retries.
+
+Example
+
+	provider, err := trace.NewProvider(trace.WithHTTPJSONRetryMetrics(meterProvider))
+	if err != nil {
+		panic(err)
+	}
+*/
+func WithHTTPJSONRetryMetrics(mp metric.MeterProvider) Option {
+	return &opts{
+		fn: func(tp *traceProvider) {
+			tp.httpJSONRetriesMeterProvider = mp
+		},
+	}
+}
+
+/*
+	WithClock sets the clock used for the spans start, end and event timestamps, instead of the system time.
+	It's useful for deterministic duration assertions in tests, or for platforms with coarse timers.
+	Timestamps explicitly set with trace.WithTimestamp take precedence over the clock.
+
+Example
+
+	provider, err := trace.NewProvider(trace.WithClock(myClock))
+	if err != nil {
+		panic(err)
+	}
+*/
+func WithClock(clock Clock) Option {
+	return &opts{
+		fn: func(tp *traceProvider) {
+			tp.clock = clock
+		},
+	}
+}
+
+/*
+	WithClockOffset shifts the timestamps of the exported spans by the offset returned by the given function,
+	so hybrid gateways with skewed clocks don't produce negative-duration traces in the backend.
+	The function is called on every export, so the offset can be updated at runtime,
+	e.g. from the control plane or an NTP query. It must be safe for concurrent use.
+
+Example
+
+	var offset atomic.Int64 // nanoseconds, updated from the control plane
+	provider, err := trace.NewProvider(trace.WithClockOffset(func() time.Duration {
+		return time.Duration(offset.Load())
+	}))
+	if err != nil {
+		panic(err)
+	}
+*/
+func WithClockOffset(offset func() time.Duration) Option {
+	return &opts{
+		fn: func(tp *traceProvider) {
+			tp.clockOffset = offset
+		},
+	}
+}
+
+/*
+	WithSpanProcessor registers an additional span processor in the tracer provider.
+	The processors are called in the order they are registered, before the export pipelines.
+	It can be used multiple times to add several processors.
+
+Example
+
+	provider, err := trace.NewProvider(trace.WithSpanProcessor(trace.EnrichOnStart(enrich)))
+	if err != nil {
+		panic(err)
+	}
+*/
+func WithSpanProcessor(processor sdktrace.SpanProcessor) Option {
+	return &opts{
+		fn: func(tp *traceProvider) {
+			tp.spanProcessors = append(tp.spanProcessors, processor)
+		},
+	}
+}
+
+/*
+	WithEventListener registers a listener for the state changes of the provider: the health
+	changes of the exporters, the shutdown of the provider and the configuration reloads
+	done through a SwappableProvider. It can be used multiple times to add several listeners.
+
+Example
+
+	events := make(chan trace.Event, 16)
+
+	provider, err := trace.NewProvider(trace.WithEventListener(func(event trace.Event) {
+		select {
+		case events <- event:
+		default:
+		}
+	}))
+	if err != nil {
+		panic(err)
+	}
+*/
+func WithEventListener(listener EventListener) Option {
+	return &opts{
+		fn: func(tp *traceProvider) {
+			if tp.events == nil {
+				tp.events = &eventBus{}
+			}
+
+			tp.events.subscribe(listener)
+		},
+	}
+}
+
+/*
+	WithDryRun validates the configuration instead of creating the provider: the resource,
+	the exporters with their TLS files and endpoints, the sampler and the propagator.
+	No connection is opened and the OpenTelemetry globals are not set.
+	NewProvider fills the given report and returns a noop provider with the validation errors joined.
+
+Example
+
+	report := &trace.DryRunReport{}
+
+	_, err := trace.NewProvider(trace.WithConfig(cfg), trace.WithDryRun(report))
+	if err != nil {
+		for _, pipeline := range report.Pipelines {
+			fmt.Println(pipeline.Exporter, pipeline.Endpoint, pipeline.Err)
+		}
+	}
+*/
+func WithDryRun(report *DryRunReport) Option {
+	return &opts{
+		fn: func(tp *traceProvider) {
+			tp.dryRunReport = report
+		},
+	}
+}
+
+/*
+	WithGRPCDialer sets the dialer used by the "grpc" exporters to connect to the collector,
+	for deployments reaching it through a bastion tunnel or a custom proxy.
+	It takes precedence over the socks5_proxy config.
+
+Example
+
+	dialer := func(ctx context.Context, address string) (net.Conn, error) {
+		return tunnel.DialContext(ctx, "tcp", address)
+	}
+
+	provider, err := trace.NewProvider(trace.WithGRPCDialer(dialer))
+	if err != nil {
+		panic(err)
+	}
+*/
+func WithGRPCDialer(dialer Dialer) Option {
+	return &opts{
+		fn: func(tp *traceProvider) {
+			tp.grpcDialer = dialer
+		},
+	}
+}
+
+/*
+	WithShutdownOnContextDone shuts the provider down when the context set with WithContext is done,
+	for applications tying the provider lifecycle to their own context. The exports in flight are
+	cancelled with the context, and the spans that are not exported yet are dropped.
+
+Example
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	provider, err := trace.NewProvider(trace.WithContext(ctx), trace.WithShutdownOnContextDone())
+	if err != nil {
+		panic(err)
+	}
+*/
+func WithShutdownOnContextDone() Option {
+	return &opts{
+		fn: func(tp *traceProvider) {
+			tp.shutdownOnContextDone = true
+		},
+	}
+}
@@ -2,11 +2,16 @@ package trace
 
 import (
 	"context"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/TykTechnologies/opentelemetry/config"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 func Test_WithLogger(t *testing.T) {
@@ -72,6 +77,13 @@ func Test_WithServiceVersion(t *testing.T) {
 	assert.Equal(t, "v1", tp.resources.version)
 }
 
+func Test_WithSchemaURL(t *testing.T) {
+	tp := &traceProvider{}
+	WithSchemaURL("https://opentelemetry.io/schemas/1.21.0").apply(tp)
+
+	assert.Equal(t, "https://opentelemetry.io/schemas/1.21.0", tp.resources.schemaURL)
+}
+
 func Test_WithHostDetector(t *testing.T) {
 	tp := &traceProvider{}
 	WithHostDetector().apply(tp)
@@ -101,3 +113,110 @@ func Test_WithCustomResourceAttributes(t *testing.T) {
 
 	assert.Len(t, tp.resources.customAttrs, 1)
 }
+
+func Test_WithResourceDetector(t *testing.T) {
+	tp := &traceProvider{}
+	detector := resource.StringDetector("", "key", func() (string, error) {
+		return "value", nil
+	})
+
+	WithResourceDetector(detector).apply(tp)
+	WithResourceDetector(detector).apply(tp)
+
+	assert.Len(t, tp.resources.detectors, 2)
+}
+
+func Test_WithSpanMetrics(t *testing.T) {
+	tp := &traceProvider{}
+	mp := noop.NewMeterProvider()
+
+	WithSpanMetrics(mp, "tyk.api.id").apply(tp)
+
+	assert.NotNil(t, tp.spanMetrics)
+	assert.Equal(t, mp, tp.spanMetrics.meterProvider)
+	assert.Equal(t, []string{"tyk.api.id"}, tp.spanMetrics.dimensions)
+}
+
+func Test_WithSpanVolumeMetrics(t *testing.T) {
+	tp := &traceProvider{}
+	mp := noop.NewMeterProvider()
+
+	WithSpanVolumeMetrics(mp).apply(tp)
+
+	assert.Equal(t, mp, tp.spanVolumeMeterProvider)
+}
+
+func Test_WithHTTPJSONRetryMetrics(t *testing.T) {
+	tp := &traceProvider{}
+	mp := noop.NewMeterProvider()
+
+	WithHTTPJSONRetryMetrics(mp).apply(tp)
+
+	assert.Equal(t, mp, tp.httpJSONRetriesMeterProvider)
+}
+
+func Test_WithClock(t *testing.T) {
+	tp := &traceProvider{}
+	clock := &testClock{}
+
+	WithClock(clock).apply(tp)
+
+	assert.Equal(t, clock, tp.clock)
+}
+
+func Test_WithClockOffset(t *testing.T) {
+	tp := &traceProvider{}
+
+	WithClockOffset(func() time.Duration { return time.Second }).apply(tp)
+
+	assert.NotNil(t, tp.clockOffset)
+	assert.Equal(t, time.Second, tp.clockOffset())
+}
+
+func Test_WithSpanProcessor(t *testing.T) {
+	tp := &traceProvider{}
+	first := EnrichOnStart(func(context.Context, sdktrace.ReadWriteSpan) {})
+	second := EnrichOnStart(func(context.Context, sdktrace.ReadWriteSpan) {})
+
+	WithSpanProcessor(first).apply(tp)
+	WithSpanProcessor(second).apply(tp)
+
+	assert.Equal(t, []sdktrace.SpanProcessor{first, second}, tp.spanProcessors)
+}
+
+func Test_WithEventListener(t *testing.T) {
+	tp := &traceProvider{}
+	received := []EventType{}
+
+	WithEventListener(func(event Event) { received = append(received, event.Type) }).apply(tp)
+	WithEventListener(func(event Event) { received = append(received, event.Type) }).apply(tp)
+
+	tp.publish(Event{Type: ProviderShutdown})
+
+	assert.Equal(t, []EventType{ProviderShutdown, ProviderShutdown}, received)
+}
+
+func Test_WithDryRun(t *testing.T) {
+	tp := &traceProvider{}
+	report := &DryRunReport{}
+
+	WithDryRun(report).apply(tp)
+
+	assert.Equal(t, report, tp.dryRunReport)
+}
+
+func Test_WithGRPCDialer(t *testing.T) {
+	tp := &traceProvider{}
+
+	WithGRPCDialer(func(context.Context, string) (net.Conn, error) { return nil, nil }).apply(tp)
+
+	assert.NotNil(t, tp.grpcDialer)
+}
+
+func Test_WithShutdownOnContextDone(t *testing.T) {
+	tp := &traceProvider{}
+
+	WithShutdownOnContextDone().apply(tp)
+
+	assert.True(t, tp.shutdownOnContextDone)
+}
@@ -0,0 +1,131 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// pipelinesFactory creates one span processor per configured pipeline.
+// The first processor always belongs to the main exporter config, followed by
+// the processors of the additional pipelines in the same order they were configured.
+// If clockOffset is not nil, the timestamps of the exported spans are shifted by the offset it returns.
+// If events is not nil, the health changes of the exporters are published to it.
+// If dialer is not nil, the "grpc" exporters connect to the collector with it.
+// If retries is not nil, the export retries of the "http" exporters with the "json" encoding are counted with it.
+// If cancelExports is set, the exports are cancelled when ctx is done, otherwise they outlive it
+// so the spans are still flushed by a Shutdown called after ctx is done.
+func pipelinesFactory(ctx context.Context, cfg *config.OpenTelemetry, logger Logger,
+	clockOffset func() time.Duration, events *eventBus, dialer Dialer,
+	retries metric.Int64Counter, cancelExports bool) ([]sdktrace.SpanProcessor, error) {
+	pipelineCfgs := []*config.OpenTelemetry{cfg}
+	for _, pipeline := range cfg.Pipelines {
+		pipelineCfgs = append(pipelineCfgs, pipelineConfig(cfg, pipeline))
+	}
+
+	// the span processor and batch settings are validated first, so an invalid one does not leave
+	// the exporters opened
+	for i, pipelineCfg := range pipelineCfgs {
+		if err := validateSpanProcessor(pipelineCfg); err != nil {
+			if i == 0 {
+				return nil, err
+			}
+
+			return nil, fmt.Errorf("pipeline %d: %w", i-1, err)
+		}
+	}
+
+	processors := make([]sdktrace.SpanProcessor, 0, len(pipelineCfgs))
+
+	for i, pipelineCfg := range pipelineCfgs {
+		exporter, err := exporterFactory(ctx, pipelineCfg, dialer, retries)
+		if err != nil {
+			// release the exporters that were already created
+			for _, processor := range processors {
+				_ = processor.Shutdown(ctx)
+			}
+
+			if i == 0 {
+				return nil, err
+			}
+
+			return nil, fmt.Errorf("pipeline %d: %w", i-1, err)
+		}
+
+		// the panics are recovered first, so the other wrappers see them as failed exports
+		exporter = newRecoverExporter(exporter, logger)
+
+		// the exports in flight are cancelled with the root context when the provider is shut down with it
+		if cancelExports && ctx.Done() != nil {
+			exporter = newContextExporter(exporter, ctx)
+		}
+
+		if cfg.AttributeBudget.MaxSpanBytes > 0 || cfg.AttributeBudget.MaxTraceBytes > 0 {
+			exporter = newAttributeBudgetExporter(exporter, cfg.AttributeBudget.MaxSpanBytes,
+				cfg.AttributeBudget.MaxTraceBytes)
+		}
+
+		if cfg.EventLimit.MaxEventsPerSpan > 0 {
+			exporter = newEventLimitExporter(exporter, cfg.EventLimit.MaxEventsPerSpan)
+		}
+
+		if clockOffset != nil {
+			exporter = newClockOffsetExporter(exporter, clockOffset)
+		}
+
+		// the health is tracked before the load shedding, which hides the failures while dropping the spans
+		if events != nil {
+			exporter = newHealthExporter(exporter, i, events)
+		}
+
+		if cfg.LoadShedding.Enabled {
+			exporter = newLoadSheddingExporter(exporter, cfg.LoadShedding.MaxConsecutiveFailures,
+				time.Duration(cfg.LoadShedding.CoolDown)*time.Second, logger)
+		}
+
+		processor, err := spanProcessorFactory(pipelineCfg.SpanProcessorType, exporter, pipelineCfg.Batch)
+		if err != nil {
+			_ = exporter.Shutdown(ctx)
+
+			for _, processor := range processors {
+				_ = processor.Shutdown(ctx)
+			}
+
+			if i == 0 {
+				return nil, err
+			}
+
+			return nil, fmt.Errorf("pipeline %d: %w", i-1, err)
+		}
+
+		processors = append(processors, processor)
+	}
+
+	return processors, nil
+}
+
+// pipelineConfig returns a copy of the main config with the exporter settings
+// replaced by the ones from the given pipeline.
+func pipelineConfig(cfg *config.OpenTelemetry, pipeline config.Pipeline) *config.OpenTelemetry {
+	pipelineCfg := *cfg
+
+	pipelineCfg.Exporter = pipeline.Exporter
+	pipelineCfg.Endpoint = pipeline.Endpoint
+	pipelineCfg.Headers = pipeline.Headers
+	pipelineCfg.ConnectionTimeout = pipeline.ConnectionTimeout
+	pipelineCfg.HTTPEncoding = pipeline.HTTPEncoding
+	pipelineCfg.SpanProcessorType = pipeline.SpanProcessorType
+	pipelineCfg.TLS = pipeline.TLS
+	pipelineCfg.Pipelines = nil
+
+	// the pipelines without batch settings inherit the main ones
+	if pipeline.Batch != (config.Batch{}) {
+		pipelineCfg.Batch = pipeline.Batch
+	}
+
+	return &pipelineCfg
+}
@@ -0,0 +1,194 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PipelinesFactory(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name               string
+		givenCfg           *config.OpenTelemetry
+		expectedProcessors int
+		expectedErr        string
+	}{
+		{
+			name: "main exporter only",
+			givenCfg: &config.OpenTelemetry{
+				Exporter:          "http",
+				ConnectionTimeout: 1,
+			},
+			expectedProcessors: 1,
+		},
+		{
+			name: "main exporter and stdout pipeline",
+			givenCfg: &config.OpenTelemetry{
+				Exporter:          "http",
+				ConnectionTimeout: 1,
+				Pipelines: []config.Pipeline{
+					{
+						Exporter:          "stdout",
+						SpanProcessorType: "simple",
+					},
+				},
+			},
+			expectedProcessors: 2,
+		},
+		{
+			name: "main exporter with attribute budget",
+			givenCfg: &config.OpenTelemetry{
+				Exporter:          "http",
+				ConnectionTimeout: 1,
+				AttributeBudget: config.AttributeBudget{
+					MaxSpanBytes: 1024,
+				},
+			},
+			expectedProcessors: 1,
+		},
+		{
+			name: "invalid main exporter",
+			givenCfg: &config.OpenTelemetry{
+				Exporter: "invalid",
+			},
+			expectedErr: "invalid exporter type: invalid",
+		},
+		{
+			name: "invalid pipeline exporter",
+			givenCfg: &config.OpenTelemetry{
+				Exporter:          "http",
+				ConnectionTimeout: 1,
+				Pipelines: []config.Pipeline{
+					{
+						Exporter: "stdout",
+					},
+					{
+						Exporter: "invalid",
+					},
+				},
+			},
+			expectedErr: "pipeline 1: invalid exporter type: invalid",
+		},
+		{
+			name: "invalid main batch config",
+			givenCfg: &config.OpenTelemetry{
+				Exporter:          "http",
+				ConnectionTimeout: 1,
+				Batch:             config.Batch{Size: 4096},
+			},
+			expectedErr: "invalid batch config: size 4096 exceeds the queue size 2048",
+		},
+		{
+			name: "invalid pipeline batch config",
+			givenCfg: &config.OpenTelemetry{
+				Exporter:          "http",
+				ConnectionTimeout: 1,
+				Pipelines: []config.Pipeline{
+					{
+						Exporter: "stdout",
+						Batch:    config.Batch{Timeout: 120000},
+					},
+				},
+			},
+			expectedErr: "pipeline 0: invalid batch config: timeout 120000ms exceeds 60000ms",
+		},
+		{
+			name: "unregistered main span processor",
+			givenCfg: &config.OpenTelemetry{
+				Exporter:          "http",
+				ConnectionTimeout: 1,
+				SpanProcessorType: "bacth",
+			},
+			expectedErr: "invalid span processor type: bacth",
+		},
+		{
+			name: "unregistered pipeline span processor",
+			givenCfg: &config.OpenTelemetry{
+				Exporter:          "http",
+				ConnectionTimeout: 1,
+				Pipelines: []config.Pipeline{
+					{
+						Exporter:          "stdout",
+						SpanProcessorType: "mpsc",
+					},
+				},
+			},
+			expectedErr: "pipeline 0: invalid span processor type: mpsc",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			tc.givenCfg.Endpoint = server.URL
+
+			processors, err := pipelinesFactory(context.Background(), tc.givenCfg, &noopLogger{}, nil, nil, nil, nil, false)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				assert.Nil(t, processors)
+
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, processors, tc.expectedProcessors)
+		})
+	}
+}
+
+func Test_PipelineConfig(t *testing.T) {
+	cfg := &config.OpenTelemetry{
+		Enabled:           true,
+		Exporter:          "grpc",
+		Endpoint:          "localhost:4317",
+		ResourceName:      "tyk",
+		SpanProcessorType: "batch",
+		Pipelines: []config.Pipeline{
+			{
+				Exporter: "stdout",
+			},
+		},
+	}
+
+	pipelineCfg := pipelineConfig(cfg, config.Pipeline{
+		Exporter:          "http",
+		Endpoint:          "collector:4318",
+		Headers:           map[string]string{"key": "value"},
+		ConnectionTimeout: 5,
+		HTTPEncoding:      "json",
+		SpanProcessorType: "simple",
+	})
+
+	assert.Equal(t, "http", pipelineCfg.Exporter)
+	assert.Equal(t, "collector:4318", pipelineCfg.Endpoint)
+	assert.Equal(t, map[string]string{"key": "value"}, pipelineCfg.Headers)
+	assert.Equal(t, 5, pipelineCfg.ConnectionTimeout)
+	assert.Equal(t, "json", pipelineCfg.HTTPEncoding)
+	assert.Equal(t, "simple", pipelineCfg.SpanProcessorType)
+	assert.Equal(t, "tyk", pipelineCfg.ResourceName)
+	assert.Nil(t, pipelineCfg.Pipelines)
+
+	// the main config must not be modified
+	assert.Equal(t, "grpc", cfg.Exporter)
+	assert.Len(t, cfg.Pipelines, 1)
+
+	t.Run("batch settings", func(t *testing.T) {
+		cfg := &config.OpenTelemetry{Batch: config.Batch{Size: 100}}
+
+		inherited := pipelineConfig(cfg, config.Pipeline{Exporter: "stdout"})
+		assert.Equal(t, config.Batch{Size: 100}, inherited.Batch)
+
+		overridden := pipelineConfig(cfg, config.Pipeline{Exporter: "stdout", Batch: config.Batch{QueueSize: 4096}})
+		assert.Equal(t, config.Batch{QueueSize: 4096}, overridden.Batch)
+	})
+}
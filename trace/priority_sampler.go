@@ -0,0 +1,87 @@
+package trace
+
+import (
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// SamplingPriorityKey is the span attribute and baggage entry used to force the sampling decision:
+// values greater than 0 force the span to be sampled, and 0 forces it to be dropped.
+const SamplingPriorityKey = "sampling.priority"
+
+// prioritySampler wraps a sampler and honours the sampling priority set in the span start
+// attributes or in the baggage, following the Datadog-style conventions.
+// When no priority is set, the decision is delegated to the wrapped sampler.
+type prioritySampler struct {
+	sampler sdktrace.Sampler
+}
+
+var _ sdktrace.Sampler = &prioritySampler{}
+
+func newPrioritySampler(sampler sdktrace.Sampler) sdktrace.Sampler {
+	return &prioritySampler{sampler: sampler}
+}
+
+func (s *prioritySampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	priority, ok := samplingPriority(p)
+	if !ok {
+		return s.sampler.ShouldSample(p)
+	}
+
+	decision := sdktrace.Drop
+	if priority > 0 {
+		decision = sdktrace.RecordAndSample
+	}
+
+	return sdktrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: oteltrace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+func (s *prioritySampler) Description() string {
+	return "PrioritySampler{" + s.sampler.Description() + "}"
+}
+
+// samplingPriority returns the sampling priority of the span. The start attributes take
+// precedence over the baggage. Negative or invalid values are ignored.
+func samplingPriority(p sdktrace.SamplingParameters) (int64, bool) {
+	for _, attr := range p.Attributes {
+		if attr.Key != SamplingPriorityKey {
+			continue
+		}
+
+		switch attr.Value.Type() {
+		case attribute.INT64:
+			return validPriority(attr.Value.AsInt64())
+		case attribute.FLOAT64:
+			return validPriority(int64(attr.Value.AsFloat64()))
+		case attribute.STRING:
+			return parsePriority(attr.Value.AsString())
+		}
+	}
+
+	member := baggage.FromContext(p.ParentContext).Member(SamplingPriorityKey)
+	if member.Key() == "" {
+		return 0, false
+	}
+
+	return parsePriority(member.Value())
+}
+
+func parsePriority(value string) (int64, bool) {
+	priority, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return validPriority(priority)
+}
+
+func validPriority(priority int64) (int64, bool) {
+	return priority, priority >= 0
+}
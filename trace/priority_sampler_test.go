@@ -0,0 +1,144 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func Test_PrioritySampler(t *testing.T) {
+	baggageCtx := func(value string) context.Context {
+		member, err := baggage.NewMemberRaw(SamplingPriorityKey, value)
+		assert.NoError(t, err)
+
+		bag, err := baggage.New(member)
+		assert.NoError(t, err)
+
+		return baggage.ContextWithBaggage(context.Background(), bag)
+	}
+
+	tcs := []struct {
+		testName         string
+		sampler          sdktrace.Sampler
+		ctx              context.Context
+		attributes       []attribute.KeyValue
+		expectedDecision sdktrace.SamplingDecision
+	}{
+		{
+			testName:         "no priority delegates to the sampler",
+			sampler:          sdktrace.NeverSample(),
+			ctx:              context.Background(),
+			expectedDecision: sdktrace.Drop,
+		},
+		{
+			testName:         "positive priority attribute forces sampling",
+			sampler:          sdktrace.NeverSample(),
+			ctx:              context.Background(),
+			attributes:       []attribute.KeyValue{attribute.Int(SamplingPriorityKey, 1)},
+			expectedDecision: sdktrace.RecordAndSample,
+		},
+		{
+			testName:         "zero priority attribute forces drop",
+			sampler:          sdktrace.AlwaysSample(),
+			ctx:              context.Background(),
+			attributes:       []attribute.KeyValue{attribute.Int(SamplingPriorityKey, 0)},
+			expectedDecision: sdktrace.Drop,
+		},
+		{
+			testName:         "string priority attribute",
+			sampler:          sdktrace.NeverSample(),
+			ctx:              context.Background(),
+			attributes:       []attribute.KeyValue{attribute.String(SamplingPriorityKey, "2")},
+			expectedDecision: sdktrace.RecordAndSample,
+		},
+		{
+			testName:         "float priority attribute",
+			sampler:          sdktrace.AlwaysSample(),
+			ctx:              context.Background(),
+			attributes:       []attribute.KeyValue{attribute.Float64(SamplingPriorityKey, 0)},
+			expectedDecision: sdktrace.Drop,
+		},
+		{
+			testName:         "negative priority is ignored",
+			sampler:          sdktrace.AlwaysSample(),
+			ctx:              context.Background(),
+			attributes:       []attribute.KeyValue{attribute.Int(SamplingPriorityKey, -1)},
+			expectedDecision: sdktrace.RecordAndSample,
+		},
+		{
+			testName:         "invalid priority is ignored",
+			sampler:          sdktrace.NeverSample(),
+			ctx:              context.Background(),
+			attributes:       []attribute.KeyValue{attribute.String(SamplingPriorityKey, "high")},
+			expectedDecision: sdktrace.Drop,
+		},
+		{
+			testName:         "positive priority baggage forces sampling",
+			sampler:          sdktrace.NeverSample(),
+			ctx:              baggageCtx("1"),
+			expectedDecision: sdktrace.RecordAndSample,
+		},
+		{
+			testName:         "zero priority baggage forces drop",
+			sampler:          sdktrace.AlwaysSample(),
+			ctx:              baggageCtx("0"),
+			expectedDecision: sdktrace.Drop,
+		},
+		{
+			testName:         "attribute takes precedence over baggage",
+			sampler:          sdktrace.AlwaysSample(),
+			ctx:              baggageCtx("1"),
+			attributes:       []attribute.KeyValue{attribute.Int(SamplingPriorityKey, 0)},
+			expectedDecision: sdktrace.Drop,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			sampler := newPrioritySampler(tc.sampler)
+
+			result := sampler.ShouldSample(sdktrace.SamplingParameters{
+				ParentContext: tc.ctx,
+				TraceID:       oteltrace.TraceID{0x01},
+				Attributes:    tc.attributes,
+			})
+
+			assert.Equal(t, tc.expectedDecision, result.Decision)
+		})
+	}
+}
+
+func Test_PrioritySamplerDescription(t *testing.T) {
+	sampler := newPrioritySampler(sdktrace.AlwaysSample())
+
+	assert.Equal(t, "PrioritySampler{AlwaysOnSampler}", sampler.Description())
+}
+
+func Test_PrioritySamplingThroughHTTPHandler(t *testing.T) {
+	t.Run("priority forcing the sampling", func(t *testing.T) {
+		spans := handledRequestSpans(t, config.Sampling{Type: config.ALWAYSOFF, Priority: true},
+			http.Header{"Baggage": []string{SamplingPriorityKey + "=1"}})
+
+		assert.Len(t, spans, 1)
+	})
+
+	t.Run("priority forcing the drop", func(t *testing.T) {
+		spans := handledRequestSpans(t, config.Sampling{Type: config.ALWAYSON, Priority: true},
+			http.Header{"Baggage": []string{SamplingPriorityKey + "=0"}})
+
+		assert.Empty(t, spans)
+	})
+
+	t.Run("no priority", func(t *testing.T) {
+		spans := handledRequestSpans(t, config.Sampling{Type: config.ALWAYSON, Priority: true}, http.Header{})
+
+		assert.Len(t, spans, 1)
+	})
+}
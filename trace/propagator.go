@@ -1,21 +1,164 @@
 package trace
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"github.com/TykTechnologies/opentelemetry/config"
 	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 func propagatorFactory(cfg *config.OpenTelemetry) (propagation.TextMapPropagator, error) {
+	propagators := []propagation.TextMapPropagator{}
+
 	switch cfg.ContextPropagation {
 	case config.PROPAGATOR_B3:
-		propagator := b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader))
-		return propagator, nil
+		propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
 	case config.PROPAGATOR_TRACECONTEXT:
-		return propagation.TraceContext{}, nil
+		propagators = append(propagators, propagation.TraceContext{})
+
+		if cfg.Sampling.MeshSampled {
+			propagators = append(propagators, meshSampledPropagator{})
+		}
 	default:
 		return nil, fmt.Errorf("invalid context propagation type: %s", cfg.ContextPropagation)
 	}
+
+	// the debug and priority decisions of the callers can be sent as baggage entries,
+	// the other entries of the baggage are neither extracted nor propagated
+	samplingKeys := []string{}
+	if cfg.Sampling.Debug {
+		samplingKeys = append(samplingKeys, DebugBaggageKey)
+	}
+
+	if cfg.Sampling.Priority {
+		samplingKeys = append(samplingKeys, SamplingPriorityKey)
+	}
+
+	if len(samplingKeys) > 0 {
+		propagators = append(propagators, samplingBaggagePropagator{keys: samplingKeys})
+	}
+
+	if cfg.Sampling.Debug {
+		propagators = append(propagators, debugPropagator{})
+	}
+
+	if len(propagators) == 1 {
+		return propagators[0], nil
+	}
+
+	return propagation.NewCompositeTextMapPropagator(propagators...), nil
+}
+
+// baggageHeader is the header of the W3C baggage.
+const baggageHeader = "baggage"
+
+// samplingBaggagePropagator extracts the given entries of the incoming baggage, used by the callers
+// to send their sampling decisions, and adds them to the baggage of the context. Unlike
+// propagation.Baggage, the other entries are ignored, so the baggage sent by the clients isn't
+// forwarded to the upstreams. It doesn't inject anything, the sampling decisions are propagated
+// downstream with the sampled flag of the span context.
+type samplingBaggagePropagator struct {
+	keys []string
+}
+
+var _ propagation.TextMapPropagator = samplingBaggagePropagator{}
+
+func (samplingBaggagePropagator) Inject(context.Context, propagation.TextMapCarrier) {}
+
+func (p samplingBaggagePropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	if carrier.Get(baggageHeader) == "" {
+		return ctx
+	}
+
+	incoming := baggage.FromContext(propagation.Baggage{}.Extract(context.Background(), carrier))
+	bag := baggage.FromContext(ctx)
+
+	for _, key := range p.keys {
+		member := incoming.Member(key)
+		if member.Key() == "" {
+			continue
+		}
+
+		if b, err := bag.SetMember(member); err == nil {
+			bag = b
+		}
+	}
+
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+func (samplingBaggagePropagator) Fields() []string {
+	return []string{baggageHeader}
+}
+
+const (
+	b3SingleHeader  = "b3"
+	b3SampledHeader = "X-B3-Sampled"
+	b3FlagsHeader   = "X-B3-Flags"
+)
+
+// meshSampledPropagator merges the B3 sampling flags sent by service mesh sidecars into the
+// span context extracted by the previous propagators, so their sampling decision is honoured
+// when using the tracecontext propagator. It doesn't inject anything.
+type meshSampledPropagator struct{}
+
+var _ propagation.TextMapPropagator = meshSampledPropagator{}
+
+func (meshSampledPropagator) Inject(context.Context, propagation.TextMapCarrier) {}
+
+func (meshSampledPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	sc := oteltrace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return ctx
+	}
+
+	sampled, ok := meshSampledFlag(carrier)
+	if !ok {
+		return ctx
+	}
+
+	flags := sc.TraceFlags().WithSampled(sampled)
+
+	return oteltrace.ContextWithRemoteSpanContext(ctx, sc.WithTraceFlags(flags))
+}
+
+func (meshSampledPropagator) Fields() []string {
+	return []string{b3SingleHeader, b3SampledHeader, b3FlagsHeader}
+}
+
+// meshSampledFlag returns the sampling decision of the B3 headers, if any.
+// The debug flag implies the span is sampled.
+func meshSampledFlag(carrier propagation.TextMapCarrier) (sampled, ok bool) {
+	if carrier.Get(b3FlagsHeader) == "1" {
+		return true, true
+	}
+
+	switch strings.ToLower(carrier.Get(b3SampledHeader)) {
+	case "1", "true":
+		return true, true
+	case "0", "false":
+		return false, true
+	}
+
+	// the sampling state is the only field of the single header, or the third one
+	single := strings.Split(carrier.Get(b3SingleHeader), "-")
+
+	state := single[0]
+	if len(single) >= 3 {
+		state = single[2]
+	}
+
+	switch state {
+	case "1", "d":
+		return true, true
+	case "0":
+		return false, true
+	}
+
+	return false, false
 }
@@ -1,13 +1,16 @@
 package trace
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/TykTechnologies/opentelemetry/config"
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func Test_PropagatorFactory(t *testing.T) {
@@ -41,6 +44,54 @@ func Test_PropagatorFactory(t *testing.T) {
 			expectedPropagator: propagation.TraceContext{},
 			expectedErr:        nil,
 		},
+		{
+			name: "tracecontext propagator with mesh sampled flags",
+			givenConfig: &config.OpenTelemetry{
+				ContextPropagation: config.PROPAGATOR_TRACECONTEXT,
+				Sampling: config.Sampling{
+					MeshSampled: true,
+				},
+			},
+			expectedPropagator: propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, meshSampledPropagator{}),
+			expectedErr:        nil,
+		},
+		{
+			name: "debug sampling",
+			givenConfig: &config.OpenTelemetry{
+				ContextPropagation: config.PROPAGATOR_B3,
+				Sampling: config.Sampling{
+					Debug: true,
+				},
+			},
+			expectedPropagator: propagation.NewCompositeTextMapPropagator(b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)),
+				samplingBaggagePropagator{keys: []string{DebugBaggageKey}}, debugPropagator{}),
+			expectedErr: nil,
+		},
+		{
+			name: "priority sampling",
+			givenConfig: &config.OpenTelemetry{
+				ContextPropagation: config.PROPAGATOR_TRACECONTEXT,
+				Sampling: config.Sampling{
+					Priority: true,
+				},
+			},
+			expectedPropagator: propagation.NewCompositeTextMapPropagator(propagation.TraceContext{},
+				samplingBaggagePropagator{keys: []string{SamplingPriorityKey}}),
+			expectedErr: nil,
+		},
+		{
+			name: "debug and priority sampling",
+			givenConfig: &config.OpenTelemetry{
+				ContextPropagation: config.PROPAGATOR_TRACECONTEXT,
+				Sampling: config.Sampling{
+					Debug:    true,
+					Priority: true,
+				},
+			},
+			expectedPropagator: propagation.NewCompositeTextMapPropagator(propagation.TraceContext{},
+				samplingBaggagePropagator{keys: []string{DebugBaggageKey, SamplingPriorityKey}}, debugPropagator{}),
+			expectedErr: nil,
+		},
 	}
 
 	for _, tc := range tcs {
@@ -51,3 +102,124 @@ func Test_PropagatorFactory(t *testing.T) {
 		})
 	}
 }
+
+func Test_MeshSampledPropagator(t *testing.T) {
+	const traceparent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-"
+
+	tcs := []struct {
+		name            string
+		headers         map[string]string
+		expectedValid   bool
+		expectedSampled bool
+	}{
+		{
+			name:            "no mesh headers keeps the traceparent flag",
+			headers:         map[string]string{"traceparent": traceparent + "01"},
+			expectedValid:   true,
+			expectedSampled: true,
+		},
+		{
+			name:            "x-b3-sampled forces sampling",
+			headers:         map[string]string{"traceparent": traceparent + "00", "X-B3-Sampled": "1"},
+			expectedValid:   true,
+			expectedSampled: true,
+		},
+		{
+			name:            "x-b3-sampled forces drop",
+			headers:         map[string]string{"traceparent": traceparent + "01", "X-B3-Sampled": "false"},
+			expectedValid:   true,
+			expectedSampled: false,
+		},
+		{
+			name:            "x-b3-flags debug forces sampling",
+			headers:         map[string]string{"traceparent": traceparent + "00", "X-B3-Flags": "1", "X-B3-Sampled": "0"},
+			expectedValid:   true,
+			expectedSampled: true,
+		},
+		{
+			name:            "b3 single header sampling state only",
+			headers:         map[string]string{"traceparent": traceparent + "00", "b3": "d"},
+			expectedValid:   true,
+			expectedSampled: true,
+		},
+		{
+			name: "b3 single header",
+			headers: map[string]string{
+				"traceparent": traceparent + "01",
+				"b3":          "0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-0",
+			},
+			expectedValid:   true,
+			expectedSampled: false,
+		},
+		{
+			name:            "invalid mesh flag is ignored",
+			headers:         map[string]string{"traceparent": traceparent + "01", "X-B3-Sampled": "maybe"},
+			expectedValid:   true,
+			expectedSampled: true,
+		},
+		{
+			name:          "no span context",
+			headers:       map[string]string{"X-B3-Sampled": "1"},
+			expectedValid: false,
+		},
+	}
+
+	prop := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, meshSampledPropagator{})
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := prop.Extract(context.Background(), propagation.MapCarrier(tc.headers))
+
+			sc := trace.SpanContextFromContext(ctx)
+			assert.Equal(t, tc.expectedValid, sc.IsValid())
+			assert.Equal(t, tc.expectedSampled, sc.IsSampled())
+
+			if tc.expectedValid {
+				assert.True(t, sc.IsRemote())
+				assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", sc.TraceID().String())
+			}
+		})
+	}
+}
+
+func Test_SamplingBaggagePropagator(t *testing.T) {
+	prop := samplingBaggagePropagator{keys: []string{DebugBaggageKey, SamplingPriorityKey}}
+
+	t.Run("only the sampling entries are extracted", func(t *testing.T) {
+		ctx := prop.Extract(context.Background(), propagation.MapCarrier{
+			"baggage": DebugBaggageKey + "=1,user.id=42,session=secret",
+		})
+
+		bag := baggage.FromContext(ctx)
+		assert.Equal(t, 1, bag.Len())
+		assert.Equal(t, "1", bag.Member(DebugBaggageKey).Value())
+	})
+
+	t.Run("existing baggage is kept", func(t *testing.T) {
+		member, err := baggage.NewMemberRaw("tenant", "acme")
+		assert.Nil(t, err)
+
+		bag, err := baggage.New(member)
+		assert.Nil(t, err)
+
+		ctx := prop.Extract(baggage.ContextWithBaggage(context.Background(), bag), propagation.MapCarrier{
+			"baggage": SamplingPriorityKey + "=0,user.id=42",
+		})
+
+		bag = baggage.FromContext(ctx)
+		assert.Equal(t, 2, bag.Len())
+		assert.Equal(t, "acme", bag.Member("tenant").Value())
+		assert.Equal(t, "0", bag.Member(SamplingPriorityKey).Value())
+	})
+
+	t.Run("the baggage isn't injected downstream", func(t *testing.T) {
+		ctx := prop.Extract(context.Background(), propagation.MapCarrier{
+			"baggage": DebugBaggageKey + "=1,user.id=42",
+		})
+
+		carrier := propagation.MapCarrier{}
+		prop.Inject(ctx, carrier)
+
+		assert.Empty(t, carrier)
+	})
+}
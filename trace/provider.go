@@ -2,11 +2,15 @@ package trace
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/TykTechnologies/opentelemetry/config"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 	noopMetricProvider "go.opentelemetry.io/otel/metric/noop"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	oteltrace "go.opentelemetry.io/otel/trace"
@@ -15,12 +19,47 @@ import (
 // Provider is the interface that wraps the basic methods of a tracer provider.
 // If missconfigured or disabled, the provider will return a noop tracer
 type Provider interface {
-	// Shutdown execute the underlying exporter shutdown function
+	// Shutdown execute the underlying exporter shutdown function. It's safe to call it
+	// several times and concurrently, the provider is shut down only once.
 	Shutdown(context.Context) error
 	// Tracer returns a tracer with pre-configured name. It's used to create spans.
 	Tracer() Tracer
 	// Type returns the type of the provider, it can be either "noop" or "otel"
 	Type() string
+	// Enabled returns true if the provider is exporting spans, false if it's a noop provider
+	Enabled() bool
+	// TracerProvider returns the underlying OpenTelemetry tracer provider.
+	// It can be used to wire third-party instrumentation libraries against the same
+	// pipeline without relying on the global tracer provider.
+	TracerProvider() oteltrace.TracerProvider
+}
+
+/*
+	ProviderLifecycle is implemented by the providers of this package on top of Provider.
+	It's a separate interface so the implementations and mocks of Provider outside this package
+	don't have to implement it; the callers type-assert the provider to use it.
+
+Example
+
+	if lifecycle, ok := provider.(trace.ProviderLifecycle); ok {
+		err := lifecycle.ForceFlush(ctx)
+		...
+	}
+*/
+type ProviderLifecycle interface {
+	// ForceFlush exports all the ended spans that have not yet been exported.
+	// It's a noop for disabled providers.
+	ForceFlush(context.Context) error
+	// OnShutdown registers a function called by Shutdown, before the spans are flushed, to close the
+	// resources depending on the provider such as custom processors or bridges. The functions are called
+	// in the order they were registered, and their errors are returned by Shutdown.
+	OnShutdown(func(context.Context) error)
+	// Closed returns true once the provider has been shut down.
+	Closed() bool
+	// EffectiveConfig returns a copy of the configuration used by the provider, with the defaults
+	// applied if it's enabled, e.g. to expose the settings in use on a debug endpoint or in a support
+	// bundle. The secrets are redacted, see config.OpenTelemetry.Redacted.
+	EffectiveConfig() config.OpenTelemetry
 }
 
 type Tracer = oteltrace.Tracer
@@ -30,9 +69,15 @@ const (
 	OTEL_PROVIDER = "otel"
 )
 
+var (
+	_ Provider          = &traceProvider{}
+	_ ProviderLifecycle = &traceProvider{}
+)
+
 type traceProvider struct {
-	traceProvider      oteltrace.TracerProvider
-	providerShutdownFn func(context.Context) error
+	traceProvider        oteltrace.TracerProvider
+	providerShutdownFn   func(context.Context) error
+	providerForceFlushFn func(context.Context) error
 
 	cfg    *config.OpenTelemetry
 	logger Logger
@@ -41,6 +86,36 @@ type traceProvider struct {
 	providerType string
 
 	resources resourceConfig
+
+	spanMetrics *spanMetricsConfig
+
+	spanVolumeMeterProvider metric.MeterProvider
+
+	httpJSONRetriesMeterProvider metric.MeterProvider
+
+	clock       Clock
+	clockOffset func() time.Duration
+
+	spanProcessors []sdktrace.SpanProcessor
+
+	errHandler *errHandler
+
+	events *eventBus
+
+	dryRunReport *DryRunReport
+
+	grpcDialer Dialer
+
+	shutdownHooks shutdownHooks
+	shutdownOnce  sync.Once
+	closed        atomic.Bool
+
+	shutdownOnContextDone bool
+}
+
+type spanMetricsConfig struct {
+	meterProvider metric.MeterProvider
+	dimensions    []string
 }
 
 /*
@@ -63,12 +138,13 @@ type traceProvider struct {
 */
 func NewProvider(opts ...Option) (Provider, error) {
 	provider := &traceProvider{
-		traceProvider:      oteltrace.NewNoopTracerProvider(),
-		providerShutdownFn: nil,
-		logger:             &noopLogger{},
-		cfg:                &config.OpenTelemetry{},
-		ctx:                context.Background(),
-		providerType:       NOOP_PROVIDER,
+		traceProvider:        oteltrace.NewNoopTracerProvider(),
+		providerShutdownFn:   nil,
+		providerForceFlushFn: nil,
+		logger:               &noopLogger{},
+		cfg:                  &config.OpenTelemetry{},
+		ctx:                  context.Background(),
+		providerType:         NOOP_PROVIDER,
 	}
 
 	// apply the given options
@@ -76,14 +152,25 @@ func NewProvider(opts ...Option) (Provider, error) {
 		opt.apply(provider)
 	}
 
+	// move the deprecated config fields to their replacements before the defaults are applied
+	for _, deprecation := range provider.cfg.Migrate() {
+		logWarn(provider.logger, deprecation.String())
+	}
+
 	// set the config defaults - this does not override the config values
 	provider.cfg.SetDefaults()
 
 	// if the provider is not enabled, return a noop provider
 	if !provider.cfg.Enabled {
+		provider.watchContext()
 		return provider, nil
 	}
 
+	// the dry run validates the configuration without opening connections nor setting the globals
+	if provider.dryRunReport != nil {
+		return provider, provider.dryRun(provider.ctx, provider.dryRunReport)
+	}
+
 	// create the resource
 	resource, err := resourceFactory(provider.ctx, provider.cfg.ResourceName, provider.resources)
 	if err != nil {
@@ -91,21 +178,63 @@ func NewProvider(opts ...Option) (Provider, error) {
 		return provider, fmt.Errorf("failed to create resource: %w", err)
 	}
 
-	// create the exporter - here's where connecting to the collector happens
-	exporter, err := exporterFactory(provider.ctx, provider.cfg)
-	if err != nil {
-		provider.logger.Error("failed to create exporter", err)
-		return provider, fmt.Errorf("failed to create exporter: %w", err)
+	// create everything that can fail before the exporters, so an error does not leave them opened
+	var nameProcessor *spanNameProcessor
+	if len(provider.cfg.SpanNameReplacements) > 0 {
+		nameProcessor, err = newSpanNameProcessor(provider.cfg.SpanNameReplacements)
+		if err != nil {
+			provider.logger.Error("failed to create span name processor", err)
+			return provider, fmt.Errorf("failed to create span name processor: %w", err)
+		}
 	}
 
-	// create the span processor - this is what will send the spans to the exporter.
-	spanProcesor := spanProcessorFactory(provider.cfg.SpanProcessorType, exporter)
+	var spanMetricsProcessor sdktrace.SpanProcessor
+	if provider.spanMetrics != nil {
+		spanMetricsProcessor, err = newSpanMetricsProcessor(provider.spanMetrics.meterProvider, provider.spanMetrics.dimensions)
+		if err != nil {
+			provider.logger.Error("failed to create span metrics processor", err)
+			return provider, fmt.Errorf("failed to create span metrics processor: %w", err)
+		}
+	}
 
 	// create the sampler based on the configs
-	samplerType := provider.cfg.Sampling.Type
-	samplingRate := provider.cfg.Sampling.Rate
-	parentBasedSampling := provider.cfg.Sampling.ParentBased
-	sampler := getSampler(samplerType, samplingRate, parentBasedSampling)
+	sampler := samplerFactory(provider.cfg)
+
+	var volume *spanVolume
+	if provider.spanVolumeMeterProvider != nil {
+		volume, err = newSpanVolume(provider.spanVolumeMeterProvider)
+		if err != nil {
+			provider.logger.Error("failed to create span volume metrics", err)
+			return provider, fmt.Errorf("failed to create span volume metrics: %w", err)
+		}
+
+		// the spans dropped by the sampler never reach the span processors, so they're counted by the sampler
+		sampler = volume.sampler(sampler)
+	}
+
+	propagator, err := propagatorFactory(provider.cfg)
+	if err != nil {
+		provider.logger.Error("failed to create context propagator", err)
+		return provider, fmt.Errorf("failed to create context propagator: %w", err)
+	}
+
+	var httpJSONRetries metric.Int64Counter
+	if provider.httpJSONRetriesMeterProvider != nil {
+		httpJSONRetries, err = newHTTPJSONRetriesCounter(provider.httpJSONRetriesMeterProvider)
+		if err != nil {
+			provider.logger.Error("failed to create export retry metrics", err)
+			return provider, fmt.Errorf("failed to create export retry metrics: %w", err)
+		}
+	}
+
+	// create the exporters and their span processors - here's where connecting to the collector happens.
+	// The span processors are what will send the spans to each exporter.
+	spanProcessors, err := pipelinesFactory(provider.ctx, provider.cfg, provider.logger, provider.clockOffset,
+		provider.events, provider.grpcDialer, httpJSONRetries, provider.shutdownOnContextDone)
+	if err != nil {
+		provider.logger.Error("failed to create exporter", err)
+		return provider, fmt.Errorf("failed to create exporter: %w", err)
+	}
 
 	// Create the tracer provider
 	// The tracer provider will use the resource and exporter created previously
@@ -113,25 +242,50 @@ func NewProvider(opts ...Option) (Provider, error) {
 	// The tracer provider must be registered as a global tracer provider
 	// so that any other package can use it
 
-	tracerProvider := sdktrace.NewTracerProvider(
+	tracerProviderOpts := []sdktrace.TracerProviderOption{
 		sdktrace.WithSampler(sampler),
 		sdktrace.WithResource(resource),
-		sdktrace.WithSpanProcessor(spanProcesor),
-	)
+	}
 
-	propagator, err := propagatorFactory(provider.cfg)
-	if err != nil {
-		provider.logger.Error("failed to create context propagator", err)
-		return provider, fmt.Errorf("failed to create context propagator: %w", err)
+	if provider.cfg.AttributeBudget.MaxSpanBytes > 0 {
+		tracerProviderOpts = append(tracerProviderOpts,
+			sdktrace.WithRawSpanLimits(attributeBudgetSpanLimits(provider.cfg.AttributeBudget.MaxSpanBytes)))
+	}
+
+	// the custom processors go before the export pipelines, so their changes on start are exported
+	spanProcessors = append(provider.spanProcessors[:len(provider.spanProcessors):len(provider.spanProcessors)], spanProcessors...)
+
+	if nameProcessor != nil {
+		// the span names are rewritten on start, before any other processor reads them
+		spanProcessors = append([]sdktrace.SpanProcessor{nameProcessor}, spanProcessors...)
+	}
+
+	if spanMetricsProcessor != nil {
+		spanProcessors = append(spanProcessors, spanMetricsProcessor)
+	}
+
+	if volume != nil {
+		spanProcessors = append(spanProcessors, volume.processor())
 	}
 
+	for _, spanProcessor := range spanProcessors {
+		tracerProviderOpts = append(tracerProviderOpts, sdktrace.WithSpanProcessor(spanProcessor))
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(tracerProviderOpts...)
+
 	// set the local tracer provider
 	provider.traceProvider = tracerProvider
 	provider.providerShutdownFn = tracerProvider.Shutdown
+	provider.providerForceFlushFn = tracerProvider.ForceFlush
 	provider.providerType = OTEL_PROVIDER
 
+	if provider.clock != nil {
+		provider.traceProvider = newClockTracerProvider(tracerProvider, provider.clock)
+	}
+
 	// set global otel tracer provider
-	otel.SetTracerProvider(tracerProvider)
+	otel.SetTracerProvider(provider.traceProvider)
 
 	otel.SetMeterProvider(noopMetricProvider.NewMeterProvider())
 
@@ -139,24 +293,152 @@ func NewProvider(opts ...Option) (Provider, error) {
 	otel.SetTextMapPropagator(propagator)
 
 	// set the global otel error handler
-	otel.SetErrorHandler(&errHandler{
-		logger: provider.logger,
-	})
+	provider.errHandler = &errHandler{
+		logger:   provider.logger,
+		interval: time.Duration(provider.cfg.ErrorLogInterval) * time.Second,
+	}
+	otel.SetErrorHandler(provider.errHandler)
+
+	if provider.cfg.WarmUp {
+		provider.warmUp(provider.ctx)
+	}
 
-	provider.logger.Info("Tracer provider initialized successfully")
+	// the endpoint may hold credentials
+	redactedCfg := provider.cfg.Redacted()
+	provider.logger.Info(fmt.Sprintf(
+		"Tracer provider initialized successfully: exporter=%s endpoint=%s tls=%t sampler=%s propagator=%s pipelines=%d",
+		provider.cfg.Exporter,
+		redactedCfg.Endpoint,
+		provider.cfg.TLS.Enabled,
+		sampler.Description(),
+		provider.cfg.ContextPropagation,
+		len(provider.cfg.Pipelines),
+	))
+
+	provider.watchContext()
 
 	return provider, nil
 }
 
+// watchContext shuts the provider down when its context is done, if enabled with WithShutdownOnContextDone.
+func (tp *traceProvider) watchContext() {
+	if !tp.shutdownOnContextDone {
+		return
+	}
+
+	stop := context.AfterFunc(tp.ctx, func() {
+		// the exports are cancelled with the context, so their errors are expected
+		if err := tp.Shutdown(context.Background()); err != nil && !errors.Is(err, tp.ctx.Err()) {
+			tp.logger.Error("failed to shutdown provider", err)
+		}
+	})
+
+	// the watch is released if the provider is shut down first
+	tp.OnShutdown(func(context.Context) error {
+		stop()
+		return nil
+	})
+}
+
+// samplerFactory creates the sampler based on the configs.
+func samplerFactory(cfg *config.OpenTelemetry) sdktrace.Sampler {
+	sampler := getSampler(cfg.Sampling.Type, cfg.Sampling.Rate, cfg.Sampling.ParentBased)
+
+	if cfg.Sampling.Priority {
+		sampler = newPrioritySampler(sampler)
+	}
+
+	// the debug marker takes precedence over the sampling priority
+	if cfg.Sampling.Debug {
+		sampler = newDebugSampler(sampler)
+	}
+
+	return sampler
+}
+
+// warmUp performs a test export on the main exporter and every additional pipeline,
+// logging the failures without interrupting the initialisation.
+func (tp *traceProvider) warmUp(ctx context.Context) {
+	if err := warmUpExporter(ctx, tp.cfg, tp.grpcDialer); err != nil {
+		tp.logger.Error("exporter warm up failed", err)
+	}
+
+	for i, pipeline := range tp.cfg.Pipelines {
+		if err := warmUpExporter(ctx, pipelineConfig(tp.cfg, pipeline), tp.grpcDialer); err != nil {
+			tp.logger.Error(fmt.Sprintf("exporter warm up failed for pipeline %d", i), err)
+		}
+	}
+}
+
+// Shutdown shuts down the provider once: the concurrent calls wait for the first one to complete,
+// and the later calls return nil straight away.
 func (tp *traceProvider) Shutdown(ctx context.Context) error {
+	var err error
+
+	tp.shutdownOnce.Do(func() {
+		err = tp.shutdown(ctx)
+		tp.closed.Store(true)
+	})
+
+	return err
+}
+
+func (tp *traceProvider) shutdown(ctx context.Context) error {
+	// the dependent resources are closed first, so the spans they end are flushed
+	hooksErr := tp.shutdownHooks.run(ctx)
+
 	if tp.providerShutdownFn == nil {
-		return nil
+		return hooksErr
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, time.Duration(tp.cfg.ConnectionTimeout)*time.Second)
 	defer cancel()
 
-	return tp.providerShutdownFn(ctx)
+	err := errors.Join(hooksErr, tp.providerShutdownFn(ctx))
+
+	// log the errors suppressed until now, including the ones of the final export
+	if tp.errHandler != nil {
+		tp.errHandler.flush()
+	}
+
+	tp.events.publish(Event{Type: ProviderShutdown, Err: err})
+
+	return err
+}
+
+func (tp *traceProvider) EffectiveConfig() config.OpenTelemetry {
+	cfg := tp.cfg.Redacted()
+	if !cfg.Enabled {
+		return cfg
+	}
+
+	cfg.Batch = batchConfig(cfg.Batch)
+
+	for i, pipeline := range tp.cfg.Pipelines {
+		cfg.Pipelines[i].Batch = batchConfig(pipelineConfig(tp.cfg, pipeline).Batch)
+	}
+
+	return cfg
+}
+
+func (tp *traceProvider) Closed() bool {
+	return tp.closed.Load()
+}
+
+func (tp *traceProvider) OnShutdown(hook func(context.Context) error) {
+	tp.shutdownHooks.add(hook)
+}
+
+func (tp *traceProvider) publish(event Event) {
+	tp.events.publish(event)
+}
+
+func (tp *traceProvider) ForceFlush(ctx context.Context) error {
+	if tp.providerForceFlushFn == nil {
+		return nil
+	}
+
+	return tp.providerForceFlushFn(ctx)
 }
 
 func (tp *traceProvider) Tracer() Tracer {
@@ -166,3 +448,11 @@ func (tp *traceProvider) Tracer() Tracer {
 func (tp *traceProvider) Type() string {
 	return tp.providerType
 }
+
+func (tp *traceProvider) Enabled() bool {
+	return tp.providerType == OTEL_PROVIDER
+}
+
+func (tp *traceProvider) TracerProvider() oteltrace.TracerProvider {
+	return tp.traceProvider
+}
@@ -0,0 +1,140 @@
+// Package providertest provides a conformance suite for trace.Provider implementations,
+// so forks and wrappers of the provider can validate that they keep its contract.
+package providertest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TykTechnologies/opentelemetry/trace"
+)
+
+/*
+	VerifyProvider runs the conformance suite against the given provider:
+	- Tracer and TracerProvider never return nil, even after Shutdown.
+	- Type is either trace.NOOP_PROVIDER or trace.OTEL_PROVIDER, and Enabled is true only for the latter.
+	- Disabled providers don't record spans.
+	- Shutdown can be called several times.
+	- If the provider implements trace.ProviderLifecycle: ForceFlush can be called on any provider,
+	  the functions registered with OnShutdown are called once, on the first Shutdown, and Closed
+	  is false until the provider is shut down, and true afterwards.
+
+	The provider is shut down by the suite, so it must not be used afterwards.
+
+Example
+
+	func TestMyProvider(t *testing.T) {
+		providertest.VerifyProvider(t, NewMyProvider())
+	}
+*/
+func VerifyProvider(t *testing.T, provider trace.Provider) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	if provider == nil {
+		t.Fatal("provider is nil")
+	}
+
+	t.Run("type and enabled", func(t *testing.T) {
+		switch provider.Type() {
+		case trace.OTEL_PROVIDER:
+			if !provider.Enabled() {
+				t.Errorf("provider of type %q must be enabled", provider.Type())
+			}
+		case trace.NOOP_PROVIDER:
+			if provider.Enabled() {
+				t.Errorf("provider of type %q must not be enabled", provider.Type())
+			}
+		default:
+			t.Errorf("unexpected provider type %q", provider.Type())
+		}
+	})
+
+	t.Run("tracer", func(t *testing.T) {
+		verifyTracer(t, provider)
+	})
+
+	t.Run("recording", func(t *testing.T) {
+		_, span := provider.Tracer().Start(ctx, "providertest")
+		defer span.End()
+
+		if !provider.Enabled() && span.IsRecording() {
+			t.Error("disabled provider must not record spans")
+		}
+	})
+
+	lifecycle, hasLifecycle := provider.(trace.ProviderLifecycle)
+
+	t.Run("force flush", func(t *testing.T) {
+		if !hasLifecycle {
+			t.Skip("provider doesn't implement trace.ProviderLifecycle")
+		}
+
+		if err := lifecycle.ForceFlush(ctx); err != nil {
+			t.Errorf("ForceFlush returned an error: %v", err)
+		}
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+		hookCalls := 0
+
+		if hasLifecycle {
+			if lifecycle.Closed() {
+				t.Error("provider is closed before Shutdown")
+			}
+
+			lifecycle.OnShutdown(func(context.Context) error {
+				hookCalls++
+				return nil
+			})
+		}
+
+		if err := provider.Shutdown(ctx); err != nil {
+			t.Errorf("Shutdown returned an error: %v", err)
+		}
+
+		if err := provider.Shutdown(ctx); err != nil {
+			t.Errorf("second Shutdown returned an error: %v", err)
+		}
+
+		if hasLifecycle {
+			if hookCalls != 1 {
+				t.Errorf("OnShutdown function called %d times, expected once", hookCalls)
+			}
+
+			if !lifecycle.Closed() {
+				t.Error("provider is not closed after Shutdown")
+			}
+		}
+
+		// the provider must still be safe to use after shutdown
+		verifyTracer(t, provider)
+	})
+}
+
+func verifyTracer(t *testing.T, provider trace.Provider) {
+	t.Helper()
+
+	tracer := provider.Tracer()
+	if tracer == nil {
+		t.Fatal("Tracer returned nil")
+	}
+
+	if provider.TracerProvider() == nil {
+		t.Fatal("TracerProvider returned nil")
+	}
+
+	ctx, span := tracer.Start(context.Background(), "providertest")
+	if span == nil {
+		t.Fatal("Start returned a nil span")
+	}
+
+	_, child := provider.TracerProvider().Tracer("providertest").Start(ctx, "providertest-child")
+	if child == nil {
+		t.Fatal("Start returned a nil span")
+	}
+
+	child.End()
+	span.End()
+}
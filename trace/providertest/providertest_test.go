@@ -0,0 +1,79 @@
+package providertest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"github.com/TykTechnologies/opentelemetry/trace"
+	"github.com/TykTechnologies/opentelemetry/trace/tracetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_VerifyProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tcs := []struct {
+		name     string
+		provider func() trace.Provider
+	}{
+		{
+			name: "noop provider",
+			provider: func() trace.Provider {
+				provider, err := trace.NewProvider(trace.WithConfig(&config.OpenTelemetry{Enabled: false}))
+				assert.Nil(t, err)
+
+				return provider
+			},
+		},
+		{
+			name: "misconfigured provider",
+			provider: func() trace.Provider {
+				provider, err := trace.NewProvider(trace.WithConfig(&config.OpenTelemetry{
+					Enabled:  true,
+					Exporter: "invalid",
+				}))
+				assert.NotNil(t, err)
+
+				return provider
+			},
+		},
+		{
+			name: "otel provider",
+			provider: func() trace.Provider {
+				provider, err := trace.NewProvider(trace.WithContext(context.Background()), trace.WithConfig(&config.OpenTelemetry{
+					Enabled:           true,
+					Exporter:          "http",
+					Endpoint:          server.URL,
+					ConnectionTimeout: 10,
+				}))
+				assert.Nil(t, err)
+
+				return provider
+			},
+		},
+		{
+			name: "in-memory provider",
+			provider: func() trace.Provider {
+				return tracetest.NewProvider()
+			},
+		},
+		{
+			name: "provider without lifecycle",
+			provider: func() trace.Provider {
+				return struct{ trace.Provider }{tracetest.NewProvider()}
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			VerifyProvider(t, tc.provider())
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Package pumpexporter provides a span exporter writing the request spans as Tyk Pump analytics
+// records, so the existing analytics storage can be reused for the traces. The records are written
+// to a pluggable Sink, such as the Redis list read by Tyk Pump.
+package pumpexporter
+
+import (
+	"context"
+	"time"
+
+	semconv "github.com/TykTechnologies/opentelemetry/semconv/v1.0.0"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	otelsemconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Record is an analytics record in the format ingested by Tyk Pump.
+type Record struct {
+	Method        string     `json:"method"`
+	Host          string     `json:"host"`
+	Path          string     `json:"path"`
+	RawPath       string     `json:"raw_path"`
+	ContentLength int64      `json:"content_length"`
+	UserAgent     string     `json:"user_agent"`
+	Day           int        `json:"day"`
+	Month         time.Month `json:"month"`
+	Year          int        `json:"year"`
+	Hour          int        `json:"hour"`
+	ResponseCode  int        `json:"response_code"`
+	APIKey        string     `json:"api_key"`
+	TimeStamp     time.Time  `json:"timestamp"`
+	APIVersion    string     `json:"api_version"`
+	APIName       string     `json:"api_name"`
+	APIID         string     `json:"api_id"`
+	OrgID         string     `json:"org_id"`
+	OauthID       string     `json:"oauth_id"`
+	// RequestTime is the duration of the request in milliseconds.
+	RequestTime int64    `json:"request_time"`
+	IPAddress   string   `json:"ip_address"`
+	Tags        []string `json:"tags"`
+	Alias       string   `json:"alias"`
+	TrackPath   bool     `json:"track_path"`
+	TraceID     string   `json:"trace_id"`
+}
+
+// Sink writes the analytics records to a storage.
+type Sink interface {
+	Write(ctx context.Context, records []Record) error
+}
+
+type exporter struct {
+	sink Sink
+}
+
+var _ sdktrace.SpanExporter = &exporter{}
+
+/*
+	NewExporter returns a span exporter converting the server spans to analytics records and writing
+	them to the sink. The other spans, such as the upstream or plugin spans, are not exported since
+	Tyk Pump records one analytics record per request.
+	The records are filled from the Tyk API semantic conventions and the OpenTelemetry HTTP attributes
+	of the spans.
+
+Example
+
+	sink := pumpexporter.NewRedisSink(redisClient, pumpexporter.DefaultAnalyticsKey, msgpack.Marshal)
+
+	provider, err := trace.NewProvider(
+		trace.WithConfig(cfg),
+		trace.WithSpanProcessor(sdktrace.NewBatchSpanProcessor(pumpexporter.NewExporter(sink))),
+	)
+*/
+func NewExporter(sink Sink) sdktrace.SpanExporter {
+	return &exporter{sink: sink}
+}
+
+func (e *exporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	records := make([]Record, 0, len(spans))
+
+	for _, span := range spans {
+		if span.SpanKind() != oteltrace.SpanKindServer {
+			continue
+		}
+
+		records = append(records, NewRecord(span))
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	return e.sink.Write(ctx, records)
+}
+
+func (e *exporter) Shutdown(context.Context) error {
+	return nil
+}
+
+// NewRecord converts a request span to an analytics record.
+func NewRecord(span sdktrace.ReadOnlySpan) Record {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+
+	str := func(keys ...attribute.Key) string {
+		for _, key := range keys {
+			if value, ok := attrs[key]; ok && value.AsString() != "" {
+				return value.AsString()
+			}
+		}
+
+		return ""
+	}
+
+	path := str(otelsemconv.HTTPTargetKey, "url.path")
+	start := span.StartTime().UTC()
+
+	return Record{
+		Method:        str(otelsemconv.HTTPMethodKey, "http.request.method"),
+		Host:          str(otelsemconv.NetHostNameKey, "server.address"),
+		Path:          path,
+		RawPath:       path,
+		ContentLength: attrs[otelsemconv.HTTPRequestContentLengthKey].AsInt64(),
+		UserAgent:     str(otelsemconv.UserAgentOriginalKey),
+		Day:           start.Day(),
+		Month:         start.Month(),
+		Year:          start.Year(),
+		Hour:          start.Hour(),
+		ResponseCode:  int(attrs[otelsemconv.HTTPStatusCodeKey].AsInt64()),
+		APIKey:        str(semconv.TykAPIKeyKey),
+		TimeStamp:     start,
+		APIVersion:    str(semconv.TykAPIVersionKey),
+		APIName:       str(semconv.TykAPINameKey),
+		APIID:         str(semconv.TykAPIIDKey),
+		OrgID:         str(semconv.TykAPIOrgIDKey),
+		OauthID:       str(semconv.TykOauthIDKey),
+		RequestTime:   span.EndTime().Sub(span.StartTime()).Milliseconds(),
+		IPAddress:     str(otelsemconv.HTTPClientIPKey, otelsemconv.NetSockPeerAddrKey, "client.address"),
+		Tags:          attrs[semconv.TykAPITagsKey].AsStringSlice(),
+		Alias:         str(semconv.TykAPIKeyAliasKey),
+		TrackPath:     path != "",
+		TraceID:       span.SpanContext().TraceID().String(),
+	}
+}
@@ -0,0 +1,157 @@
+package pumpexporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	semconv "github.com/TykTechnologies/opentelemetry/semconv/v1.0.0"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	otelsemconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+type recordingSink struct {
+	records []Record
+}
+
+func (s *recordingSink) Write(_ context.Context, records []Record) error {
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func requestSpan() tracetest.SpanStub {
+	start := time.Date(2024, time.March, 5, 14, 30, 0, 0, time.UTC)
+	traceID, _ := oteltrace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+
+	return tracetest.SpanStub{
+		Name:        "GET /users",
+		SpanKind:    oteltrace.SpanKindServer,
+		SpanContext: oteltrace.NewSpanContext(oteltrace.SpanContextConfig{TraceID: traceID}),
+		StartTime:   start,
+		EndTime:     start.Add(150 * time.Millisecond),
+		Attributes: []attribute.KeyValue{
+			otelsemconv.HTTPMethod("GET"),
+			otelsemconv.HTTPTarget("/users/123"),
+			otelsemconv.NetHostName("api.example.com"),
+			otelsemconv.HTTPStatusCode(200),
+			otelsemconv.HTTPRequestContentLength(42),
+			otelsemconv.UserAgentOriginal("curl/8.0"),
+			otelsemconv.HTTPClientIP("10.0.0.1"),
+			semconv.TykAPIID("api-id"),
+			semconv.TykAPIName("users"),
+			semconv.TykAPIOrgID("org-id"),
+			semconv.TykAPIVersion("v1"),
+			semconv.TykAPITags("public"),
+			semconv.TykAPIKey("key-hash"),
+			semconv.TykAPIKeyAlias("portal"),
+		},
+	}
+}
+
+func TestNewRecord(t *testing.T) {
+	record := NewRecord(tracetest.SpanStubs{requestSpan()}.Snapshots()[0])
+
+	assert.Equal(t, Record{
+		Method:        "GET",
+		Host:          "api.example.com",
+		Path:          "/users/123",
+		RawPath:       "/users/123",
+		ContentLength: 42,
+		UserAgent:     "curl/8.0",
+		Day:           5,
+		Month:         time.March,
+		Year:          2024,
+		Hour:          14,
+		ResponseCode:  200,
+		APIKey:        "key-hash",
+		TimeStamp:     time.Date(2024, time.March, 5, 14, 30, 0, 0, time.UTC),
+		APIVersion:    "v1",
+		APIName:       "users",
+		APIID:         "api-id",
+		OrgID:         "org-id",
+		RequestTime:   150,
+		IPAddress:     "10.0.0.1",
+		Tags:          []string{"public"},
+		Alias:         "portal",
+		TrackPath:     true,
+		TraceID:       "0102030405060708090a0b0c0d0e0f10",
+	}, record)
+}
+
+func TestExporter(t *testing.T) {
+	sink := &recordingSink{}
+	exporter := NewExporter(sink)
+
+	upstream := requestSpan()
+	upstream.Name = "upstream"
+	upstream.SpanKind = oteltrace.SpanKindClient
+
+	spans := tracetest.SpanStubs{requestSpan(), upstream}.Snapshots()
+
+	assert.Nil(t, exporter.ExportSpans(context.Background(), spans))
+	assert.Len(t, sink.records, 1)
+	assert.Equal(t, "api-id", sink.records[0].APIID)
+
+	// the batches without request spans are not written
+	assert.Nil(t, exporter.ExportSpans(context.Background(), spans[1:]))
+	assert.Len(t, sink.records, 1)
+
+	assert.Nil(t, exporter.Shutdown(context.Background()))
+}
+
+// commandRecorder records the redis commands instead of sending them.
+type commandRecorder struct {
+	args [][]interface{}
+}
+
+func (r *commandRecorder) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("unexpected dial")
+	}
+}
+
+func (r *commandRecorder) ProcessHook(redis.ProcessHook) redis.ProcessHook {
+	return func(_ context.Context, cmd redis.Cmder) error {
+		r.args = append(r.args, cmd.Args())
+		return nil
+	}
+}
+
+func (r *commandRecorder) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestRedisSink(t *testing.T) {
+	recorder := &commandRecorder{}
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	client.AddHook(recorder)
+
+	sink := NewRedisSink(client, DefaultAnalyticsKey, JSONEncoder)
+	records := []Record{{APIID: "first"}, {APIID: "second"}}
+
+	assert.Nil(t, sink.Write(context.Background(), records))
+	assert.Len(t, recorder.args, 1)
+	assert.Equal(t, []interface{}{"rpush", DefaultAnalyticsKey}, recorder.args[0][:2])
+	assert.Len(t, recorder.args[0], 4)
+
+	var record Record
+
+	assert.Nil(t, json.Unmarshal(recorder.args[0][3].([]byte), &record))
+	assert.Equal(t, "second", record.APIID)
+
+	t.Run("encoding error", func(t *testing.T) {
+		failing := NewRedisSink(client, DefaultAnalyticsKey, func(interface{}) ([]byte, error) {
+			return nil, errors.New("unsupported type")
+		})
+
+		assert.EqualError(t, failing.Write(context.Background(), records),
+			"failed to encode analytics record: unsupported type")
+	})
+}
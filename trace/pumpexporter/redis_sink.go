@@ -0,0 +1,58 @@
+package pumpexporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultAnalyticsKey is the Redis list from which Tyk Pump reads the analytics records.
+const DefaultAnalyticsKey = "analytics-tyk-system-analytics"
+
+// Encoder encodes an analytics record.
+type Encoder func(v interface{}) ([]byte, error)
+
+// JSONEncoder encodes the records in JSON, for the consumers other than Tyk Pump.
+var JSONEncoder Encoder = json.Marshal
+
+type redisSink struct {
+	client redis.Cmdable
+	key    string
+	encode Encoder
+}
+
+var _ Sink = &redisSink{}
+
+/*
+	NewRedisSink returns a sink pushing the encoded records to the given Redis list, in a single command
+	per export. Tyk Pump decodes the records of its list with msgpack, so it's the encoder to use with
+	the DefaultAnalyticsKey.
+
+Example
+
+	sink := pumpexporter.NewRedisSink(redisClient, pumpexporter.DefaultAnalyticsKey, msgpack.Marshal)
+*/
+func NewRedisSink(client redis.Cmdable, key string, encode Encoder) Sink {
+	return &redisSink{
+		client: client,
+		key:    key,
+		encode: encode,
+	}
+}
+
+func (s *redisSink) Write(ctx context.Context, records []Record) error {
+	values := make([]interface{}, 0, len(records))
+
+	for _, record := range records {
+		encoded, err := s.encode(record)
+		if err != nil {
+			return fmt.Errorf("failed to encode analytics record: %w", err)
+		}
+
+		values = append(values, encoded)
+	}
+
+	return s.client.RPush(ctx, s.key, values...).Err()
+}
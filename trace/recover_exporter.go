@@ -0,0 +1,49 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// recoverExporter wraps a span exporter and recovers from its panics, so a bug in the
+// OTLP client can't crash the host process. The span processors call the exporter from
+// their own goroutines, where a panic can't be recovered by the host.
+// A recovered panic is logged with its stack and returned as the export error, so it's
+// reported like any other failed export.
+type recoverExporter struct {
+	sdktrace.SpanExporter
+	logger Logger
+}
+
+func newRecoverExporter(exporter sdktrace.SpanExporter, logger Logger) *recoverExporter {
+	return &recoverExporter{
+		SpanExporter: exporter,
+		logger:       logger,
+	}
+}
+
+func (e *recoverExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) (err error) {
+	defer e.recover("export", &err)
+
+	return e.SpanExporter.ExportSpans(ctx, spans)
+}
+
+func (e *recoverExporter) Shutdown(ctx context.Context) (err error) {
+	defer e.recover("shutdown", &err)
+
+	return e.SpanExporter.Shutdown(ctx)
+}
+
+// recover must be deferred, it replaces the returned error by the recovered panic, if any.
+func (e *recoverExporter) recover(operation string, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	*err = fmt.Errorf("exporter panic during %s: %v", operation, r)
+	e.logger.Error(fmt.Sprintf("recovered from %v\n%s", *err, debug.Stack()))
+}
@@ -0,0 +1,57 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type panickingExporter struct{}
+
+func (panickingExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error {
+	panic("nil client")
+}
+
+func (panickingExporter) Shutdown(context.Context) error {
+	panic("already closed")
+}
+
+func Test_RecoverExporter(t *testing.T) {
+	ctx := context.Background()
+	spans := tracetest.SpanStubs{{Name: "span"}}.Snapshots()
+
+	t.Run("panics are returned as errors", func(t *testing.T) {
+		logger := &recordingLogger{}
+		exporter := newRecoverExporter(panickingExporter{}, logger)
+
+		assert.EqualError(t, exporter.ExportSpans(ctx, spans), "exporter panic during export: nil client")
+		assert.EqualError(t, exporter.Shutdown(ctx), "exporter panic during shutdown: already closed")
+
+		assert.Len(t, logger.errors, 2)
+		assert.Contains(t, logger.errors[0], "recovered from exporter panic during export: nil client")
+		assert.Contains(t, logger.errors[0], "recover_exporter_test.go")
+	})
+
+	t.Run("errors are returned as is", func(t *testing.T) {
+		logger := &recordingLogger{}
+		fe := &failingExporter{err: assert.AnError}
+		exporter := newRecoverExporter(fe, logger)
+
+		assert.Equal(t, assert.AnError, exporter.ExportSpans(ctx, spans))
+		assert.Nil(t, exporter.Shutdown(ctx))
+		assert.Empty(t, logger.errors)
+	})
+
+	t.Run("batch processor", func(t *testing.T) {
+		exporter := newRecoverExporter(panickingExporter{}, &noopLogger{})
+		tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+
+		_, span := tracerProvider.Tracer("test").Start(ctx, "span")
+		span.End()
+
+		assert.EqualError(t, tracerProvider.ForceFlush(ctx), "exporter panic during export: nil client")
+	})
+}
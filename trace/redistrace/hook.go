@@ -0,0 +1,214 @@
+// Package redistrace provides a go-redis hook that instruments the redis commands
+// with client spans and, optionally, a command duration histogram.
+package redistrace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/TykTechnologies/opentelemetry/trace/redistrace"
+
+// DurationMetricName is the name of the histogram that records the duration of the redis commands.
+const DurationMetricName = "db.client.operation.duration"
+
+type Option interface {
+	apply(*hook)
+}
+
+type opts struct {
+	fn func(*hook)
+}
+
+func (o *opts) apply(h *hook) {
+	o.fn(h)
+}
+
+// WithTracerProvider sets the tracer provider used to create the redis spans.
+// Defaults to the global tracer provider, which is the one set by trace.NewProvider.
+func WithTracerProvider(tp oteltrace.TracerProvider) Option {
+	return &opts{
+		fn: func(h *hook) {
+			h.tracer = tp.Tracer(instrumentationName)
+		},
+	}
+}
+
+// WithMeterProvider enables the command duration histogram using the given meter provider.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return &opts{
+		fn: func(h *hook) {
+			h.meterProvider = mp
+		},
+	}
+}
+
+// WithDBStatement adds the full command, including its arguments, as the db.statement attribute.
+// It's disabled by default since the arguments can contain sensitive data such as session objects.
+func WithDBStatement() Option {
+	return &opts{
+		fn: func(h *hook) {
+			h.withStatement = true
+		},
+	}
+}
+
+// WithAttributes adds the given attributes to all the redis spans and measurements.
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return &opts{
+		fn: func(h *hook) {
+			h.attrs = append(h.attrs, attrs...)
+		},
+	}
+}
+
+type hook struct {
+	tracer        oteltrace.Tracer
+	meterProvider metric.MeterProvider
+	duration      metric.Float64Histogram
+	withStatement bool
+	attrs         []attribute.KeyValue
+}
+
+var _ redis.Hook = &hook{}
+
+/*
+	NewHook returns a go-redis hook that creates a client span for every redis command, pipeline and dial.
+	The spans are created from the span in the command context, so storage calls appear
+	as children of the API request spans.
+
+Example
+
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	rdb.AddHook(redistrace.NewHook(redistrace.WithMeterProvider(otel.GetMeterProvider())))
+*/
+func NewHook(options ...Option) redis.Hook {
+	h := &hook{
+		tracer: otel.GetTracerProvider().Tracer(instrumentationName),
+		attrs:  []attribute.KeyValue{semconv.DBSystemRedis},
+	}
+
+	for _, opt := range options {
+		opt.apply(h)
+	}
+
+	if h.meterProvider != nil {
+		duration, err := h.meterProvider.Meter(instrumentationName).Float64Histogram(DurationMetricName,
+			metric.WithDescription("Duration of the redis commands."),
+			metric.WithUnit("s"),
+		)
+		if err != nil {
+			otel.Handle(err)
+		} else {
+			h.duration = duration
+		}
+	}
+
+	return h
+}
+
+func (h *hook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		ctx, span := h.tracer.Start(ctx, "redis.dial",
+			oteltrace.WithSpanKind(oteltrace.SpanKindClient),
+			oteltrace.WithAttributes(h.attrs...))
+		defer span.End()
+
+		conn, err := next(ctx, network, addr)
+		recordError(span, err)
+
+		return conn, err
+	}
+}
+
+func (h *hook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		attrs := append(h.attrs[:len(h.attrs):len(h.attrs)], semconv.DBOperation(cmd.Name()))
+
+		spanAttrs := attrs
+		if h.withStatement {
+			spanAttrs = append(spanAttrs, semconv.DBStatement(cmdString(cmd)))
+		}
+
+		ctx, span := h.tracer.Start(ctx, cmd.FullName(),
+			oteltrace.WithSpanKind(oteltrace.SpanKindClient),
+			oteltrace.WithAttributes(spanAttrs...))
+		defer span.End()
+
+		start := time.Now()
+		err := next(ctx, cmd)
+
+		recordError(span, err)
+		h.recordDuration(ctx, start, attrs)
+
+		return err
+	}
+}
+
+func (h *hook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		attrs := append(h.attrs[:len(h.attrs):len(h.attrs)], semconv.DBOperation("pipeline"))
+
+		spanAttrs := append(attrs[:len(attrs):len(attrs)], attribute.Int("db.redis.num_cmd", len(cmds)))
+		if h.withStatement {
+			statements := make([]string, 0, len(cmds))
+			for _, cmd := range cmds {
+				statements = append(statements, cmdString(cmd))
+			}
+
+			spanAttrs = append(spanAttrs, semconv.DBStatement(strings.Join(statements, "\n")))
+		}
+
+		ctx, span := h.tracer.Start(ctx, "pipeline",
+			oteltrace.WithSpanKind(oteltrace.SpanKindClient),
+			oteltrace.WithAttributes(spanAttrs...))
+		defer span.End()
+
+		start := time.Now()
+		err := next(ctx, cmds)
+
+		recordError(span, err)
+		h.recordDuration(ctx, start, attrs)
+
+		return err
+	}
+}
+
+func (h *hook) recordDuration(ctx context.Context, start time.Time, attrs []attribute.KeyValue) {
+	if h.duration == nil {
+		return
+	}
+
+	h.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+}
+
+// recordError sets the span status to error, except for redis.Nil which means a missing key.
+func recordError(span oteltrace.Span, err error) {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+func cmdString(cmd redis.Cmder) string {
+	args := make([]string, 0, len(cmd.Args()))
+	for _, arg := range cmd.Args() {
+		args = append(args, fmt.Sprint(arg))
+	}
+
+	return strings.Join(args, " ")
+}
@@ -0,0 +1,167 @@
+package redistrace
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+)
+
+type testMeterProvider struct {
+	noop.MeterProvider
+	histogram *testHistogram
+}
+
+func (mp *testMeterProvider) Meter(string, ...metric.MeterOption) metric.Meter {
+	return &testMeter{histogram: mp.histogram}
+}
+
+type testMeter struct {
+	noop.Meter
+	histogram *testHistogram
+}
+
+func (m *testMeter) Float64Histogram(name string, _ ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	m.histogram.name = name
+	return m.histogram, nil
+}
+
+type testHistogram struct {
+	noop.Float64Histogram
+	name  string
+	attrs []attribute.Set
+}
+
+func (h *testHistogram) Record(_ context.Context, _ float64, opts ...metric.RecordOption) {
+	h.attrs = append(h.attrs, metric.NewRecordConfig(opts).Attributes())
+}
+
+func Test_ProcessHook(t *testing.T) {
+	errRedis := errors.New("connection reset")
+
+	tcs := []struct {
+		name           string
+		opts           []Option
+		cmdErr         error
+		expectedStatus codes.Code
+		expectedAttrs  []attribute.KeyValue
+	}{
+		{
+			name:           "successful command",
+			expectedStatus: codes.Unset,
+			expectedAttrs: []attribute.KeyValue{
+				semconv.DBSystemRedis,
+				semconv.DBOperation("get"),
+			},
+		},
+		{
+			name:           "missing key is not an error",
+			cmdErr:         redis.Nil,
+			expectedStatus: codes.Unset,
+		},
+		{
+			name:           "failed command",
+			cmdErr:         errRedis,
+			expectedStatus: codes.Error,
+		},
+		{
+			name:           "with statement and custom attributes",
+			opts:           []Option{WithDBStatement(), WithAttributes(attribute.String("tyk.storage", "sessions"))},
+			expectedStatus: codes.Unset,
+			expectedAttrs: []attribute.KeyValue{
+				semconv.DBStatement("get apikey-1"),
+				attribute.String("tyk.storage", "sessions"),
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			histogram := &testHistogram{}
+
+			h := NewHook(append(tc.opts, WithTracerProvider(tp), WithMeterProvider(&testMeterProvider{histogram: histogram}))...)
+
+			ctx, parent := tp.Tracer("test").Start(context.Background(), "GET /api")
+			cmd := redis.NewStringCmd(ctx, "get", "apikey-1")
+
+			err := h.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+				return tc.cmdErr
+			})(ctx, cmd)
+			assert.Equal(t, tc.cmdErr, err)
+
+			spans := exporter.GetSpans()
+			assert.Len(t, spans, 1)
+			assert.Equal(t, "get", spans[0].Name)
+			assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent.SpanID())
+			assert.Equal(t, tc.expectedStatus, spans[0].Status.Code)
+
+			for _, attr := range tc.expectedAttrs {
+				assert.Contains(t, spans[0].Attributes, attr)
+			}
+
+			if !assert.Len(t, histogram.attrs, 1) {
+				return
+			}
+
+			assert.Equal(t, DurationMetricName, histogram.name)
+
+			// the statement is never used as a metric attribute
+			_, ok := histogram.attrs[0].Value(semconv.DBStatementKey)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func Test_ProcessPipelineHook(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	h := NewHook(WithTracerProvider(tp), WithDBStatement())
+
+	ctx := context.Background()
+	cmds := []redis.Cmder{
+		redis.NewStringCmd(ctx, "get", "key1"),
+		redis.NewStatusCmd(ctx, "set", "key2", "value"),
+	}
+
+	err := h.ProcessPipelineHook(func(ctx context.Context, cmds []redis.Cmder) error {
+		return nil
+	})(ctx, cmds)
+	assert.Nil(t, err)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "pipeline", spans[0].Name)
+	assert.Contains(t, spans[0].Attributes, attribute.Int("db.redis.num_cmd", 2))
+	assert.Contains(t, spans[0].Attributes, semconv.DBStatement("get key1\nset key2 value"))
+}
+
+func Test_DialHook(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	h := NewHook(WithTracerProvider(tp))
+
+	errDial := errors.New("connection refused")
+	_, err := h.DialHook(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errDial
+	})(context.Background(), "tcp", "localhost:6379")
+	assert.Equal(t, errDial, err)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "redis.dial", spans[0].Name)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+}
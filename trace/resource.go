@@ -2,27 +2,45 @@ package trace
 
 import (
 	"context"
+	"errors"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk"
 	"go.opentelemetry.io/otel/sdk/resource"
 	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
 )
 
+const (
+	// TelemetryDistroNameKey is the resource attribute with the name of the distribution
+	// of the OpenTelemetry SDK, this library.
+	TelemetryDistroNameKey = attribute.Key("telemetry.distro.name")
+	// TelemetryDistroVersionKey is the resource attribute with the version of this library.
+	TelemetryDistroVersionKey = attribute.Key("telemetry.distro.version")
+)
+
 type resourceConfig struct {
-	id      string
-	version string
+	id        string
+	version   string
+	schemaURL string
 
 	withHost      bool
 	withContainer bool
 	withProcess   bool
 
 	customAttrs []Attribute
+	detectors   []resource.Detector
 }
 
 func resourceFactory(ctx context.Context, resourceName string, cfg resourceConfig) (*resource.Resource, error) {
 	opts := []resource.Option{}
 
+	// the telemetry SDK attributes go first, so they can be overridden by the custom attributes
 	attrs := []attribute.KeyValue{
+		semconv.TelemetrySDKName("opentelemetry"),
+		semconv.TelemetrySDKLanguageGo,
+		semconv.TelemetrySDKVersion(sdk.Version()),
+		TelemetryDistroNameKey.String(libraryName),
+		TelemetryDistroVersionKey.String(libraryVersion()),
 		semconv.ServiceNameKey.String(resourceName),
 	}
 
@@ -37,7 +55,11 @@ func resourceFactory(ctx context.Context, resourceName string, cfg resourceConfi
 	// add custom attributes
 	attrs = append(attrs, cfg.customAttrs...)
 
-	opts = append(opts, resource.WithAttributes(attrs...))
+	// the schema URL describes the attributes set by us, so it defaults to the semconv version in use
+	schemaURL := cfg.schemaURL
+	if schemaURL == "" {
+		schemaURL = semconv.SchemaURL
+	}
 
 	if cfg.withContainer {
 		opts = append(opts, resource.WithContainer())
@@ -57,5 +79,24 @@ func resourceFactory(ctx context.Context, resourceName string, cfg resourceConfi
 			resource.WithProcessRuntimeDescription())
 	}
 
-	return resource.New(ctx, opts...)
+	if len(cfg.detectors) > 0 {
+		opts = append(opts, resource.WithDetectors(cfg.detectors...))
+	}
+
+	// if the detectors return differing schema URLs, the detected resource is returned without schema URL
+	// and it's safe to continue since the configured schema URL will be applied below
+	detected, err := resource.New(ctx, opts...)
+	if err != nil && !errors.Is(err, resource.ErrSchemaURLConflict) {
+		return detected, err
+	}
+
+	// detected attributes take precedence over the configured ones
+	res, err := resource.Merge(resource.NewWithAttributes(schemaURL, attrs...), detected)
+	if errors.Is(err, resource.ErrSchemaURLConflict) {
+		// detectors are using a different semconv version than the configured schema URL,
+		// so we keep the merged attributes under the configured schema URL
+		return resource.NewWithAttributes(schemaURL, res.Attributes()...), nil
+	}
+
+	return res, err
 }
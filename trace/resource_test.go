@@ -2,11 +2,14 @@ package trace
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk"
+	"go.opentelemetry.io/otel/sdk/resource"
 	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
 )
 
@@ -19,6 +22,7 @@ func TestResourceFactory(t *testing.T) {
 		resourceName  string
 		cfg           resourceConfig
 		expectedAttrs []attribute.KeyValue
+		expectedURL   string
 		expectedErr   error
 	}{
 		{
@@ -28,6 +32,34 @@ func TestResourceFactory(t *testing.T) {
 			expectedAttrs: []attribute.KeyValue{
 				semconv.ServiceNameKey.String("testResource"),
 			},
+			expectedURL: semconv.SchemaURL,
+			expectedErr: nil,
+		},
+		{
+			name:         "Test with custom schema URL",
+			resourceName: "testResource",
+			cfg: resourceConfig{
+				schemaURL: "https://opentelemetry.io/schemas/1.21.0",
+			},
+			expectedAttrs: []attribute.KeyValue{
+				semconv.ServiceNameKey.String("testResource"),
+			},
+			expectedURL: "https://opentelemetry.io/schemas/1.21.0",
+			expectedErr: nil,
+		},
+		{
+			// the host detector uses the SDK semconv version, which differs from the configured one
+			name:         "Test with conflicting detector schema URL",
+			resourceName: "testResource",
+			cfg: resourceConfig{
+				schemaURL: "https://opentelemetry.io/schemas/1.0.0",
+				withHost:  true,
+			},
+			expectedAttrs: []attribute.KeyValue{
+				semconv.ServiceNameKey.String("testResource"),
+				semconv.HostName(currentHost),
+			},
+			expectedURL: "https://opentelemetry.io/schemas/1.0.0",
 			expectedErr: nil,
 		},
 		{
@@ -79,6 +111,78 @@ func TestResourceFactory(t *testing.T) {
 				attribute.Key("customKey").String("customValue"),
 			},
 		},
+		{
+			name:         "Test with telemetry SDK attributes",
+			resourceName: "testResource",
+			cfg:          resourceConfig{},
+			expectedAttrs: []attribute.KeyValue{
+				semconv.TelemetrySDKName("opentelemetry"),
+				semconv.TelemetrySDKLanguageGo,
+				semconv.TelemetrySDKVersion(sdk.Version()),
+				TelemetryDistroNameKey.String("tyk-otel"),
+				TelemetryDistroVersionKey.String(libraryVersion()),
+			},
+		},
+		{
+			name:         "Test with overridden telemetry distro attributes",
+			resourceName: "testResource",
+			cfg: resourceConfig{
+				customAttrs: []Attribute{
+					TelemetryDistroNameKey.String("tyk-gateway"),
+				},
+			},
+			expectedAttrs: []attribute.KeyValue{
+				TelemetryDistroNameKey.String("tyk-gateway"),
+			},
+		},
+		{
+			name:         "Test with custom detector",
+			resourceName: "testResource",
+			cfg: resourceConfig{
+				detectors: []resource.Detector{
+					resource.StringDetector("", "tyk.cluster.id", func() (string, error) {
+						return "cluster-1", nil
+					}),
+				},
+			},
+			expectedAttrs: []attribute.KeyValue{
+				semconv.ServiceNameKey.String("testResource"),
+				attribute.Key("tyk.cluster.id").String("cluster-1"),
+			},
+			expectedURL: semconv.SchemaURL,
+		},
+		{
+			name:         "Test with custom detectors with differing schema URLs",
+			resourceName: "testResource",
+			cfg: resourceConfig{
+				detectors: []resource.Detector{
+					resource.StringDetector("https://opentelemetry.io/schemas/1.21.0", "tyk.cluster.id", func() (string, error) {
+						return "cluster-1", nil
+					}),
+					resource.StringDetector("https://opentelemetry.io/schemas/1.22.0", "tyk.license.id", func() (string, error) {
+						return "license-1", nil
+					}),
+				},
+			},
+			expectedAttrs: []attribute.KeyValue{
+				semconv.ServiceNameKey.String("testResource"),
+				attribute.Key("tyk.cluster.id").String("cluster-1"),
+				attribute.Key("tyk.license.id").String("license-1"),
+			},
+			expectedURL: semconv.SchemaURL,
+		},
+		{
+			name:         "Test with failing custom detector",
+			resourceName: "testResource",
+			cfg: resourceConfig{
+				detectors: []resource.Detector{
+					resource.StringDetector("", "tyk.cluster.id", func() (string, error) {
+						return "", errors.New("cluster unavailable")
+					}),
+				},
+			},
+			expectedErr: errors.New("cluster unavailable"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -88,17 +192,23 @@ func TestResourceFactory(t *testing.T) {
 
 			if tc.expectedErr != nil {
 				assert.Error(t, err)
-				assert.Equal(t, tc.expectedErr, err)
-			} else {
-				assert.NoError(t, err)
+				assert.ErrorContains(t, err, tc.expectedErr.Error())
+
+				return
 			}
 
+			assert.NoError(t, err)
+
 			if res != nil {
 				attrs := res.Attributes()
 
 				for _, expectedAttr := range tc.expectedAttrs {
 					assert.Contains(t, attrs, expectedAttr)
 				}
+
+				if tc.expectedURL != "" {
+					assert.Equal(t, tc.expectedURL, res.SchemaURL())
+				}
 			}
 		})
 	}
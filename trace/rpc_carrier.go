@@ -0,0 +1,70 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// RPCEnvelope wraps the payloads sent through the Tyk RPC layer with the trace context,
+// so the traces flow from the edge gateways through MDCB to the control plane.
+// It's encoded as JSON, with the payload as a base64 string.
+type RPCEnvelope struct {
+	// TraceContext holds the span context and baggage fields of the global propagator.
+	TraceContext MapCarrier `json:"trace_context,omitempty"`
+	// Payload is the original RPC payload.
+	Payload []byte `json:"payload"`
+}
+
+// NewRPCEnvelope returns an envelope with the payload and the trace context of ctx,
+// using the globally registered context propagator.
+func NewRPCEnvelope(ctx context.Context, payload []byte) RPCEnvelope {
+	carrier := MapCarrier{}
+	Inject(ctx, carrier)
+
+	if len(carrier) == 0 {
+		carrier = nil
+	}
+
+	return RPCEnvelope{
+		TraceContext: carrier,
+		Payload:      payload,
+	}
+}
+
+// Context returns a copy of ctx with the span context and baggage of the envelope,
+// using the globally registered context propagator.
+func (e RPCEnvelope) Context(ctx context.Context) context.Context {
+	if len(e.TraceContext) == 0 {
+		return ctx
+	}
+
+	return Extract(ctx, e.TraceContext)
+}
+
+/*
+	MarshalRPCEnvelope encodes the payload in an envelope with the trace context of ctx.
+
+Example
+
+	data, err := trace.MarshalRPCEnvelope(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	// on the receiving side
+	ctx, payload, err := trace.UnmarshalRPCEnvelope(ctx, data)
+*/
+func MarshalRPCEnvelope(ctx context.Context, payload []byte) ([]byte, error) {
+	return json.Marshal(NewRPCEnvelope(ctx, payload))
+}
+
+// UnmarshalRPCEnvelope decodes an envelope encoded with MarshalRPCEnvelope, returning a copy of ctx
+// with the trace context of the envelope and the original payload.
+func UnmarshalRPCEnvelope(ctx context.Context, data []byte) (context.Context, []byte, error) {
+	var envelope RPCEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return ctx, nil, err
+	}
+
+	return envelope.Context(ctx), envelope.Payload, nil
+}
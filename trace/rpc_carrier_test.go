@@ -0,0 +1,68 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_RPCEnvelope(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	member, err := baggage.NewMember("tyk.org", "org-1")
+	assert.Nil(t, err)
+
+	bag, err := baggage.New(member)
+	assert.Nil(t, err)
+
+	ctx := baggage.ContextWithBaggage(trace.ContextWithSpanContext(context.Background(), sc), bag)
+
+	data, err := MarshalRPCEnvelope(ctx, []byte(`{"key":"value"}`))
+	assert.Nil(t, err)
+
+	received, payload, err := UnmarshalRPCEnvelope(context.Background(), data)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"key":"value"}`, string(payload))
+
+	receivedSC := trace.SpanContextFromContext(received)
+	assert.Equal(t, sc.TraceID(), receivedSC.TraceID())
+	assert.Equal(t, sc.SpanID(), receivedSC.SpanID())
+	assert.True(t, receivedSC.IsRemote())
+	assert.Equal(t, "org-1", baggage.FromContext(received).Member("tyk.org").Value())
+}
+
+func Test_RPCEnvelopeWithoutTraceContext(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	envelope := NewRPCEnvelope(context.Background(), []byte("payload"))
+	assert.Nil(t, envelope.TraceContext)
+
+	data, err := MarshalRPCEnvelope(context.Background(), []byte("payload"))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"payload":"cGF5bG9hZA=="}`, string(data))
+
+	ctx, payload, err := UnmarshalRPCEnvelope(context.Background(), data)
+	assert.Nil(t, err)
+	assert.Equal(t, "payload", string(payload))
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+}
+
+func Test_UnmarshalRPCEnvelopeInvalid(t *testing.T) {
+	ctx := context.Background()
+
+	received, payload, err := UnmarshalRPCEnvelope(ctx, []byte("not json"))
+	assert.NotNil(t, err)
+	assert.Nil(t, payload)
+	assert.Equal(t, ctx, received)
+}
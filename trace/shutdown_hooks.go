@@ -0,0 +1,43 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// shutdownHooks holds the functions registered with OnShutdown.
+type shutdownHooks struct {
+	mu    sync.Mutex
+	hooks []func(context.Context) error
+}
+
+func (h *shutdownHooks) add(hook func(context.Context) error) {
+	if hook == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.hooks = append(h.hooks, hook)
+}
+
+// run calls the hooks in the order they were registered and returns their errors joined.
+// The hooks are removed, so they are called only once even if the provider is shut down again.
+func (h *shutdownHooks) run(ctx context.Context) error {
+	h.mu.Lock()
+	hooks := h.hooks
+	h.hooks = nil
+	h.mu.Unlock()
+
+	errs := []error{}
+
+	for _, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
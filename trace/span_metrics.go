@@ -0,0 +1,112 @@
+package trace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	spanMetricsMeterName = "github.com/TykTechnologies/opentelemetry/trace/spanmetrics"
+
+	// SpanMetricsCallsName is the name of the counter with the number of ended sampled spans.
+	SpanMetricsCallsName = "traces.span.metrics.calls"
+	// SpanMetricsErrorsName is the name of the counter with the number of ended sampled spans with error status.
+	SpanMetricsErrorsName = "traces.span.metrics.errors"
+	// SpanMetricsDurationName is the name of the histogram with the duration of the ended sampled spans.
+	SpanMetricsDurationName = "traces.span.metrics.duration"
+)
+
+// spanMetricsProcessor is a span processor that derives RED metrics (rate, errors and duration)
+// from the ended spans, keyed by span name, kind, status code and the configured dimensions.
+// The spans dropped by the sampler are never recorded, so they don't reach the processor: the
+// status and duration of a span are only known once it ends, which the sampler can't observe.
+type spanMetricsProcessor struct {
+	calls    metric.Int64Counter
+	errors   metric.Int64Counter
+	duration metric.Float64Histogram
+
+	dimensions []attribute.Key
+}
+
+var _ sdktrace.SpanProcessor = &spanMetricsProcessor{}
+
+func newSpanMetricsProcessor(mp metric.MeterProvider, dimensions []string) (*spanMetricsProcessor, error) {
+	meter := mp.Meter(spanMetricsMeterName)
+
+	calls, err := meter.Int64Counter(SpanMetricsCallsName,
+		metric.WithDescription("Number of ended sampled spans."),
+		metric.WithUnit("{span}"))
+	if err != nil {
+		return nil, err
+	}
+
+	errors, err := meter.Int64Counter(SpanMetricsErrorsName,
+		metric.WithDescription("Number of ended sampled spans with error status."),
+		metric.WithUnit("{span}"))
+	if err != nil {
+		return nil, err
+	}
+
+	duration, err := meter.Float64Histogram(SpanMetricsDurationName,
+		metric.WithDescription("Duration of the ended sampled spans."),
+		metric.WithUnit("ms"))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]attribute.Key, 0, len(dimensions))
+	for _, dimension := range dimensions {
+		keys = append(keys, attribute.Key(dimension))
+	}
+
+	return &spanMetricsProcessor{
+		calls:      calls,
+		errors:     errors,
+		duration:   duration,
+		dimensions: keys,
+	}, nil
+}
+
+func (p *spanMetricsProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (p *spanMetricsProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	attrs := []attribute.KeyValue{
+		attribute.String("span.name", s.Name()),
+		attribute.String("span.kind", s.SpanKind().String()),
+		attribute.String("status.code", s.Status().Code.String()),
+	}
+
+	// only the configured dimensions are used, to keep the metrics cardinality under control
+	if len(p.dimensions) > 0 {
+		set := attribute.NewSet(s.Attributes()...)
+		for _, key := range p.dimensions {
+			if value, ok := set.Value(key); ok {
+				attrs = append(attrs, attribute.KeyValue{Key: key, Value: value})
+			}
+		}
+	}
+
+	ctx := context.Background()
+	opt := metric.WithAttributes(attrs...)
+
+	p.calls.Add(ctx, 1, opt)
+
+	if s.Status().Code == codes.Error {
+		p.errors.Add(ctx, 1, opt)
+	}
+
+	duration := s.EndTime().Sub(s.StartTime())
+	p.duration.Record(ctx, float64(duration.Microseconds())/1000, opt)
+}
+
+func (p *spanMetricsProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+func (p *spanMetricsProcessor) ForceFlush(context.Context) error {
+	return nil
+}
@@ -0,0 +1,112 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_SpanMetricsProcessor(t *testing.T) {
+	ctx := context.Background()
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	processor, err := newSpanMetricsProcessor(mp, []string{"tyk.api.id"})
+	assert.Nil(t, err)
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor))
+	tracer := tp.Tracer("test")
+
+	for i := 0; i < 3; i++ {
+		_, span := tracer.Start(ctx, "GET /users", trace.WithSpanKind(trace.SpanKindServer))
+		span.SetAttributes(NewAttribute("tyk.api.id", "api-1"), NewAttribute("http.target", "/users/1"))
+
+		if i == 0 {
+			span.RecordError(errors.New("upstream error"))
+			span.SetStatus(codes.Error, "upstream error")
+		}
+
+		span.End()
+	}
+
+	rm := metricdata.ResourceMetrics{}
+	assert.Nil(t, reader.Collect(ctx, &rm))
+	assert.Len(t, rm.ScopeMetrics, 1)
+
+	metrics := map[string]metricdata.Metrics{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+
+	okAttrs := attribute.NewSet(
+		attribute.String("span.name", "GET /users"),
+		attribute.String("span.kind", "server"),
+		attribute.String("status.code", "Unset"),
+		attribute.String("tyk.api.id", "api-1"),
+	)
+	errorAttrs := attribute.NewSet(
+		attribute.String("span.name", "GET /users"),
+		attribute.String("span.kind", "server"),
+		attribute.String("status.code", "Error"),
+		attribute.String("tyk.api.id", "api-1"),
+	)
+
+	calls, ok := metrics[SpanMetricsCallsName].Data.(metricdata.Sum[int64])
+	assert.True(t, ok)
+	assert.ElementsMatch(t, []metricdata.DataPoint[int64]{
+		{Attributes: okAttrs, Value: 2},
+		{Attributes: errorAttrs, Value: 1},
+	}, withoutTimestamps(calls.DataPoints))
+
+	errorsCount, ok := metrics[SpanMetricsErrorsName].Data.(metricdata.Sum[int64])
+	assert.True(t, ok)
+	assert.Equal(t, []metricdata.DataPoint[int64]{
+		{Attributes: errorAttrs, Value: 1},
+	}, withoutTimestamps(errorsCount.DataPoints))
+
+	duration, ok := metrics[SpanMetricsDurationName].Data.(metricdata.Histogram[float64])
+	assert.True(t, ok)
+	assert.Len(t, duration.DataPoints, 2)
+	assert.Equal(t, "ms", metrics[SpanMetricsDurationName].Unit)
+}
+
+func withoutTimestamps(dps []metricdata.DataPoint[int64]) []metricdata.DataPoint[int64] {
+	result := make([]metricdata.DataPoint[int64], 0, len(dps))
+	for _, dp := range dps {
+		result = append(result, metricdata.DataPoint[int64]{Attributes: dp.Attributes, Value: dp.Value})
+	}
+
+	return result
+}
+
+func Test_SpanMetricsOnlyCoverSampledSpans(t *testing.T) {
+	ctx := context.Background()
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	processor, err := newSpanMetricsProcessor(mp, nil)
+	assert.Nil(t, err)
+
+	// the spans dropped by the sampler never reach the span processors
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.TraceIDRatioBased(0)),
+		sdktrace.WithSpanProcessor(processor))
+
+	for i := 0; i < 3; i++ {
+		_, span := tp.Tracer("test").Start(ctx, "GET /users")
+		span.End()
+	}
+
+	rm := metricdata.ResourceMetrics{}
+	assert.Nil(t, reader.Collect(ctx, &rm))
+	assert.Empty(t, rm.ScopeMetrics)
+}
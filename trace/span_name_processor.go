@@ -0,0 +1,69 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// spanNameRule is a compiled span name replacement.
+type spanNameRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// spanNameProcessor is a span processor that rewrites the span names when they start,
+// applying the configured regex replacements in order. It's used to control the cardinality
+// of the span names, e.g. collapsing IDs in paths: "GET /users/123" -> "GET /users/{id}".
+// Span names changed after the span started are not rewritten.
+type spanNameProcessor struct {
+	rules []spanNameRule
+}
+
+var _ sdktrace.SpanProcessor = &spanNameProcessor{}
+
+func newSpanNameProcessor(replacements []config.SpanNameReplacement) (*spanNameProcessor, error) {
+	rules := make([]spanNameRule, 0, len(replacements))
+
+	for i, replacement := range replacements {
+		pattern, err := regexp.Compile(replacement.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid span name replacement %d: %w", i, err)
+		}
+
+		rules = append(rules, spanNameRule{
+			pattern:     pattern,
+			replacement: replacement.Replacement,
+		})
+	}
+
+	return &spanNameProcessor{rules: rules}, nil
+}
+
+func (p *spanNameProcessor) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
+	name := p.sanitize(s.Name())
+	if name != s.Name() {
+		s.SetName(name)
+	}
+}
+
+func (p *spanNameProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (p *spanNameProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+func (p *spanNameProcessor) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (p *spanNameProcessor) sanitize(name string) string {
+	for _, rule := range p.rules {
+		name = rule.pattern.ReplaceAllString(name, rule.replacement)
+	}
+
+	return name
+}
@@ -0,0 +1,86 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_SpanNameProcessor(t *testing.T) {
+	replacements := []config.SpanNameReplacement{
+		{Pattern: `/[0-9]+(/|$)`, Replacement: "/{id}$1"},
+		{Pattern: `/[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`, Replacement: "/{uuid}"},
+	}
+
+	tcs := []struct {
+		testName     string
+		spanName     string
+		expectedName string
+	}{
+		{
+			testName:     "no match",
+			spanName:     "GET /users",
+			expectedName: "GET /users",
+		},
+		{
+			testName:     "numeric id",
+			spanName:     "GET /users/123",
+			expectedName: "GET /users/{id}",
+		},
+		{
+			testName:     "multiple numeric ids",
+			spanName:     "GET /users/123/orders/456/items",
+			expectedName: "GET /users/{id}/orders/{id}/items",
+		},
+		{
+			testName:     "uuid",
+			spanName:     "DELETE /sessions/2b1e8f3c-9d4a-4c2e-8f1a-3b5d7e9f1a2c",
+			expectedName: "DELETE /sessions/{uuid}",
+		},
+	}
+
+	processor, err := newSpanNameProcessor(replacements)
+	assert.Nil(t, err)
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(
+				sdktrace.WithSpanProcessor(processor),
+				sdktrace.WithSpanProcessor(recorder),
+			)
+
+			_, span := tp.Tracer("test").Start(context.Background(), tc.spanName)
+			span.End()
+
+			spans := recorder.Ended()
+			assert.Len(t, spans, 1)
+			assert.Equal(t, tc.expectedName, spans[0].Name())
+		})
+	}
+}
+
+func Test_NewSpanNameProcessor(t *testing.T) {
+	_, err := newSpanNameProcessor([]config.SpanNameReplacement{
+		{Pattern: "/users/[0-9]+", Replacement: "/users/{id}"},
+		{Pattern: "(", Replacement: ""},
+	})
+
+	assert.ErrorContains(t, err, "invalid span name replacement 1")
+}
+
+func Test_ProviderSpanNameReplacements(t *testing.T) {
+	_, err := NewProvider(WithContext(context.Background()), WithConfig(&config.OpenTelemetry{
+		Enabled:  true,
+		Exporter: config.STDOUTEXPORTER,
+		SpanNameReplacements: []config.SpanNameReplacement{
+			{Pattern: "(", Replacement: ""},
+		},
+	}))
+
+	assert.ErrorContains(t, err, "failed to create span name processor")
+}
@@ -1,16 +1,153 @@
 package trace
 
 import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TykTechnologies/opentelemetry/config"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
-func spanProcessorFactory(spanProcessorType string, exporter sdktrace.SpanExporter) sdktrace.SpanProcessor {
-	switch spanProcessorType {
-	case "simple":
-		return newSimpleSpanProcessor(exporter)
-	default:
-		// Default to BatchSpanProcessor
-		return newBatchSpanProcessor(exporter)
+// SpanProcessorFactory creates a span processor sending the spans to the given exporter.
+// The batch settings are the ones of the pipeline, with the defaults applied.
+type SpanProcessorFactory func(exporter sdktrace.SpanExporter, batch config.Batch) sdktrace.SpanProcessor
+
+var (
+	processorFactoriesMu sync.RWMutex
+	processorFactories   = map[string]SpanProcessorFactory{
+		"simple": func(exporter sdktrace.SpanExporter, _ config.Batch) sdktrace.SpanProcessor {
+			return newSimpleSpanProcessor(exporter)
+		},
+		"batch": func(exporter sdktrace.SpanExporter, batch config.Batch) sdktrace.SpanProcessor {
+			return newBatchSpanProcessor(exporter, batchOptions(batch)...)
+		},
+		"batch_by_trace": func(exporter sdktrace.SpanExporter, batch config.Batch) sdktrace.SpanProcessor {
+			return newTraceBatchSpanProcessor(exporter, batch)
+		},
+	}
+)
+
+const (
+	defaultBatchSize      = 512
+	defaultBatchTimeout   = 5000
+	defaultBatchQueueSize = 2048
+	// maxBatchTimeout is the maximum batch timeout in milliseconds. The spans buffered for
+	// longer would reach the backends after most of their ingestion windows.
+	maxBatchTimeout = 60000
+)
+
+/*
+	RegisterProcessor makes a span processor available under the given name, so it can be selected
+	with the span_processor_type config of the exporters and pipelines. It's meant to be called
+	from an init function, and panics if the name is already registered or the factory is nil.
+
+Example
+
+	func init() {
+		trace.RegisterProcessor("mpsc", func(exporter sdktrace.SpanExporter, batch config.Batch) sdktrace.SpanProcessor {
+			return mpsc.NewSpanProcessor(exporter, batch.Size, batch.QueueSize)
+		})
+	}
+*/
+func RegisterProcessor(name string, factory SpanProcessorFactory) {
+	if factory == nil {
+		panic("trace: RegisterProcessor factory is nil")
+	}
+
+	processorFactoriesMu.Lock()
+	defer processorFactoriesMu.Unlock()
+
+	if _, ok := processorFactories[name]; ok {
+		panic(fmt.Sprintf("trace: RegisterProcessor called twice for processor %q", name))
+	}
+
+	processorFactories[name] = factory
+}
+
+func spanProcessorFactory(spanProcessorType string, exporter sdktrace.SpanExporter,
+	batch config.Batch) (sdktrace.SpanProcessor, error) {
+	factory, err := lookupProcessor(spanProcessorType)
+	if err != nil {
+		return nil, err
+	}
+
+	return factory(exporter, batchConfig(batch)), nil
+}
+
+// lookupProcessor returns the factory of the span processor registered under the given name,
+// or the batch one if the name is empty.
+func lookupProcessor(spanProcessorType string) (SpanProcessorFactory, error) {
+	if spanProcessorType == "" {
+		spanProcessorType = "batch"
+	}
+
+	processorFactoriesMu.RLock()
+	factory, ok := processorFactories[spanProcessorType]
+	processorFactoriesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("invalid span processor type: %s", spanProcessorType)
+	}
+
+	return factory, nil
+}
+
+// batchConfig returns the batch settings with the defaults applied.
+func batchConfig(batch config.Batch) config.Batch {
+	if batch.Size == 0 {
+		batch.Size = defaultBatchSize
+	}
+
+	if batch.Timeout == 0 {
+		batch.Timeout = defaultBatchTimeout
+	}
+
+	if batch.QueueSize == 0 {
+		batch.QueueSize = defaultBatchQueueSize
+	}
+
+	return batch
+}
+
+// validateBatch checks the batch settings against the limits of the batch span processor,
+// which would otherwise silently cap the batch size to the queue size.
+func validateBatch(batch config.Batch) error {
+	if batch.Size < 0 || batch.Timeout < 0 || batch.QueueSize < 0 {
+		return errors.New("invalid batch config: the size, timeout and queue size can't be negative")
+	}
+
+	batch = batchConfig(batch)
+
+	if batch.Size > batch.QueueSize {
+		return fmt.Errorf("invalid batch config: size %d exceeds the queue size %d", batch.Size, batch.QueueSize)
+	}
+
+	if batch.Timeout > maxBatchTimeout {
+		return fmt.Errorf("invalid batch config: timeout %dms exceeds %dms", batch.Timeout, maxBatchTimeout)
+	}
+
+	return nil
+}
+
+// validateSpanProcessor checks the span processor type and the batch settings of the pipeline.
+func validateSpanProcessor(cfg *config.OpenTelemetry) error {
+	if _, err := lookupProcessor(cfg.SpanProcessorType); err != nil {
+		return err
+	}
+
+	return validateBatch(cfg.Batch)
+}
+
+// batchOptions returns the options of the batch span processor for the given batch settings.
+func batchOptions(batch config.Batch) []sdktrace.BatchSpanProcessorOption {
+	batch = batchConfig(batch)
+
+	return []sdktrace.BatchSpanProcessorOption{
+		sdktrace.WithMaxExportBatchSize(batch.Size),
+		sdktrace.WithBatchTimeout(time.Duration(batch.Timeout) * time.Millisecond),
+		sdktrace.WithMaxQueueSize(batch.QueueSize),
 	}
 }
 
@@ -18,6 +155,6 @@ func newSimpleSpanProcessor(exporter sdktrace.SpanExporter) sdktrace.SpanProcess
 	return sdktrace.NewSimpleSpanProcessor(exporter)
 }
 
-func newBatchSpanProcessor(exporter sdktrace.SpanExporter) sdktrace.SpanProcessor {
-	return sdktrace.NewBatchSpanProcessor(exporter)
+func newBatchSpanProcessor(exporter sdktrace.SpanExporter, opts ...sdktrace.BatchSpanProcessorOption) sdktrace.SpanProcessor {
+	return sdktrace.NewBatchSpanProcessor(exporter, opts...)
 }
@@ -4,9 +4,12 @@ import (
 	"context"
 	"strconv"
 	"testing"
+	"time"
 
+	"github.com/TykTechnologies/opentelemetry/config"
 	"github.com/stretchr/testify/assert"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	sdktracetest "go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -177,6 +180,210 @@ func Test_NewBatchSpanProcessor(t *testing.T) {
 	})
 }
 
+func Test_SpanProcessorFactory(t *testing.T) {
+	te := testExporter{}
+
+	tcs := []struct {
+		name              string
+		spanProcessorType string
+		expectedType      sdktrace.SpanProcessor
+		expectedErr       string
+	}{
+		{
+			name:              "simple",
+			spanProcessorType: "simple",
+			expectedType:      sdktrace.NewSimpleSpanProcessor(&te),
+		},
+		{
+			name:              "batch",
+			spanProcessorType: "batch",
+			expectedType:      sdktrace.NewBatchSpanProcessor(&te),
+		},
+		{
+			name:              "batch by trace",
+			spanProcessorType: "batch_by_trace",
+			expectedType:      &traceBatchSpanProcessor{},
+		},
+		{
+			name:              "default",
+			spanProcessorType: "",
+			expectedType:      sdktrace.NewBatchSpanProcessor(&te),
+		},
+		{
+			name:              "unregistered",
+			spanProcessorType: "mpsc",
+			expectedErr:       "invalid span processor type: mpsc",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			processor, err := spanProcessorFactory(tc.spanProcessorType, &te, config.Batch{})
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				assert.Nil(t, processor)
+
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.IsType(t, tc.expectedType, processor)
+			assert.Nil(t, processor.Shutdown(context.Background()))
+		})
+	}
+}
+
+func Test_ValidateBatch(t *testing.T) {
+	tcs := []struct {
+		name        string
+		batch       config.Batch
+		expectedErr string
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name:  "custom settings",
+			batch: config.Batch{Size: 1000, Timeout: 1000, QueueSize: 10000},
+		},
+		{
+			name:        "negative value",
+			batch:       config.Batch{Timeout: -1},
+			expectedErr: "invalid batch config: the size, timeout and queue size can't be negative",
+		},
+		{
+			name:        "size exceeding the default queue size",
+			batch:       config.Batch{Size: 4096},
+			expectedErr: "invalid batch config: size 4096 exceeds the queue size 2048",
+		},
+		{
+			name:        "default size exceeding the queue size",
+			batch:       config.Batch{QueueSize: 100},
+			expectedErr: "invalid batch config: size 512 exceeds the queue size 100",
+		},
+		{
+			name:        "timeout too long",
+			batch:       config.Batch{Timeout: 60001},
+			expectedErr: "invalid batch config: timeout 60001ms exceeds 60000ms",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateBatch(tc.batch)
+			if tc.expectedErr == "" {
+				assert.Nil(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func Test_BatchOptions(t *testing.T) {
+	exporter := sdktracetest.NewInMemoryExporter()
+
+	// a single span is exported straight away when the batch size is 1
+	processor, err := spanProcessorFactory("batch", exporter, config.Batch{Size: 1})
+	assert.Nil(t, err)
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor))
+
+	_, span := tp.Tracer("test").Start(context.Background(), "span")
+	span.End()
+
+	assert.Eventually(t, func() bool {
+		return len(exporter.GetSpans()) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	assert.Nil(t, tp.Shutdown(context.Background()))
+}
+
+func Test_RegisterProcessor(t *testing.T) {
+	te := testExporter{}
+
+	var gotExporter sdktrace.SpanExporter
+
+	custom := sdktrace.NewSimpleSpanProcessor(&te)
+
+	t.Cleanup(func() {
+		processorFactoriesMu.Lock()
+		defer processorFactoriesMu.Unlock()
+
+		delete(processorFactories, "test_custom")
+	})
+
+	var gotBatch config.Batch
+
+	RegisterProcessor("test_custom", func(exporter sdktrace.SpanExporter, batch config.Batch) sdktrace.SpanProcessor {
+		gotExporter, gotBatch = exporter, batch
+		return custom
+	})
+
+	processor, err := spanProcessorFactory("test_custom", &te, config.Batch{Size: 100})
+	assert.Nil(t, err)
+	assert.Equal(t, custom, processor)
+	assert.Equal(t, &te, gotExporter)
+	// the registered processors get the batch settings with the defaults applied
+	assert.Equal(t, config.Batch{Size: 100, Timeout: 5000, QueueSize: 2048}, gotBatch)
+
+	assert.PanicsWithValue(t, `trace: RegisterProcessor called twice for processor "test_custom"`, func() {
+		RegisterProcessor("test_custom", processorFactories["simple"])
+	})
+	assert.PanicsWithValue(t, `trace: RegisterProcessor called twice for processor "batch"`, func() {
+		RegisterProcessor("batch", processorFactories["simple"])
+	})
+	assert.PanicsWithValue(t, "trace: RegisterProcessor factory is nil", func() {
+		RegisterProcessor("test_nil", nil)
+	})
+}
+
+func Test_NewTraceBatchSpanProcessor(t *testing.T) {
+	t.Parallel()
+
+	te := testExporter{}
+
+	processor := newTraceBatchSpanProcessor(&te, config.Batch{})
+	assert.NotNil(t, processor)
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	tp.RegisterSpanProcessor(processor)
+
+	traceIDs := make([]trace.TraceID, 3)
+	spanIDs := make([]trace.SpanID, 3)
+
+	for i := 0; i < 3; i++ {
+		traceID, err := trace.TraceIDFromHex("0102030405060708010204081020304" + strconv.Itoa(i))
+		assert.Nil(t, err)
+		traceIDs[i] = traceID
+
+		spanID, err := trace.SpanIDFromHex("010204081020304" + strconv.Itoa(i))
+		assert.Nil(t, err)
+		spanIDs[i] = spanID
+	}
+
+	// interleave the spans of the 3 traces: 0, 1, 2, 0, 1, 2, ...
+	spans := make([][]trace.Span, 3)
+	for i := 0; i < 3; i++ {
+		spans[i] = startTestSpan(t, tp, spanIDs[i], traceIDs[i], 3)
+	}
+
+	for j := 0; j < 3; j++ {
+		for i := 0; i < 3; i++ {
+			spans[i][j].End()
+		}
+	}
+
+	tp.ForceFlush(context.Background()) // forcing flush to avoid waiting for the batch timeout
+	assert.Equal(t, 9, len(te.spans))
+
+	for i := 0; i < 9; i++ {
+		gotTraceID := te.spans[i].SpanContext().TraceID()
+		assert.Equal(t, traceIDs[i/3], gotTraceID) // 3 contiguous spans per trace
+	}
+}
+
 func startTestSpan(t *testing.T,
 	tp trace.TracerProvider,
 	sid trace.SpanID,
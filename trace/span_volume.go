@@ -0,0 +1,120 @@
+package trace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	spanVolumeMeterName = "github.com/TykTechnologies/opentelemetry/trace/spanvolume"
+
+	// SpansStartedName is the name of the counter with the number of started spans.
+	SpansStartedName = "tyk.otel.spans.started"
+	// SpansEndedName is the name of the counter with the number of ended spans.
+	SpansEndedName = "tyk.otel.spans.ended"
+	// SpansSampledName is the name of the counter with the number of started spans that were sampled.
+	SpansSampledName = "tyk.otel.spans.sampled"
+)
+
+// spanVolume holds the counters of the started, ended and sampled spans, giving visibility into the span
+// production rate. The spans dropped by the sampler never reach the span processors, so the started and
+// sampled spans are counted by a sampler wrapper, and the ended ones by a span processor.
+type spanVolume struct {
+	started metric.Int64Counter
+	ended   metric.Int64Counter
+	sampled metric.Int64Counter
+}
+
+func newSpanVolume(mp metric.MeterProvider) (*spanVolume, error) {
+	meter := mp.Meter(spanVolumeMeterName)
+
+	started, err := meter.Int64Counter(SpansStartedName,
+		metric.WithDescription("Number of started spans."),
+		metric.WithUnit("{span}"))
+	if err != nil {
+		return nil, err
+	}
+
+	ended, err := meter.Int64Counter(SpansEndedName,
+		metric.WithDescription("Number of ended spans."),
+		metric.WithUnit("{span}"))
+	if err != nil {
+		return nil, err
+	}
+
+	sampled, err := meter.Int64Counter(SpansSampledName,
+		metric.WithDescription("Number of started spans that were sampled."),
+		metric.WithUnit("{span}"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &spanVolume{
+		started: started,
+		ended:   ended,
+		sampled: sampled,
+	}, nil
+}
+
+// sampler wraps the sampler to count the started and sampled spans.
+func (v *spanVolume) sampler(sampler sdktrace.Sampler) sdktrace.Sampler {
+	return &spanVolumeSampler{sampler: sampler, volume: v}
+}
+
+// processor returns the span processor counting the ended spans.
+func (v *spanVolume) processor() sdktrace.SpanProcessor {
+	return &spanVolumeProcessor{volume: v}
+}
+
+// spanVolumeSampler counts every span started, and the ones sampled by the wrapped sampler.
+type spanVolumeSampler struct {
+	sampler sdktrace.Sampler
+	volume  *spanVolume
+}
+
+var _ sdktrace.Sampler = &spanVolumeSampler{}
+
+func (s *spanVolumeSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := s.sampler.ShouldSample(p)
+
+	ctx := p.ParentContext
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	s.volume.started.Add(ctx, 1)
+
+	if result.Decision == sdktrace.RecordAndSample {
+		s.volume.sampled.Add(ctx, 1)
+	}
+
+	return result
+}
+
+// Description returns the description of the wrapped sampler, since the counting doesn't change the sampling.
+func (s *spanVolumeSampler) Description() string {
+	return s.sampler.Description()
+}
+
+// spanVolumeProcessor is a lightweight span processor counting the ended spans.
+type spanVolumeProcessor struct {
+	volume *spanVolume
+}
+
+var _ sdktrace.SpanProcessor = &spanVolumeProcessor{}
+
+func (p *spanVolumeProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (p *spanVolumeProcessor) OnEnd(sdktrace.ReadOnlySpan) {
+	p.volume.ended.Add(context.Background(), 1)
+}
+
+func (p *spanVolumeProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+func (p *spanVolumeProcessor) ForceFlush(context.Context) error {
+	return nil
+}
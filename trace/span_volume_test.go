@@ -0,0 +1,152 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"github.com/stretchr/testify/assert"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// collectSpanVolume returns the values of the span volume counters collected by the reader.
+func collectSpanVolume(t *testing.T, reader sdkmetric.Reader) map[string]int64 {
+	t.Helper()
+
+	rm := metricdata.ResourceMetrics{}
+	assert.Nil(t, reader.Collect(context.Background(), &rm))
+	assert.Len(t, rm.ScopeMetrics, 1)
+
+	values := map[string]int64{}
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			assert.True(t, ok)
+			assert.Len(t, sum.DataPoints, 1)
+
+			values[m.Name] = sum.DataPoints[0].Value
+		}
+	}
+
+	return values
+}
+
+func Test_SpanVolume(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("recorded spans", func(t *testing.T) {
+		reader := sdkmetric.NewManualReader()
+		mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+		volume, err := newSpanVolume(mp)
+		assert.Nil(t, err)
+
+		// every second span is recorded without being sampled
+		sampledCount := 0
+		tp := sdktrace.NewTracerProvider(
+			sdktrace.WithSpanProcessor(volume.processor()),
+			sdktrace.WithSampler(volume.sampler(testRecordSampler(func() sdktrace.SamplingDecision {
+				sampledCount++
+				if sampledCount%2 == 0 {
+					return sdktrace.RecordOnly
+				}
+
+				return sdktrace.RecordAndSample
+			}))),
+		)
+		tracer := tp.Tracer("test")
+
+		for i := 0; i < 4; i++ {
+			_, span := tracer.Start(ctx, "span")
+			if i < 3 {
+				span.End()
+			}
+		}
+
+		assert.Equal(t, map[string]int64{
+			SpansStartedName: 4,
+			SpansEndedName:   3,
+			SpansSampledName: 2,
+		}, collectSpanVolume(t, reader))
+	})
+
+	t.Run("dropped spans", func(t *testing.T) {
+		reader := sdkmetric.NewManualReader()
+		mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+		volume, err := newSpanVolume(mp)
+		assert.Nil(t, err)
+
+		tp := sdktrace.NewTracerProvider(
+			sdktrace.WithSpanProcessor(volume.processor()),
+			sdktrace.WithSampler(volume.sampler(sdktrace.TraceIDRatioBased(0))),
+		)
+		tracer := tp.Tracer("test")
+
+		for i := 0; i < 4; i++ {
+			_, span := tracer.Start(ctx, "span")
+			span.End()
+		}
+
+		// the dropped spans are counted as started, but never reach the span processors
+		values := collectSpanVolume(t, reader)
+		assert.Equal(t, int64(4), values[SpansStartedName])
+		assert.Equal(t, int64(0), values[SpansSampledName])
+		assert.Greater(t, values[SpansStartedName], values[SpansSampledName])
+	})
+}
+
+func Test_SpanVolumeWithProvider(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	reader := sdkmetric.NewManualReader()
+
+	provider, err := NewProvider(
+		WithConfig(&config.OpenTelemetry{
+			Enabled:           true,
+			Exporter:          "http",
+			Endpoint:          collector.URL,
+			ConnectionTimeout: 1,
+			Sampling:          config.Sampling{Type: config.ALWAYSOFF},
+		}),
+		WithSpanVolumeMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	assert.Nil(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, span := provider.Tracer().Start(context.Background(), "span")
+		span.End()
+	}
+
+	values := collectSpanVolume(t, reader)
+	assert.Equal(t, int64(3), values[SpansStartedName])
+	assert.Equal(t, int64(0), values[SpansSampledName])
+
+	assert.Nil(t, provider.Shutdown(context.Background()))
+}
+
+func Test_SpanVolumeSamplerDescription(t *testing.T) {
+	volume, err := newSpanVolume(sdkmetric.NewMeterProvider())
+	assert.Nil(t, err)
+
+	assert.Equal(t, "AlwaysOnSampler", volume.sampler(sdktrace.AlwaysSample()).Description())
+}
+
+// testRecordSampler is a sampler that returns the decision of the given function.
+type testRecordSampler func() sdktrace.SamplingDecision
+
+func (s testRecordSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return sdktrace.SamplingResult{Decision: s()}
+}
+
+func (s testRecordSampler) Description() string {
+	return "testRecordSampler"
+}
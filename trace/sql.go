@@ -0,0 +1,652 @@
+package trace
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const sqlTracerName = "github.com/TykTechnologies/opentelemetry/trace/sql"
+
+type SQLOption interface {
+	apply(*sqlConfig)
+}
+
+type sqlOpts struct {
+	fn func(*sqlConfig)
+}
+
+func (o *sqlOpts) apply(cfg *sqlConfig) {
+	o.fn(cfg)
+}
+
+type sqlConfig struct {
+	tracerProvider   oteltrace.TracerProvider
+	attrs            []Attribute
+	disableStatement bool
+}
+
+// WithSQLTracerProvider sets the tracer provider used to create the database spans.
+// Defaults to the global tracer provider, which is the one set by NewProvider.
+func WithSQLTracerProvider(tp oteltrace.TracerProvider) SQLOption {
+	return &sqlOpts{
+		fn: func(cfg *sqlConfig) {
+			cfg.tracerProvider = tp
+		},
+	}
+}
+
+// WithSQLSystem sets the db.system attribute of the database spans, e.g. "postgresql" or "mysql".
+func WithSQLSystem(system string) SQLOption {
+	return &sqlOpts{
+		fn: func(cfg *sqlConfig) {
+			cfg.attrs = append(cfg.attrs, semconv.DBSystemKey.String(system))
+		},
+	}
+}
+
+// WithSQLDBName sets the db.name attribute of the database spans.
+func WithSQLDBName(name string) SQLOption {
+	return &sqlOpts{
+		fn: func(cfg *sqlConfig) {
+			cfg.attrs = append(cfg.attrs, semconv.DBName(name))
+		},
+	}
+}
+
+// WithSQLStatementDisabled disables the db.statement attribute,
+// useful when the queries might contain sensitive data.
+func WithSQLStatementDisabled() SQLOption {
+	return &sqlOpts{
+		fn: func(cfg *sqlConfig) {
+			cfg.disableStatement = true
+		},
+	}
+}
+
+/*
+	WrapSQLDriver wraps the given database driver with one that creates a client span
+	for every connection, statement and transaction operation.
+	The spans are created from the span in the operation context, so they respect the
+	sampler and propagators of the tracer provider.
+
+Example
+
+	sql.Register("postgres-otel", trace.WrapSQLDriver(&pq.Driver{}, trace.WithSQLSystem("postgresql")))
+	db, err := sql.Open("postgres-otel", dsn)
+	if err != nil {
+		panic(err)
+	}
+*/
+func WrapSQLDriver(d driver.Driver, opts ...SQLOption) driver.Driver {
+	return &sqlDriver{driver: d, cfg: newSQLConfig(opts...)}
+}
+
+/*
+	WrapSQLConnector wraps the given database connector the same way as WrapSQLDriver.
+	It can be used with sql.OpenDB.
+
+Example
+
+	db := sql.OpenDB(trace.WrapSQLConnector(connector, trace.WithSQLSystem("postgresql")))
+*/
+func WrapSQLConnector(c driver.Connector, opts ...SQLOption) driver.Connector {
+	return &sqlConnector{connector: c, driver: &sqlDriver{driver: c.Driver(), cfg: newSQLConfig(opts...)}}
+}
+
+func newSQLConfig(opts ...SQLOption) *sqlConfig {
+	cfg := &sqlConfig{}
+	for _, opt := range opts {
+		opt.apply(cfg)
+	}
+
+	return cfg
+}
+
+func (cfg *sqlConfig) tracer() oteltrace.Tracer {
+	tp := cfg.tracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+
+	return tp.Tracer(sqlTracerName)
+}
+
+// startSpan starts a database client span, the returned function ends it recording the given error.
+func (cfg *sqlConfig) startSpan(ctx context.Context, name, query string) (context.Context, func(error)) {
+	attrs := cfg.attrs
+	if query != "" && !cfg.disableStatement {
+		attrs = append(attrs[:len(attrs):len(attrs)], semconv.DBStatement(query))
+	}
+
+	ctx, span := cfg.tracer().Start(ctx, name,
+		oteltrace.WithSpanKind(oteltrace.SpanKindClient),
+		oteltrace.WithAttributes(attrs...))
+
+	return ctx, func(err error) {
+		// driver.ErrSkip is not a failure, it asks database/sql to use the fallback path
+		if err != nil && !errors.Is(err, driver.ErrSkip) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		span.End()
+	}
+}
+
+type sqlDriver struct {
+	driver driver.Driver
+	cfg    *sqlConfig
+}
+
+var (
+	_ driver.Driver        = &sqlDriver{}
+	_ driver.DriverContext = &sqlDriver{}
+)
+
+func (d *sqlDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSQLConn(conn, d.cfg), nil
+}
+
+func (d *sqlDriver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.driver.(driver.DriverContext); ok {
+		connector, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+
+		return &sqlConnector{connector: connector, driver: d}, nil
+	}
+
+	return &sqlConnector{name: name, driver: d}, nil
+}
+
+type sqlConnector struct {
+	connector driver.Connector
+	name      string
+	driver    *sqlDriver
+}
+
+func (c *sqlConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	if c.connector == nil {
+		return c.driver.Open(c.name)
+	}
+
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSQLConn(conn, c.driver.cfg), nil
+}
+
+func (c *sqlConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type sqlConn struct {
+	conn driver.Conn
+	cfg  *sqlConfig
+}
+
+var (
+	_ driver.Conn               = &sqlConn{}
+	_ driver.ConnPrepareContext = &sqlConn{}
+	_ driver.ConnBeginTx        = &sqlConn{}
+	_ driver.Pinger             = &sqlConn{}
+	_ driver.SessionResetter    = &sqlConn{}
+	_ driver.Validator          = &sqlConn{}
+	_ driver.NamedValueChecker  = &sqlConn{}
+	_ driver.ExecerContext      = &sqlExecerConn{}
+	_ driver.QueryerContext     = &sqlQueryerConn{}
+	_ driver.ExecerContext      = &sqlExecerQueryerConn{}
+	_ driver.QueryerContext     = &sqlExecerQueryerConn{}
+)
+
+// database/sql converts the arguments without the statement when a connection implements ExecerContext
+// or QueryerContext, so the wrapper only implements the ones the wrapped connection implements.
+type (
+	sqlExecerConn        struct{ *sqlConn }
+	sqlQueryerConn       struct{ *sqlConn }
+	sqlExecerQueryerConn struct{ *sqlConn }
+)
+
+func newSQLConn(conn driver.Conn, cfg *sqlConfig) driver.Conn {
+	wrapped := &sqlConn{conn: conn, cfg: cfg}
+
+	_, execer := conn.(driver.ExecerContext)
+	_, queryer := conn.(driver.QueryerContext)
+
+	switch {
+	case execer && queryer:
+		return &sqlExecerQueryerConn{wrapped}
+	case execer:
+		return &sqlExecerConn{wrapped}
+	case queryer:
+		return &sqlQueryerConn{wrapped}
+	default:
+		return wrapped
+	}
+}
+
+func (c *sqlExecerConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.execContext(ctx, query, args)
+}
+
+func (c *sqlQueryerConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.queryContext(ctx, query, args)
+}
+
+func (c *sqlExecerQueryerConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.execContext(ctx, query, args)
+}
+
+func (c *sqlExecerQueryerConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.queryContext(ctx, query, args)
+}
+
+func (c *sqlConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *sqlConn) PrepareContext(ctx context.Context, query string) (stmt driver.Stmt, err error) {
+	ctx, end := c.cfg.startSpan(ctx, "sql.conn.prepare", query)
+	defer func() { end(err) }()
+
+	if cpc, ok := c.conn.(driver.ConnPrepareContext); ok {
+		stmt, err = cpc.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.conn.Prepare(query)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newSQLStmt(stmt, c.conn, query, c.cfg), nil
+}
+
+func (c *sqlConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *sqlConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *sqlConn) BeginTx(ctx context.Context, opts driver.TxOptions) (tx driver.Tx, err error) {
+	spanCtx, end := c.cfg.startSpan(ctx, "sql.conn.begin_tx", "")
+	defer func() { end(err) }()
+
+	if cbt, ok := c.conn.(driver.ConnBeginTx); ok {
+		tx, err = cbt.BeginTx(spanCtx, opts)
+	} else {
+		tx, err = beginTxFallback(spanCtx, c.conn, opts)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the commit and rollback spans are siblings of the begin span
+	return &sqlTx{tx: tx, ctx: ctx, cfg: c.cfg}, nil
+}
+
+// beginTxFallback begins a transaction on the connections without ConnBeginTx the way database/sql
+// does: the options these can't honour are rejected instead of being silently dropped.
+func beginTxFallback(ctx context.Context, conn driver.Conn, opts driver.TxOptions) (driver.Tx, error) {
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+		return nil, errors.New("sql: driver does not support non-default isolation level")
+	}
+
+	if opts.ReadOnly {
+		return nil, errors.New("sql: driver does not support read-only transactions")
+	}
+
+	tx, err := conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	// the context may be canceled while beginning the transaction
+	if err := ctx.Err(); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func (c *sqlConn) execContext(ctx context.Context, query string, args []driver.NamedValue) (res driver.Result, err error) {
+	execer, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, end := c.cfg.startSpan(ctx, "sql.conn.exec", query)
+	defer func() { end(err) }()
+
+	return execer.ExecContext(ctx, query, args)
+}
+
+func (c *sqlConn) queryContext(ctx context.Context, query string, args []driver.NamedValue) (rows driver.Rows, err error) {
+	queryer, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, end := c.cfg.startSpan(ctx, "sql.conn.query", query)
+
+	rows, err = queryer.QueryContext(ctx, query, args)
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+
+	// the query span ends once the rows are closed, so it covers the iteration of the results
+	return &sqlRows{rows: rows, end: end}, nil
+}
+
+func (c *sqlConn) Ping(ctx context.Context) (err error) {
+	pinger, ok := c.conn.(driver.Pinger)
+	if !ok {
+		return nil
+	}
+
+	ctx, end := c.cfg.startSpan(ctx, "sql.conn.ping", "")
+	defer func() { end(err) }()
+
+	return pinger.Ping(ctx)
+}
+
+func (c *sqlConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+
+	return nil
+}
+
+func (c *sqlConn) IsValid() bool {
+	if validator, ok := c.conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+
+	return true
+}
+
+func (c *sqlConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+
+	return driver.ErrSkip
+}
+
+type sqlStmt struct {
+	stmt  driver.Stmt
+	conn  driver.Conn
+	query string
+	cfg   *sqlConfig
+}
+
+var (
+	_ driver.Stmt              = &sqlStmt{}
+	_ driver.StmtExecContext   = &sqlStmt{}
+	_ driver.StmtQueryContext  = &sqlStmt{}
+	_ driver.NamedValueChecker = &sqlStmt{}
+	_ driver.ColumnConverter   = &sqlColumnConverterStmt{}
+)
+
+// sqlColumnConverterStmt is used for the statements implementing driver.ColumnConverter. It's a separate
+// type because database/sql changes how it converts the arguments when a statement implements it.
+type sqlColumnConverterStmt struct {
+	*sqlStmt
+	converter driver.ColumnConverter
+}
+
+func newSQLStmt(stmt driver.Stmt, conn driver.Conn, query string, cfg *sqlConfig) driver.Stmt {
+	wrapped := &sqlStmt{stmt: stmt, conn: conn, query: query, cfg: cfg}
+
+	if converter, ok := stmt.(driver.ColumnConverter); ok {
+		return &sqlColumnConverterStmt{sqlStmt: wrapped, converter: converter}
+	}
+
+	return wrapped
+}
+
+func (s *sqlColumnConverterStmt) ColumnConverter(idx int) driver.ValueConverter {
+	return s.converter.ColumnConverter(idx)
+}
+
+func (s *sqlStmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *sqlStmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+// CheckNamedValue prefers the checker of the statement over the one of the connection,
+// the same way database/sql does for the unwrapped driver.
+func (s *sqlStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+
+	if checker, ok := s.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+
+	return driver.ErrSkip
+}
+
+func (s *sqlStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), valuesToNamedValues(args))
+}
+
+func (s *sqlStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), valuesToNamedValues(args))
+}
+
+func (s *sqlStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (res driver.Result, err error) {
+	ctx, end := s.cfg.startSpan(ctx, "sql.stmt.exec", s.query)
+	defer func() { end(err) }()
+
+	if sec, ok := s.stmt.(driver.StmtExecContext); ok {
+		return sec.ExecContext(ctx, args)
+	}
+
+	values, err := namedValuesToValues(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.stmt.Exec(values)
+}
+
+func (s *sqlStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (rows driver.Rows, err error) {
+	ctx, end := s.cfg.startSpan(ctx, "sql.stmt.query", s.query)
+
+	if sqc, ok := s.stmt.(driver.StmtQueryContext); ok {
+		rows, err = sqc.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+
+		values, err = namedValuesToValues(args)
+		if err == nil {
+			rows, err = s.stmt.Query(values)
+		}
+	}
+
+	if err != nil {
+		end(err)
+		return nil, err
+	}
+
+	// the query span ends once the rows are closed, so it covers the iteration of the results
+	return &sqlRows{rows: rows, end: end}, nil
+}
+
+// sqlRows ends the span of its query once closed. The optional column type and result set interfaces
+// fall back to the values database/sql uses when the wrapped rows don't implement them.
+type sqlRows struct {
+	rows driver.Rows
+	end  func(error)
+	err  error
+}
+
+var (
+	_ driver.Rows                           = &sqlRows{}
+	_ driver.RowsNextResultSet              = &sqlRows{}
+	_ driver.RowsColumnTypeScanType         = &sqlRows{}
+	_ driver.RowsColumnTypeDatabaseTypeName = &sqlRows{}
+	_ driver.RowsColumnTypeLength           = &sqlRows{}
+	_ driver.RowsColumnTypeNullable         = &sqlRows{}
+	_ driver.RowsColumnTypePrecisionScale   = &sqlRows{}
+)
+
+func (r *sqlRows) Columns() []string {
+	return r.rows.Columns()
+}
+
+func (r *sqlRows) Close() error {
+	err := r.rows.Close()
+	if r.err == nil {
+		r.err = err
+	}
+
+	r.end(r.err)
+
+	return err
+}
+
+func (r *sqlRows) Next(dest []driver.Value) error {
+	err := r.rows.Next(dest)
+	r.recordErr(err)
+
+	return err
+}
+
+func (r *sqlRows) HasNextResultSet() bool {
+	if next, ok := r.rows.(driver.RowsNextResultSet); ok {
+		return next.HasNextResultSet()
+	}
+
+	return false
+}
+
+func (r *sqlRows) NextResultSet() error {
+	next, ok := r.rows.(driver.RowsNextResultSet)
+	if !ok {
+		return io.EOF
+	}
+
+	err := next.NextResultSet()
+	r.recordErr(err)
+
+	return err
+}
+
+func (r *sqlRows) ColumnTypeScanType(index int) reflect.Type {
+	if ct, ok := r.rows.(driver.RowsColumnTypeScanType); ok {
+		return ct.ColumnTypeScanType(index)
+	}
+
+	return reflect.TypeOf(new(any)).Elem()
+}
+
+func (r *sqlRows) ColumnTypeDatabaseTypeName(index int) string {
+	if ct, ok := r.rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return ct.ColumnTypeDatabaseTypeName(index)
+	}
+
+	return ""
+}
+
+func (r *sqlRows) ColumnTypeLength(index int) (length int64, ok bool) {
+	if ct, ok := r.rows.(driver.RowsColumnTypeLength); ok {
+		return ct.ColumnTypeLength(index)
+	}
+
+	return 0, false
+}
+
+func (r *sqlRows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	if ct, ok := r.rows.(driver.RowsColumnTypeNullable); ok {
+		return ct.ColumnTypeNullable(index)
+	}
+
+	return false, false
+}
+
+func (r *sqlRows) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	if ct, ok := r.rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return ct.ColumnTypePrecisionScale(index)
+	}
+
+	return 0, 0, false
+}
+
+// recordErr keeps the first iteration error for the span, io.EOF only marks the end of the results.
+func (r *sqlRows) recordErr(err error) {
+	if err != nil && !errors.Is(err, io.EOF) && r.err == nil {
+		r.err = err
+	}
+}
+
+type sqlTx struct {
+	tx  driver.Tx
+	ctx context.Context
+	cfg *sqlConfig
+}
+
+func (t *sqlTx) Commit() (err error) {
+	_, end := t.cfg.startSpan(t.ctx, "sql.tx.commit", "")
+	defer func() { end(err) }()
+
+	return t.tx.Commit()
+}
+
+func (t *sqlTx) Rollback() (err error) {
+	_, end := t.cfg.startSpan(t.ctx, "sql.tx.rollback", "")
+	defer func() { end(err) }()
+
+	return t.tx.Rollback()
+}
+
+func valuesToNamedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+
+	return named
+}
+
+func namedValuesToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("sql: driver does not support the use of Named Parameters")
+		}
+
+		values[i] = arg.Value
+	}
+
+	return values, nil
+}
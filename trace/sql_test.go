@@ -0,0 +1,356 @@
+package trace
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+)
+
+var errTestQuery = errors.New("query failed")
+
+// testSQLDriver is a minimal driver that only implements the mandatory interfaces,
+// so database/sql uses the prepared statement fallbacks.
+type testSQLDriver struct{}
+
+func (d *testSQLDriver) Open(name string) (driver.Conn, error) {
+	return &testSQLConn{}, nil
+}
+
+type testSQLConnector struct {
+	prepare func(query string) driver.Stmt
+	queryer bool
+}
+
+func (c *testSQLConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn := &testSQLConn{prepare: c.prepare}
+	if c.queryer {
+		return &testSQLQueryerConn{conn}, nil
+	}
+
+	return conn, nil
+}
+
+func (c *testSQLConnector) Driver() driver.Driver {
+	return &testSQLDriver{}
+}
+
+type testSQLConn struct {
+	prepare func(query string) driver.Stmt
+}
+
+func (c *testSQLConn) Prepare(query string) (driver.Stmt, error) {
+	if c.prepare != nil {
+		return c.prepare(query), nil
+	}
+
+	return &testSQLStmt{query: query}, nil
+}
+
+func (c *testSQLConn) Close() error {
+	return nil
+}
+
+func (c *testSQLConn) Begin() (driver.Tx, error) {
+	return &testSQLTx{}, nil
+}
+
+// testSQLQueryerConn runs the queries without preparing them.
+type testSQLQueryerConn struct {
+	*testSQLConn
+}
+
+func (c *testSQLQueryerConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func (c *testSQLQueryerConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return &testSQLRows{}, nil
+}
+
+type testSQLStmt struct {
+	query string
+}
+
+func (s *testSQLStmt) Close() error {
+	return nil
+}
+
+func (s *testSQLStmt) NumInput() int {
+	return -1
+}
+
+func (s *testSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if s.query == "fail" {
+		return nil, errTestQuery
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+func (s *testSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if s.query == "fail rows" {
+		return &testSQLRows{err: errTestQuery}, nil
+	}
+
+	return &testSQLRows{}, nil
+}
+
+// testSQLArg is an argument database/sql can't convert without the help of the driver.
+type testSQLArg struct {
+	id int64
+}
+
+type testSQLConverter struct{}
+
+func (c testSQLConverter) ConvertValue(v any) (driver.Value, error) {
+	if arg, ok := v.(testSQLArg); ok {
+		return arg.id, nil
+	}
+
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+type testSQLConverterStmt struct {
+	testSQLStmt
+}
+
+func (s *testSQLConverterStmt) ColumnConverter(idx int) driver.ValueConverter {
+	return testSQLConverter{}
+}
+
+type testSQLCheckerStmt struct {
+	testSQLStmt
+}
+
+func (s *testSQLCheckerStmt) CheckNamedValue(nv *driver.NamedValue) (err error) {
+	nv.Value, err = testSQLConverter{}.ConvertValue(nv.Value)
+	return err
+}
+
+type testSQLRows struct {
+	err error
+}
+
+func (r *testSQLRows) Columns() []string {
+	return []string{"id"}
+}
+
+func (r *testSQLRows) Close() error {
+	return nil
+}
+
+func (r *testSQLRows) Next(dest []driver.Value) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	return io.EOF
+}
+
+type testSQLTx struct{}
+
+func (t *testSQLTx) Commit() error {
+	return nil
+}
+
+func (t *testSQLTx) Rollback() error {
+	return nil
+}
+
+func Test_WrapSQLDriver(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	sql.Register("test-otel", WrapSQLDriver(&testSQLDriver{},
+		WithSQLTracerProvider(tp),
+		WithSQLSystem("postgresql"),
+		WithSQLDBName("tyk"),
+	))
+
+	db, err := sql.Open("test-otel", "")
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+
+	t.Run("exec", func(t *testing.T) {
+		exporter.Reset()
+
+		_, err := db.ExecContext(ctx, "INSERT INTO apis VALUES (1)")
+		assert.Nil(t, err)
+
+		spans := exporter.GetSpans()
+		assert.Equal(t, []string{"sql.conn.prepare", "sql.stmt.exec"}, spanNames(spans))
+
+		for _, span := range spans {
+			assert.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+			assert.Contains(t, span.Attributes, semconv.DBSystemKey.String("postgresql"))
+			assert.Contains(t, span.Attributes, semconv.DBName("tyk"))
+			assert.Contains(t, span.Attributes, semconv.DBStatement("INSERT INTO apis VALUES (1)"))
+		}
+	})
+
+	t.Run("query", func(t *testing.T) {
+		exporter.Reset()
+
+		rows, err := db.QueryContext(ctx, "SELECT id FROM apis")
+		assert.Nil(t, err)
+
+		// the query span covers the iteration of the rows
+		assert.Equal(t, []string{"sql.conn.prepare"}, spanNames(exporter.GetSpans()))
+		assert.Nil(t, rows.Close())
+
+		assert.Equal(t, []string{"sql.conn.prepare", "sql.stmt.query"}, spanNames(exporter.GetSpans()))
+	})
+
+	t.Run("rows error", func(t *testing.T) {
+		exporter.Reset()
+
+		rows, err := db.QueryContext(ctx, "fail rows")
+		assert.Nil(t, err)
+		assert.False(t, rows.Next())
+		assert.ErrorIs(t, rows.Err(), errTestQuery)
+		assert.Nil(t, rows.Close())
+
+		spans := exporter.GetSpans()
+		assert.Equal(t, []string{"sql.conn.prepare", "sql.stmt.query"}, spanNames(spans))
+		assert.Equal(t, codes.Error, spans[1].Status.Code)
+		assert.Equal(t, errTestQuery.Error(), spans[1].Status.Description)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		exporter.Reset()
+
+		_, err := db.ExecContext(ctx, "fail")
+		assert.ErrorIs(t, err, errTestQuery)
+
+		spans := exporter.GetSpans()
+		assert.Equal(t, []string{"sql.conn.prepare", "sql.stmt.exec"}, spanNames(spans))
+		assert.Equal(t, codes.Unset, spans[0].Status.Code)
+		assert.Equal(t, codes.Error, spans[1].Status.Code)
+		assert.Equal(t, errTestQuery.Error(), spans[1].Status.Description)
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		exporter.Reset()
+
+		tx, err := db.BeginTx(ctx, nil)
+		assert.Nil(t, err)
+		assert.Nil(t, tx.Commit())
+
+		spans := exporter.GetSpans()
+		assert.Equal(t, []string{"sql.conn.begin_tx", "sql.tx.commit"}, spanNames(spans))
+		assert.Equal(t, parent.SpanContext().SpanID(), spans[1].Parent.SpanID())
+	})
+
+	t.Run("transaction options not supported by the driver", func(t *testing.T) {
+		exporter.Reset()
+
+		_, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		assert.EqualError(t, err, "sql: driver does not support read-only transactions")
+
+		_, err = db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+		assert.EqualError(t, err, "sql: driver does not support non-default isolation level")
+
+		spans := exporter.GetSpans()
+		assert.Equal(t, []string{"sql.conn.begin_tx", "sql.conn.begin_tx"}, spanNames(spans))
+		assert.Equal(t, codes.Error, spans[0].Status.Code)
+	})
+}
+
+func Test_WrapSQLConnector(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	db := sql.OpenDB(WrapSQLConnector(&testSQLConnector{}, WithSQLTracerProvider(tp), WithSQLStatementDisabled()))
+	defer db.Close()
+
+	_, err := db.ExecContext(context.Background(), "DELETE FROM apis")
+	assert.Nil(t, err)
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, []string{"sql.conn.prepare", "sql.stmt.exec"}, spanNames(spans))
+
+	for _, span := range spans {
+		for _, attr := range span.Attributes {
+			assert.NotEqual(t, semconv.DBStatementKey, attr.Key)
+		}
+	}
+}
+
+func Test_WrapSQLConnector_Queryer(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	db := sql.OpenDB(WrapSQLConnector(&testSQLConnector{queryer: true}, WithSQLTracerProvider(tp)))
+	defer db.Close()
+
+	_, err := db.ExecContext(context.Background(), "DELETE FROM apis")
+	assert.Nil(t, err)
+
+	rows, err := db.QueryContext(context.Background(), "SELECT id FROM apis")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"sql.conn.exec"}, spanNames(exporter.GetSpans()))
+	assert.Nil(t, rows.Close())
+
+	assert.Equal(t, []string{"sql.conn.exec", "sql.conn.query"}, spanNames(exporter.GetSpans()))
+}
+
+func Test_WrapSQLDriver_ArgumentConversion(t *testing.T) {
+	tcs := []struct {
+		name    string
+		prepare func(query string) driver.Stmt
+		wantErr bool
+	}{
+		{
+			name:    "default conversion",
+			wantErr: true,
+		},
+		{
+			name: "column converter",
+			prepare: func(query string) driver.Stmt {
+				return &testSQLConverterStmt{testSQLStmt{query: query}}
+			},
+		},
+		{
+			name: "named value checker",
+			prepare: func(query string) driver.Stmt {
+				return &testSQLCheckerStmt{testSQLStmt{query: query}}
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			db := sql.OpenDB(WrapSQLConnector(&testSQLConnector{prepare: tc.prepare}))
+			defer db.Close()
+
+			_, err := db.ExecContext(context.Background(), "INSERT INTO apis VALUES (?)", testSQLArg{id: 1})
+			if tc.wantErr {
+				assert.ErrorContains(t, err, "unsupported type")
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func spanNames(spans tracetest.SpanStubs) []string {
+	names := make([]string, 0, len(spans))
+	for _, span := range spans {
+		names = append(names, span.Name)
+	}
+
+	return names
+}
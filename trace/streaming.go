@@ -0,0 +1,94 @@
+package trace
+
+import (
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// HTTPTimeToFirstByteKey is the time between the start of the handler and the first written byte, in milliseconds.
+	HTTPTimeToFirstByteKey = attribute.Key("http.response.time_to_first_byte")
+	// HTTPStreamBytesKey is the number of bytes streamed in the response body.
+	HTTPStreamBytesKey = attribute.Key("http.response.stream.bytes")
+	// HTTPStreamFlushesKey is the number of times the response was flushed to the client.
+	HTTPStreamFlushesKey = attribute.Key("http.response.stream.flushes")
+)
+
+var (
+	_ http.Flusher = &streamingResponseWriter{}
+)
+
+// streamingResponseWriter wraps an http.ResponseWriter and keeps track of the streaming of the response.
+type streamingResponseWriter struct {
+	http.ResponseWriter
+	start     time.Time
+	firstByte time.Time
+	bytes     int64
+	flushes   int64
+}
+
+func (rw *streamingResponseWriter) Write(p []byte) (int, error) {
+	n, err := rw.ResponseWriter.Write(p)
+
+	if n > 0 && rw.firstByte.IsZero() {
+		rw.firstByte = time.Now()
+	}
+
+	rw.bytes += int64(n)
+
+	return n, err
+}
+
+func (rw *streamingResponseWriter) Flush() {
+	rw.flushes++
+
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the wrapped http.ResponseWriter, so http.ResponseController can reach it.
+func (rw *streamingResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+func (rw *streamingResponseWriter) attributes() []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		HTTPStreamBytesKey.Int64(rw.bytes),
+		HTTPStreamFlushesKey.Int64(rw.flushes),
+	}
+
+	if !rw.firstByte.IsZero() {
+		attrs = append(attrs, HTTPTimeToFirstByteKey.Float64(float64(rw.firstByte.Sub(rw.start))/float64(time.Millisecond)))
+	}
+
+	return attrs
+}
+
+// NewStreamingHandler wraps the handler of streaming responses, like SSE or chunked LLM responses,
+// recording the time to first byte, the streamed bytes and the number of flushes as attributes
+// of the span in the request context. It's meant to be wrapped by NewHTTPHandler:
+//
+//	handler := trace.NewHTTPHandler("stream", trace.NewStreamingHandler(streamHandler), tp)
+func NewStreamingHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span := SpanFromContext(r.Context())
+		if !span.IsRecording() {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		rw := &streamingResponseWriter{
+			ResponseWriter: w,
+			start:          time.Now(),
+		}
+
+		defer func() {
+			span.SetAttributes(rw.attributes()...)
+		}()
+
+		handler.ServeHTTP(rw, r)
+	})
+}
@@ -0,0 +1,58 @@
+package trace
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStreamingHandler(t *testing.T) {
+	provider, exporter := newInMemoryProvider()
+
+	handler := NewHTTPHandler("stream", NewStreamingHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		for _, event := range []string{"data: first\n\n", "data: second\n\n"} {
+			_, err := w.Write([]byte(event))
+			assert.Nil(t, err)
+
+			w.(http.Flusher).Flush()
+		}
+	})), provider)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	assert.Equal(t, "data: first\n\ndata: second\n\n", recorder.Body.String())
+	assert.True(t, recorder.Flushed)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+
+	attrs := spans[0].Attributes
+	assert.Contains(t, attrs, HTTPStreamBytesKey.Int64(27))
+	assert.Contains(t, attrs, HTTPStreamFlushesKey.Int64(2))
+
+	ttfb, ok := attributeValue(spans[0], HTTPTimeToFirstByteKey)
+	assert.True(t, ok)
+	assert.GreaterOrEqual(t, ttfb.AsFloat64(), float64(0))
+}
+
+func TestNewStreamingHandlerWithoutResponse(t *testing.T) {
+	provider, exporter := newInMemoryProvider()
+
+	handler := NewHTTPHandler("stream", NewStreamingHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})), provider)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes, HTTPStreamBytesKey.Int64(0))
+
+	_, ok := attributeValue(spans[0], HTTPTimeToFirstByteKey)
+	assert.False(t, ok)
+}
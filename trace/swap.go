@@ -0,0 +1,280 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"go.opentelemetry.io/otel"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+)
+
+// SwappableProvider is a Provider that delegates to a replaceable provider.
+// It allows to replace the provider at runtime, for example after a configuration
+// change, while the spans started under the previous provider still end and flush
+// through it. The tracers returned by the SwappableProvider always start the spans
+// with the current provider, so they can be stored by the callers.
+type SwappableProvider struct {
+	current        atomic.Pointer[providerGeneration]
+	tracerProvider *swapTracerProvider
+	shutdownHooks  shutdownHooks
+	closed         atomic.Bool
+}
+
+var (
+	_ Provider          = &SwappableProvider{}
+	_ ProviderLifecycle = &SwappableProvider{}
+)
+
+/*
+	NewSwappableProvider returns a SwappableProvider delegating to the given provider,
+	and sets it as the global OpenTelemetry tracer provider.
+
+Example
+
+	provider, err := trace.NewProvider(trace.WithConfig(cfg))
+	if err != nil {
+		panic(err)
+	}
+
+	swappable := trace.NewSwappableProvider(provider)
+
+	newProvider, err := trace.NewProvider(trace.WithConfig(newCfg))
+	if err != nil {
+		panic(err)
+	}
+
+	err = swappable.Swap(ctx, newProvider)
+*/
+func NewSwappableProvider(provider Provider) *SwappableProvider {
+	s := &SwappableProvider{}
+	s.tracerProvider = &swapTracerProvider{swappable: s}
+	s.current.Store(newProviderGeneration(provider))
+
+	otel.SetTracerProvider(s.tracerProvider)
+
+	return s
+}
+
+// Swap replaces the current provider by the given one. The new spans are started with
+// the new provider straight away, while the previous provider is shut down once all the
+// spans started under it have ended, so they are flushed. It blocks until the previous
+// provider is shut down or the context is done, in which case the previous provider is
+// shut down without waiting for the in-flight spans. The previous provider stops accepting
+// new spans before the wait, so the spans started meanwhile don't delay it.
+// The new provider emits a ConfigReloaded event to its listeners once the swap is complete.
+func (s *SwappableProvider) Swap(ctx context.Context, provider Provider) error {
+	previous := s.current.Swap(newProviderGeneration(provider))
+
+	// NewProvider replaces the global tracer provider, so it's set back to the swappable one
+	otel.SetTracerProvider(s.tracerProvider)
+
+	drained := previous.drain()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+	}
+
+	// the shutdown gets its own context, so the previous provider is flushed
+	// even if the wait for the in-flight spans was cancelled
+	err := previous.provider.Shutdown(context.WithoutCancel(ctx))
+
+	if publisher, ok := provider.(eventPublisher); ok {
+		publisher.publish(Event{Type: ConfigReloaded})
+	}
+
+	return err
+}
+
+// Provider returns the current provider.
+func (s *SwappableProvider) Provider() Provider {
+	return s.current.Load().provider
+}
+
+// Shutdown calls the functions registered with OnShutdown and shuts down the current provider.
+func (s *SwappableProvider) Shutdown(ctx context.Context) error {
+	s.closed.Store(true)
+
+	hooksErr := s.shutdownHooks.run(ctx)
+
+	return errors.Join(hooksErr, s.Provider().Shutdown(ctx))
+}
+
+// EffectiveConfig returns the configuration of the current provider, or an empty configuration
+// if it doesn't implement ProviderLifecycle.
+func (s *SwappableProvider) EffectiveConfig() config.OpenTelemetry {
+	if lifecycle, ok := s.Provider().(ProviderLifecycle); ok {
+		return lifecycle.EffectiveConfig()
+	}
+
+	return config.OpenTelemetry{}
+}
+
+// Closed returns true once the SwappableProvider or its current provider has been shut down.
+func (s *SwappableProvider) Closed() bool {
+	if s.closed.Load() {
+		return true
+	}
+
+	lifecycle, ok := s.Provider().(ProviderLifecycle)
+
+	return ok && lifecycle.Closed()
+}
+
+// OnShutdown registers a function called when the SwappableProvider is shut down. The functions
+// registered on the swapped providers are called when they are replaced instead.
+func (s *SwappableProvider) OnShutdown(hook func(context.Context) error) {
+	s.shutdownHooks.add(hook)
+}
+
+func (s *SwappableProvider) Tracer() Tracer {
+	return &swapTracer{
+		swappable: s,
+		tracer: func(p Provider) oteltrace.Tracer {
+			return p.Tracer()
+		},
+	}
+}
+
+func (s *SwappableProvider) Type() string {
+	return s.Provider().Type()
+}
+
+func (s *SwappableProvider) Enabled() bool {
+	return s.Provider().Enabled()
+}
+
+// ForceFlush flushes the current provider. It's a noop if it doesn't implement ProviderLifecycle.
+func (s *SwappableProvider) ForceFlush(ctx context.Context) error {
+	if lifecycle, ok := s.Provider().(ProviderLifecycle); ok {
+		return lifecycle.ForceFlush(ctx)
+	}
+
+	return nil
+}
+
+func (s *SwappableProvider) TracerProvider() oteltrace.TracerProvider {
+	return s.tracerProvider
+}
+
+// acquire returns the current generation with an in-flight span registered.
+func (s *SwappableProvider) acquire() *providerGeneration {
+	for {
+		generation := s.current.Load()
+		if generation.acquire() {
+			return generation
+		}
+		// the generation started draining after it was loaded, so the new one is loaded
+	}
+}
+
+// providerGeneration tracks the in-flight spans of a provider, so it can be shut
+// down once all of them have ended.
+type providerGeneration struct {
+	provider Provider
+
+	mu       sync.Mutex
+	inFlight int
+	draining bool
+	drained  chan struct{}
+}
+
+func newProviderGeneration(provider Provider) *providerGeneration {
+	return &providerGeneration{
+		provider: provider,
+		drained:  make(chan struct{}),
+	}
+}
+
+// acquire registers an in-flight span. It returns false once the generation is draining,
+// so the new spans can't delay its shutdown.
+func (g *providerGeneration) acquire() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.draining {
+		return false
+	}
+
+	g.inFlight++
+
+	return true
+}
+
+func (g *providerGeneration) release() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.inFlight--
+
+	if g.draining && g.inFlight == 0 {
+		close(g.drained)
+	}
+}
+
+// drain stops accepting new spans and returns a channel closed once all the in-flight spans have ended.
+func (g *providerGeneration) drain() <-chan struct{} {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.draining {
+		g.draining = true
+
+		if g.inFlight == 0 {
+			close(g.drained)
+		}
+	}
+
+	return g.drained
+}
+
+type swapTracerProvider struct {
+	embedded.TracerProvider
+	swappable *SwappableProvider
+}
+
+func (tp *swapTracerProvider) Tracer(name string, opts ...oteltrace.TracerOption) oteltrace.Tracer {
+	return &swapTracer{
+		swappable: tp.swappable,
+		tracer: func(p Provider) oteltrace.Tracer {
+			return p.TracerProvider().Tracer(name, opts...)
+		},
+	}
+}
+
+// swapTracer starts the spans with the current provider, tracking them until they end.
+type swapTracer struct {
+	embedded.Tracer
+	swappable *SwappableProvider
+	tracer    func(Provider) oteltrace.Tracer
+}
+
+func (t *swapTracer) Start(ctx context.Context, spanName string, opts ...oteltrace.SpanStartOption) (context.Context, oteltrace.Span) {
+	generation := t.swappable.acquire()
+
+	ctx, span := t.tracer(generation.provider).Start(ctx, spanName, opts...)
+	span = &swapSpan{Span: span, generation: generation, provider: t.swappable.tracerProvider}
+
+	// store the wrapped span, so the spans ended from the context are also tracked
+	return oteltrace.ContextWithSpan(ctx, span), span
+}
+
+type swapSpan struct {
+	oteltrace.Span
+	generation *providerGeneration
+	provider   *swapTracerProvider
+	ended      sync.Once
+}
+
+func (s *swapSpan) End(opts ...oteltrace.SpanEndOption) {
+	s.Span.End(opts...)
+	s.ended.Do(s.generation.release)
+}
+
+func (s *swapSpan) TracerProvider() oteltrace.TracerProvider {
+	return s.provider
+}
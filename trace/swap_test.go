@@ -0,0 +1,213 @@
+package trace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// keepSpansExporter keeps the exported spans after the shutdown, so they can be checked
+// once the provider is swapped.
+type keepSpansExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+func (e keepSpansExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+// externalProvider is a provider implemented outside this package, without ProviderLifecycle.
+type externalProvider struct {
+	Provider
+}
+
+func newInMemoryProvider() (*traceProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(keepSpansExporter{exporter}))
+
+	return &traceProvider{
+		traceProvider:        tracerProvider,
+		providerShutdownFn:   tracerProvider.Shutdown,
+		providerForceFlushFn: tracerProvider.ForceFlush,
+		cfg:                  &config.OpenTelemetry{ResourceName: "test", ConnectionTimeout: 1},
+		providerType:         OTEL_PROVIDER,
+	}, exporter
+}
+
+func TestSwappableProvider(t *testing.T) {
+	t.Run("new spans use the new provider", func(t *testing.T) {
+		oldProvider, oldExporter := newInMemoryProvider()
+		newProvider, newExporter := newInMemoryProvider()
+
+		swappable := NewSwappableProvider(oldProvider)
+		tracer := swappable.Tracer()
+
+		_, span := tracer.Start(context.Background(), "old")
+		span.End()
+
+		assert.Nil(t, swappable.Swap(context.Background(), newProvider))
+		assert.Equal(t, newProvider, swappable.Provider())
+
+		_, span = tracer.Start(context.Background(), "new")
+		span.End()
+
+		_, span = otel.Tracer("global").Start(context.Background(), "global")
+		span.End()
+
+		assert.Equal(t, []string{"old"}, spanNames(oldExporter.GetSpans()))
+		assert.Equal(t, []string{"new", "global"}, spanNames(newExporter.GetSpans()))
+	})
+
+	t.Run("in-flight spans end with the old provider", func(t *testing.T) {
+		oldProvider, oldExporter := newInMemoryProvider()
+		newProvider, newExporter := newInMemoryProvider()
+
+		swappable := NewSwappableProvider(oldProvider)
+
+		ctx, _ := swappable.Tracer().Start(context.Background(), "parent")
+		_, child := swappable.TracerProvider().Tracer("test").Start(ctx, "child")
+
+		swapped := make(chan error)
+		go func() {
+			swapped <- swappable.Swap(context.Background(), newProvider)
+		}()
+
+		child.End()
+
+		select {
+		case <-swapped:
+			t.Fatal("the old provider was shut down with in-flight spans")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		// the span is ended from the context, as the instrumentations do
+		SpanFromContext(ctx).End()
+
+		assert.Nil(t, <-swapped)
+		assert.Equal(t, []string{"child", "parent"}, spanNames(oldExporter.GetSpans()))
+		assert.Len(t, newExporter.GetSpans(), 0)
+	})
+
+	t.Run("cancelled swap doesn't wait for the in-flight spans", func(t *testing.T) {
+		oldProvider, _ := newInMemoryProvider()
+		newProvider, _ := newInMemoryProvider()
+
+		swappable := NewSwappableProvider(oldProvider)
+
+		_, span := swappable.Tracer().Start(context.Background(), "span")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.Nil(t, swappable.Swap(ctx, newProvider))
+
+		// ending the span after the shutdown is safe
+		span.End()
+		span.End()
+	})
+
+	t.Run("delegates to the current provider", func(t *testing.T) {
+		swappable := NewSwappableProvider(&traceProvider{
+			traceProvider: sdktrace.NewTracerProvider(),
+			cfg:           &config.OpenTelemetry{ConnectionTimeout: 1},
+			providerType:  NOOP_PROVIDER,
+		})
+
+		assert.Equal(t, NOOP_PROVIDER, swappable.Type())
+		assert.False(t, swappable.Enabled())
+
+		provider, _ := newInMemoryProvider()
+		assert.Nil(t, swappable.Swap(context.Background(), provider))
+
+		assert.Equal(t, OTEL_PROVIDER, swappable.Type())
+		assert.True(t, swappable.Enabled())
+		assert.Nil(t, swappable.ForceFlush(context.Background()))
+		assert.Nil(t, swappable.Shutdown(context.Background()))
+	})
+	t.Run("new spans aren't acquired by the draining provider", func(t *testing.T) {
+		oldProvider, oldExporter := newInMemoryProvider()
+		newProvider, newExporter := newInMemoryProvider()
+
+		swappable := NewSwappableProvider(oldProvider)
+		previous := swappable.current.Load()
+
+		// a leaked span keeps the old provider draining
+		_, leaked := swappable.Tracer().Start(context.Background(), "leaked")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		swapped := make(chan error)
+		go func() {
+			swapped <- swappable.Swap(ctx, newProvider)
+		}()
+
+		assert.Eventually(t, func() bool {
+			previous.mu.Lock()
+			defer previous.mu.Unlock()
+
+			return previous.draining
+		}, time.Second, time.Millisecond)
+
+		assert.False(t, previous.acquire())
+
+		_, span := swappable.Tracer().Start(context.Background(), "new")
+		span.End()
+
+		assert.Nil(t, <-swapped)
+		leaked.End()
+
+		assert.Empty(t, oldExporter.GetSpans())
+		assert.Equal(t, []string{"new"}, spanNames(newExporter.GetSpans()))
+	})
+
+	t.Run("closed once shut down", func(t *testing.T) {
+		provider, _ := newInMemoryProvider()
+		swappable := NewSwappableProvider(externalProvider{provider})
+
+		assert.False(t, swappable.Closed())
+		assert.Nil(t, swappable.Shutdown(context.Background()))
+		assert.True(t, swappable.Closed())
+	})
+
+	t.Run("provider without lifecycle", func(t *testing.T) {
+		provider, exporter := newInMemoryProvider()
+		provider.cfg.Enabled = true
+		swappable := NewSwappableProvider(externalProvider{provider})
+
+		_, span := swappable.Tracer().Start(context.Background(), "span")
+		span.End()
+
+		assert.Nil(t, swappable.ForceFlush(context.Background()))
+		assert.Equal(t, config.OpenTelemetry{}, swappable.EffectiveConfig())
+		assert.Equal(t, []string{"span"}, spanNames(exporter.GetSpans()))
+	})
+
+	t.Run("shutdown hooks", func(t *testing.T) {
+		oldProvider, _ := newInMemoryProvider()
+		newProvider, _ := newInMemoryProvider()
+		calls := []string{}
+
+		swappable := NewSwappableProvider(oldProvider)
+		swappable.OnShutdown(func(context.Context) error {
+			calls = append(calls, "swappable")
+			return nil
+		})
+		oldProvider.OnShutdown(func(context.Context) error {
+			calls = append(calls, "old")
+			return nil
+		})
+
+		assert.Nil(t, swappable.Swap(context.Background(), newProvider))
+		assert.Equal(t, []string{"old"}, calls)
+
+		assert.Nil(t, swappable.Shutdown(context.Background()))
+		assert.Equal(t, []string{"old", "swappable"}, calls)
+	})
+}
@@ -0,0 +1,267 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// traceBatchExportTimeout is the timeout of the exports triggered by the batch window.
+const traceBatchExportTimeout = 30 * time.Second
+
+// traceBatchSpanProcessor buffers the ended spans by trace and exports every trace in a single batch,
+// so the collectors doing tail sampling receive the spans of a trace together instead of split across
+// the batches cut by the BatchSpanProcessor.
+// A trace is exported at the end of the batch window once its local root span ended. The traces still
+// waiting for their local root are kept for one more window, and exported as they are after it, so the
+// long traces don't stay in memory. When the queue is full, the oldest traces are exported as they are
+// to make room for a batch, and the spans ending before the room is made are dropped.
+type traceBatchSpanProcessor struct {
+	exporter sdktrace.SpanExporter
+	batch    config.Batch
+
+	mu     sync.Mutex
+	traces map[oteltrace.TraceID]*bufferedTrace
+	order  []oteltrace.TraceID
+	queued int
+	closed bool
+
+	exportMu sync.Mutex
+	flush    chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+
+	// runCtx is the parent context of the exports of the run loop, cancelled when the shutdown
+	// context is done before the run loop ends.
+	runCtx    context.Context
+	cancelRun context.CancelFunc
+}
+
+type bufferedTrace struct {
+	spans []sdktrace.ReadOnlySpan
+	// complete is set once the local root span of the trace ended.
+	complete bool
+	// windows is the number of batch windows the trace was kept for.
+	windows int
+}
+
+var _ sdktrace.SpanProcessor = &traceBatchSpanProcessor{}
+
+func newTraceBatchSpanProcessor(exporter sdktrace.SpanExporter, batch config.Batch) *traceBatchSpanProcessor {
+	p := &traceBatchSpanProcessor{
+		exporter: exporter,
+		batch:    batchConfig(batch),
+		traces:   map[oteltrace.TraceID]*bufferedTrace{},
+		flush:    make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	p.runCtx, p.cancelRun = context.WithCancel(context.Background())
+
+	go p.run()
+
+	return p
+}
+
+func (p *traceBatchSpanProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (p *traceBatchSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return
+	}
+
+	if p.queued >= p.batch.QueueSize {
+		p.signalFlush()
+		return
+	}
+
+	traceID := s.SpanContext().TraceID()
+
+	trace, ok := p.traces[traceID]
+	if !ok {
+		trace = &bufferedTrace{}
+		p.traces[traceID] = trace
+		p.order = append(p.order, traceID)
+	}
+
+	trace.spans = append(trace.spans, s)
+	p.queued++
+
+	if parent := s.Parent(); !parent.IsValid() || parent.IsRemote() {
+		trace.complete = true
+	}
+
+	if p.queued >= p.batch.Size {
+		p.signalFlush()
+	}
+}
+
+func (p *traceBatchSpanProcessor) signalFlush() {
+	select {
+	case p.flush <- struct{}{}:
+	default:
+	}
+}
+
+func (p *traceBatchSpanProcessor) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(time.Duration(p.batch.Timeout) * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.exportTraces(p.take(false, true))
+		case <-p.flush:
+			p.exportTraces(p.take(false, false))
+		}
+	}
+}
+
+// take removes the traces ready to be exported from the buffer: the complete traces and the ones kept
+// for a whole window, or all of them. When the window ends, the remaining traces are aged.
+// If the queue is still full, the oldest traces are removed too until there's room for a batch,
+// so the traces that never complete don't block the new ones.
+func (p *traceBatchSpanProcessor) take(all, windowEnd bool) [][]sdktrace.ReadOnlySpan {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ready := [][]sdktrace.ReadOnlySpan{}
+	order := p.order[:0]
+
+	for _, traceID := range p.order {
+		trace := p.traces[traceID]
+
+		if all || trace.complete || trace.windows > 0 {
+			ready = append(ready, trace.spans)
+			p.queued -= len(trace.spans)
+			delete(p.traces, traceID)
+
+			continue
+		}
+
+		if windowEnd {
+			trace.windows++
+		}
+
+		order = append(order, traceID)
+	}
+
+	p.order = order
+
+	if p.queued < p.batch.QueueSize {
+		return ready
+	}
+
+	for len(p.order) > 0 && p.queued > p.batch.QueueSize-p.batch.Size {
+		traceID := p.order[0]
+		trace := p.traces[traceID]
+
+		ready = append(ready, trace.spans)
+		p.queued -= len(trace.spans)
+		delete(p.traces, traceID)
+		p.order = p.order[1:]
+	}
+
+	return ready
+}
+
+func (p *traceBatchSpanProcessor) exportTraces(traces [][]sdktrace.ReadOnlySpan) {
+	ctx, cancel := context.WithTimeout(p.runCtx, traceBatchExportTimeout)
+	defer cancel()
+
+	if err := p.export(ctx, traces); err != nil {
+		otel.Handle(err)
+	}
+}
+
+// export sends the traces in batches of up to the batch size, without splitting them: a trace with
+// more spans than the batch size is sent alone in its own batch.
+func (p *traceBatchSpanProcessor) export(ctx context.Context, traces [][]sdktrace.ReadOnlySpan) error {
+	p.exportMu.Lock()
+	defer p.exportMu.Unlock()
+
+	errs := []error{}
+	batch := []sdktrace.ReadOnlySpan{}
+
+	send := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		if err := p.exporter.ExportSpans(ctx, batch); err != nil {
+			errs = append(errs, err)
+		}
+
+		batch = []sdktrace.ReadOnlySpan{}
+	}
+
+	for _, spans := range traces {
+		if len(batch)+len(spans) > p.batch.Size {
+			send()
+		}
+
+		batch = append(batch, spans...)
+	}
+
+	send()
+
+	return errors.Join(errs...)
+}
+
+func (p *traceBatchSpanProcessor) ForceFlush(ctx context.Context) error {
+	return p.export(ctx, p.take(true, false))
+}
+
+func (p *traceBatchSpanProcessor) markClosed() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+}
+
+func (p *traceBatchSpanProcessor) Shutdown(ctx context.Context) error {
+	var err error
+
+	p.stopOnce.Do(func() {
+		defer p.cancelRun()
+
+		close(p.stop)
+
+		select {
+		case <-p.done:
+		case <-ctx.Done():
+			// the export in flight of the run loop is cancelled, and the buffered spans are dropped
+			p.cancelRun()
+			p.markClosed()
+
+			err = errors.Join(ctx.Err(), p.exporter.Shutdown(ctx))
+
+			return
+		}
+
+		p.markClosed()
+
+		err = errors.Join(p.ForceFlush(ctx), p.exporter.Shutdown(ctx))
+	})
+
+	return err
+}
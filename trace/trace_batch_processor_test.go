@@ -0,0 +1,200 @@
+package trace
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// batchRecordingExporter records the spans of every exported batch.
+type batchRecordingExporter struct {
+	mu       sync.Mutex
+	batches  [][]sdktrace.ReadOnlySpan
+	shutdown bool
+}
+
+func (e *batchRecordingExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.batches = append(e.batches, spans)
+
+	return nil
+}
+
+func (e *batchRecordingExporter) Shutdown(context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.shutdown = true
+
+	return nil
+}
+
+// traceBatches returns the indexes of the batches holding the spans of every trace.
+func (e *batchRecordingExporter) traceBatches() map[trace.TraceID][]int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	traceBatches := map[trace.TraceID][]int{}
+
+	for i, batch := range e.batches {
+		for _, span := range batch {
+			traceID := span.SpanContext().TraceID()
+			if batches := traceBatches[traceID]; len(batches) == 0 || batches[len(batches)-1] != i {
+				traceBatches[traceID] = append(batches, i)
+			}
+		}
+	}
+
+	return traceBatches
+}
+
+func (e *batchRecordingExporter) spanCount() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	count := 0
+	for _, batch := range e.batches {
+		count += len(batch)
+	}
+
+	return count
+}
+
+func Test_TraceBatchSpanProcessor(t *testing.T) {
+	t.Run("traces exported in a single batch", func(t *testing.T) {
+		exporter := &batchRecordingExporter{}
+		// a batch size smaller than the traces, which the BatchSpanProcessor would split
+		processor := newTraceBatchSpanProcessor(exporter, config.Batch{Size: 2, Timeout: 20})
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor))
+		tracer := tp.Tracer("test")
+
+		roots := []trace.Span{}
+		children := []trace.Span{}
+
+		for i := 0; i < 3; i++ {
+			ctx, root := tracer.Start(context.Background(), "root")
+			_, first := tracer.Start(ctx, "first")
+			_, second := tracer.Start(ctx, "second")
+
+			roots = append(roots, root)
+			children = append(children, first, second)
+		}
+
+		// interleave the spans of the traces, ending the roots last
+		for _, span := range children {
+			span.End()
+		}
+
+		for _, root := range roots {
+			root.End()
+		}
+
+		assert.Eventually(t, func() bool {
+			return exporter.spanCount() == 9
+		}, time.Second, 5*time.Millisecond)
+
+		traceBatches := exporter.traceBatches()
+		assert.Len(t, traceBatches, 3)
+
+		for traceID, batches := range traceBatches {
+			assert.Len(t, batches, 1, "trace %s should be exported in a single batch", traceID)
+		}
+
+		assert.Nil(t, tp.Shutdown(context.Background()))
+		assert.True(t, exporter.shutdown)
+	})
+
+	t.Run("incomplete trace kept for one more window", func(t *testing.T) {
+		exporter := &batchRecordingExporter{}
+		processor := newTraceBatchSpanProcessor(exporter, config.Batch{Timeout: 50})
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor))
+		tracer := tp.Tracer("test")
+
+		ctx, root := tracer.Start(context.Background(), "root")
+		_, child := tracer.Start(ctx, "child")
+		child.End()
+
+		// the child waits for its root during the first window
+		time.Sleep(70 * time.Millisecond)
+		assert.Equal(t, 0, exporter.spanCount())
+
+		// and it's exported as is after the second one
+		assert.Eventually(t, func() bool {
+			return exporter.spanCount() == 1
+		}, time.Second, 5*time.Millisecond)
+
+		root.End()
+		assert.Nil(t, tp.ForceFlush(context.Background()))
+		assert.Equal(t, 2, exporter.spanCount())
+
+		assert.Nil(t, tp.Shutdown(context.Background()))
+	})
+
+	t.Run("full queue exports the oldest traces", func(t *testing.T) {
+		exporter := &batchRecordingExporter{}
+		// a batch window long enough to only export on the flushes triggered by the queue
+		processor := newTraceBatchSpanProcessor(exporter, config.Batch{Size: 2, Timeout: 60000, QueueSize: 4})
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor))
+		tracer := tp.Tracer("test")
+
+		traceIDs := []trace.TraceID{}
+		roots := []trace.Span{}
+
+		// two traces whose roots never end fill the queue
+		for i := 0; i < 2; i++ {
+			ctx, root := tracer.Start(context.Background(), "root")
+			traceIDs = append(traceIDs, root.SpanContext().TraceID())
+			roots = append(roots, root)
+
+			for j := 0; j < 2; j++ {
+				_, child := tracer.Start(ctx, "child")
+				child.End()
+			}
+		}
+
+		// the oldest trace is exported as it is to make room for a batch
+		assert.Eventually(t, func() bool {
+			return exporter.spanCount() == 2
+		}, time.Second, 5*time.Millisecond)
+
+		traceBatches := exporter.traceBatches()
+		assert.Len(t, traceBatches, 1)
+		assert.Contains(t, traceBatches, traceIDs[0])
+
+		assert.Nil(t, tp.ForceFlush(context.Background()))
+		assert.Equal(t, 4, exporter.spanCount())
+
+		for _, root := range roots {
+			root.End()
+		}
+
+		assert.Nil(t, tp.Shutdown(context.Background()))
+	})
+
+	t.Run("shutdown returns when its context is done", func(t *testing.T) {
+		blocking := &blockingExporter{started: make(chan struct{})}
+		processor := newTraceBatchSpanProcessor(blocking, config.Batch{Timeout: 10})
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor))
+
+		_, span := tp.Tracer("test").Start(context.Background(), "root")
+		span.End()
+
+		// the export of the batch window blocks until its context is done
+		<-blocking.started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		assert.ErrorIs(t, processor.Shutdown(ctx), context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
@@ -0,0 +1,77 @@
+package tracetest
+
+import (
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// AssertSpanHasEvent asserts that the span has at least one event with the given name.
+// It returns the first matching event, so its attributes can be checked too.
+func AssertSpanHasEvent(t testing.TB, span Span, name string) (Event, bool) {
+	t.Helper()
+
+	for _, event := range span.Events {
+		if event.Name == name {
+			return event, true
+		}
+	}
+
+	names := make([]string, 0, len(span.Events))
+	for _, event := range span.Events {
+		names = append(names, event.Name)
+	}
+
+	t.Errorf("span %q has no event %q, events: %v", span.Name, name, names)
+
+	return Event{}, false
+}
+
+// AssertSpanLinksTo asserts that the span has a link to the given span context.
+// Only the trace and span IDs are compared.
+func AssertSpanLinksTo(t testing.TB, span Span, sc oteltrace.SpanContext) bool {
+	t.Helper()
+
+	for _, link := range span.Links {
+		if link.SpanContext.TraceID() == sc.TraceID() && link.SpanContext.SpanID() == sc.SpanID() {
+			return true
+		}
+	}
+
+	t.Errorf("span %q has no link to trace %s span %s", span.Name, sc.TraceID(), sc.SpanID())
+
+	return false
+}
+
+// AssertSpanStatus asserts the span status code and description.
+// The description is only checked for the codes.Error status, since it's ignored for the other codes.
+func AssertSpanStatus(t testing.TB, span Span, code codes.Code, description string) bool {
+	t.Helper()
+
+	if span.Status.Code != code {
+		t.Errorf("span %q has status %s, expected %s", span.Name, span.Status.Code, code)
+		return false
+	}
+
+	if code == codes.Error && span.Status.Description != description {
+		t.Errorf("span %q has status description %q, expected %q", span.Name, span.Status.Description, description)
+		return false
+	}
+
+	return true
+}
+
+// AssertSpanDurationBetween asserts that the span duration is between min and max, both inclusive.
+func AssertSpanDurationBetween(t testing.TB, span Span, min, max time.Duration) bool {
+	t.Helper()
+
+	duration := span.EndTime.Sub(span.StartTime)
+	if duration < min || duration > max {
+		t.Errorf("span %q duration %s is not between %s and %s", span.Name, duration, min, max)
+		return false
+	}
+
+	return true
+}
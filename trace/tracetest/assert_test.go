@@ -0,0 +1,107 @@
+package tracetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// recordingT records the failures of the assertions under test.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func Test_AssertSpanHasEvent(t *testing.T) {
+	tp := NewProvider()
+	defer tp.Shutdown(context.Background())
+
+	_, span := tp.Tracer().Start(context.Background(), "span")
+	span.AddEvent("cache miss", oteltrace.WithAttributes(attribute.String("key", "value")))
+	span.RecordError(errors.New("upstream error"))
+	span.End()
+
+	stub := tp.Spans()[0]
+
+	event, ok := AssertSpanHasEvent(t, stub, "cache miss")
+	assert.True(t, ok)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("key", "value")}, event.Attributes)
+
+	_, ok = AssertSpanHasEvent(t, stub, "exception")
+	assert.True(t, ok)
+
+	rt := &recordingT{TB: t}
+	_, ok = AssertSpanHasEvent(rt, stub, "unknown")
+	assert.False(t, ok)
+	assert.Equal(t, []string{`span "span" has no event "unknown", events: [cache miss exception]`}, rt.errors)
+}
+
+func Test_AssertSpanLinksTo(t *testing.T) {
+	tp := NewProvider()
+	defer tp.Shutdown(context.Background())
+
+	_, linked := tp.Tracer().Start(context.Background(), "linked")
+	linked.End()
+
+	_, span := tp.Tracer().Start(context.Background(), "span", oteltrace.WithLinks(oteltrace.Link{
+		SpanContext: linked.SpanContext(),
+	}))
+	span.End()
+
+	stub := tp.SpansByName("span")[0]
+	assert.True(t, AssertSpanLinksTo(t, stub, linked.SpanContext()))
+
+	rt := &recordingT{TB: t}
+	assert.False(t, AssertSpanLinksTo(rt, stub, span.SpanContext()))
+	assert.Len(t, rt.errors, 1)
+}
+
+func Test_AssertSpanStatus(t *testing.T) {
+	tp := NewProvider()
+	defer tp.Shutdown(context.Background())
+
+	_, span := tp.Tracer().Start(context.Background(), "span")
+	span.SetStatus(codes.Error, "upstream error")
+	span.End()
+
+	stub := tp.Spans()[0]
+	assert.True(t, AssertSpanStatus(t, stub, codes.Error, "upstream error"))
+
+	rt := &recordingT{TB: t}
+	assert.False(t, AssertSpanStatus(rt, stub, codes.Ok, ""))
+	assert.False(t, AssertSpanStatus(rt, stub, codes.Error, "other error"))
+	assert.Equal(t, []string{
+		`span "span" has status Error, expected Ok`,
+		`span "span" has status description "upstream error", expected "other error"`,
+	}, rt.errors)
+}
+
+func Test_AssertSpanDurationBetween(t *testing.T) {
+	tp := NewProvider()
+	defer tp.Shutdown(context.Background())
+
+	start := time.Now()
+	_, span := tp.Tracer().Start(context.Background(), "span", oteltrace.WithTimestamp(start))
+	span.End(oteltrace.WithTimestamp(start.Add(100 * time.Millisecond)))
+
+	stub := tp.Spans()[0]
+	assert.True(t, AssertSpanDurationBetween(t, stub, 100*time.Millisecond, 100*time.Millisecond))
+	assert.True(t, AssertSpanDurationBetween(t, stub, 50*time.Millisecond, time.Second))
+
+	rt := &recordingT{TB: t}
+	assert.False(t, AssertSpanDurationBetween(rt, stub, time.Second, 2*time.Second))
+	assert.Equal(t, []string{`span "span" duration 100ms is not between 1s and 2s`}, rt.errors)
+}
@@ -0,0 +1,110 @@
+package tracetest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TykTechnologies/opentelemetry/trace"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// RoundTripResult is the result of a propagation round trip.
+type RoundTripResult struct {
+	// ServerSpan is the span created by NewHTTPHandler for the incoming request.
+	ServerSpan Span
+	// OutboundHeaders are the headers of the downstream request sent through NewHTTPTransport.
+	OutboundHeaders http.Header
+}
+
+/*
+	PropagationRoundTrip runs a handler wrapped by trace.NewHTTPHandler behind an httptest server,
+	sends it a request with the given headers, and makes the handler call a downstream server
+	through trace.NewHTTPTransport. It returns the server span and the headers injected in the
+	downstream request, so propagators can be tested end to end.
+	The given propagator is set as the global propagator during the round trip, and the previous
+	one is restored afterwards, so tests using it must not run in parallel. If propagator is nil,
+	the current global propagator is used.
+
+Example
+
+	tp := tracetest.NewProvider()
+	res := tracetest.PropagationRoundTrip(t, tp, propagation.TraceContext{}, http.Header{
+		"Traceparent": {"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+	})
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", res.ServerSpan.SpanContext.TraceID().String())
+*/
+func PropagationRoundTrip(t testing.TB, tp *Provider, propagator propagation.TextMapPropagator,
+	headers http.Header) RoundTripResult {
+	t.Helper()
+
+	if propagator != nil {
+		previous := otel.GetTextMapPropagator()
+		otel.SetTextMapPropagator(propagator)
+
+		defer otel.SetTextMapPropagator(previous)
+	}
+
+	outbound := make(chan http.Header, 1)
+
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		outbound <- r.Header.Clone()
+	}))
+	defer downstream.Close()
+
+	client := &http.Client{Transport: trace.NewHTTPTransport(http.DefaultTransport)}
+
+	handler := trace.NewHTTPHandler("propagation", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, downstream.URL, nil)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		res, err := client.Do(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		res.Body.Close()
+	}), tp)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("failed to create the request: %v", err)
+	}
+
+	for k, values := range headers {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to send the request: %v", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected response status: %s", res.Status)
+	}
+
+	result := RoundTripResult{
+		OutboundHeaders: <-outbound,
+	}
+
+	for _, span := range tp.Spans() {
+		if span.SpanKind == oteltrace.SpanKindServer {
+			result.ServerSpan = span
+		}
+	}
+
+	return result
+}
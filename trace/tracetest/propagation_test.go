@@ -0,0 +1,79 @@
+package tracetest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func Test_PropagationRoundTrip(t *testing.T) {
+	tcs := []struct {
+		testName         string
+		propagator       propagation.TextMapPropagator
+		headers          http.Header
+		expectedTraceID  string
+		expectedParentID string
+		outboundHeader   string
+	}{
+		{
+			testName:   "tracecontext",
+			propagator: propagation.TraceContext{},
+			headers: http.Header{
+				"Traceparent": {"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+			},
+			expectedTraceID:  "0af7651916cd43dd8448eb211c80319c",
+			expectedParentID: "b7ad6b7169203331",
+			outboundHeader:   "Traceparent",
+		},
+		{
+			testName:   "b3",
+			propagator: b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)),
+			headers: http.Header{
+				"X-B3-Traceid": {"0af7651916cd43dd8448eb211c80319c"},
+				"X-B3-Spanid":  {"b7ad6b7169203331"},
+				"X-B3-Sampled": {"1"},
+			},
+			expectedTraceID:  "0af7651916cd43dd8448eb211c80319c",
+			expectedParentID: "b7ad6b7169203331",
+			outboundHeader:   "X-B3-Traceid",
+		},
+		{
+			testName:   "no incoming context",
+			propagator: propagation.TraceContext{},
+			headers:    http.Header{},
+			// a new trace is started
+			outboundHeader: "Traceparent",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			tp := NewProvider()
+			defer tp.Shutdown(context.Background())
+
+			previous := otel.GetTextMapPropagator()
+
+			res := PropagationRoundTrip(t, tp, tc.propagator, tc.headers)
+
+			// the global propagator is restored
+			assert.Equal(t, previous, otel.GetTextMapPropagator())
+
+			assert.True(t, res.ServerSpan.SpanContext.IsValid())
+			assert.Equal(t, "GET /", res.ServerSpan.Name)
+			assert.NotEmpty(t, res.OutboundHeaders.Get(tc.outboundHeader))
+
+			if tc.expectedTraceID != "" {
+				assert.Equal(t, tc.expectedTraceID, res.ServerSpan.SpanContext.TraceID().String())
+				assert.Equal(t, tc.expectedParentID, res.ServerSpan.Parent.SpanID().String())
+				assert.Contains(t, res.OutboundHeaders.Get(tc.outboundHeader), tc.expectedTraceID)
+			} else {
+				assert.False(t, res.ServerSpan.Parent.IsValid())
+			}
+		})
+	}
+}
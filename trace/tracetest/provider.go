@@ -0,0 +1,236 @@
+// Package tracetest provides an in-memory trace.Provider and helpers to
+// assert the spans recorded by the code under test.
+package tracetest
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"github.com/TykTechnologies/opentelemetry/trace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	sdktracetest "go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Span is a snapshot of an ended span.
+type Span = sdktracetest.SpanStub
+
+// Event is an event recorded in a span.
+type Event = sdktrace.Event
+
+// SpanNode is a span and its children, ordered by start time.
+type SpanNode struct {
+	Span     Span
+	Children []*SpanNode
+}
+
+// Provider is a trace.Provider that records the ended spans in memory.
+// The spans are exported synchronously, so they are available as soon as they end.
+type Provider struct {
+	exporter       *sdktracetest.InMemoryExporter
+	tracerProvider *sdktrace.TracerProvider
+
+	mu            sync.Mutex
+	shutdownHooks []func(context.Context) error
+	shutdownOnce  sync.Once
+	closed        atomic.Bool
+}
+
+var (
+	_ trace.Provider          = &Provider{}
+	_ trace.ProviderLifecycle = &Provider{}
+)
+
+/*
+	NewProvider creates a new in-memory provider. The given options are passed to the
+	underlying SDK tracer provider, e.g. to set a sampler or a resource.
+
+Example
+
+	tp := tracetest.NewProvider()
+	handler := trace.NewHTTPHandler("api", myHandler, tp)
+	...
+	spans := tp.SpansByName("GET /api")
+*/
+func NewProvider(opts ...sdktrace.TracerProviderOption) *Provider {
+	exporter := sdktracetest.NewInMemoryExporter()
+
+	opts = append([]sdktrace.TracerProviderOption{sdktrace.WithSyncer(exporter)}, opts...)
+
+	return &Provider{
+		exporter:       exporter,
+		tracerProvider: sdktrace.NewTracerProvider(opts...),
+	}
+}
+
+// Shutdown shuts down the provider once, the later calls return nil.
+func (tp *Provider) Shutdown(ctx context.Context) error {
+	var err error
+
+	tp.shutdownOnce.Do(func() {
+		err = tp.shutdown(ctx)
+		tp.closed.Store(true)
+	})
+
+	return err
+}
+
+func (tp *Provider) shutdown(ctx context.Context) error {
+	tp.mu.Lock()
+	hooks := tp.shutdownHooks
+	tp.shutdownHooks = nil
+	tp.mu.Unlock()
+
+	errs := []error{}
+
+	for _, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	errs = append(errs, tp.tracerProvider.Shutdown(ctx))
+
+	return errors.Join(errs...)
+}
+
+// EffectiveConfig returns an empty configuration, since the in-memory provider isn't configured.
+func (tp *Provider) EffectiveConfig() config.OpenTelemetry {
+	return config.OpenTelemetry{}
+}
+
+func (tp *Provider) Closed() bool {
+	return tp.closed.Load()
+}
+
+func (tp *Provider) OnShutdown(hook func(context.Context) error) {
+	if hook == nil {
+		return
+	}
+
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	tp.shutdownHooks = append(tp.shutdownHooks, hook)
+}
+
+func (tp *Provider) Tracer() trace.Tracer {
+	return tp.tracerProvider.Tracer("tyk")
+}
+
+func (tp *Provider) Type() string {
+	return trace.OTEL_PROVIDER
+}
+
+func (tp *Provider) Enabled() bool {
+	return true
+}
+
+func (tp *Provider) ForceFlush(ctx context.Context) error {
+	return tp.tracerProvider.ForceFlush(ctx)
+}
+
+func (tp *Provider) TracerProvider() oteltrace.TracerProvider {
+	return tp.tracerProvider
+}
+
+// Reset removes all the recorded spans.
+func (tp *Provider) Reset() {
+	tp.exporter.Reset()
+}
+
+// Spans returns all the ended spans, in the order they ended.
+func (tp *Provider) Spans() []Span {
+	return tp.exporter.GetSpans()
+}
+
+// SpansByName returns the ended spans with the given name, in the order they ended.
+func (tp *Provider) SpansByName(name string) []Span {
+	spans := []Span{}
+
+	for _, span := range tp.Spans() {
+		if span.Name == name {
+			spans = append(spans, span)
+		}
+	}
+
+	return spans
+}
+
+// RootSpans returns the ended spans without a local parent, in the order they ended.
+// Spans continuing a remote trace are considered root spans.
+func (tp *Provider) RootSpans() []Span {
+	spans := []Span{}
+
+	for _, span := range tp.Spans() {
+		if !span.Parent.IsValid() || span.Parent.IsRemote() {
+			spans = append(spans, span)
+		}
+	}
+
+	return spans
+}
+
+/*
+	SpanTree returns the hierarchy of the ended spans of the given trace. It returns the nodes of the
+	spans whose parent was not recorded, usually the root span of the trace, ordered by start time.
+
+Example
+
+	roots := tp.SpanTree(span.SpanContext().TraceID())
+	assert.Equal(t, "GET /api", roots[0].Span.Name)
+	assert.Equal(t, "upstream", roots[0].Children[0].Span.Name)
+*/
+func (tp *Provider) SpanTree(traceID oteltrace.TraceID) []*SpanNode {
+	nodes := map[oteltrace.SpanID]*SpanNode{}
+	traceSpans := []Span{}
+
+	for _, span := range tp.Spans() {
+		if span.SpanContext.TraceID() != traceID {
+			continue
+		}
+
+		traceSpans = append(traceSpans, span)
+		nodes[span.SpanContext.SpanID()] = &SpanNode{Span: span}
+	}
+
+	sort.SliceStable(traceSpans, func(i, j int) bool {
+		return traceSpans[i].StartTime.Before(traceSpans[j].StartTime)
+	})
+
+	roots := []*SpanNode{}
+
+	for _, span := range traceSpans {
+		node := nodes[span.SpanContext.SpanID()]
+
+		parent, ok := nodes[span.Parent.SpanID()]
+		if !ok || !span.Parent.IsValid() {
+			roots = append(roots, node)
+			continue
+		}
+
+		parent.Children = append(parent.Children, node)
+	}
+
+	return roots
+}
+
+// Find returns the first node of the tree with the given name, searching depth-first,
+// or nil if there is none.
+func (n *SpanNode) Find(name string) *SpanNode {
+	if n.Span.Name == name {
+		return n
+	}
+
+	for _, child := range n.Children {
+		if found := child.Find(name); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package tracetest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TykTechnologies/opentelemetry/trace"
+	"github.com/stretchr/testify/assert"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func Test_Provider(t *testing.T) {
+	tp := NewProvider()
+	defer tp.Shutdown(context.Background())
+
+	assert.Equal(t, trace.OTEL_PROVIDER, tp.Type())
+	assert.True(t, tp.Enabled())
+	assert.Nil(t, tp.ForceFlush(context.Background()))
+
+	ctx, root := tp.Tracer().Start(context.Background(), "root")
+	_, first := tp.Tracer().Start(ctx, "child")
+	first.End()
+
+	childCtx, second := tp.Tracer().Start(ctx, "child")
+	_, grandchild := tp.TracerProvider().Tracer("test").Start(childCtx, "grandchild")
+	grandchild.End()
+	second.End()
+	root.End()
+
+	_, other := tp.Tracer().Start(context.Background(), "other")
+	other.End()
+
+	assert.Len(t, tp.Spans(), 5)
+	assert.Len(t, tp.SpansByName("child"), 2)
+	assert.Empty(t, tp.SpansByName("unknown"))
+
+	roots := tp.RootSpans()
+	assert.Len(t, roots, 2)
+	assert.Equal(t, "root", roots[0].Name)
+	assert.Equal(t, "other", roots[1].Name)
+
+	tree := tp.SpanTree(root.SpanContext().TraceID())
+	assert.Len(t, tree, 1)
+	assert.Equal(t, "root", tree[0].Span.Name)
+	assert.Len(t, tree[0].Children, 2)
+	assert.Equal(t, first.SpanContext().SpanID(), tree[0].Children[0].Span.SpanContext.SpanID())
+	assert.Equal(t, second.SpanContext().SpanID(), tree[0].Children[1].Span.SpanContext.SpanID())
+	assert.Equal(t, "grandchild", tree[0].Children[1].Children[0].Span.Name)
+
+	assert.Equal(t, grandchild.SpanContext().SpanID(), tree[0].Find("grandchild").Span.SpanContext.SpanID())
+	assert.Nil(t, tree[0].Find("unknown"))
+
+	assert.Empty(t, tp.SpanTree(oteltrace.TraceID{0x01}))
+
+	tp.Reset()
+	assert.Empty(t, tp.Spans())
+}
+
+func Test_ProviderSpanTreeWithMissingParent(t *testing.T) {
+	tp := NewProvider()
+	defer tp.Shutdown(context.Background())
+
+	ctx, root := tp.Tracer().Start(context.Background(), "root")
+	_, child := tp.Tracer().Start(ctx, "child")
+	child.End()
+
+	// the root span is not ended, so the child is the root of the tree
+	tree := tp.SpanTree(root.SpanContext().TraceID())
+	assert.Len(t, tree, 1)
+	assert.Equal(t, "child", tree[0].Span.Name)
+
+	root.End()
+}
+
+func Test_ProviderWithHTTPHandler(t *testing.T) {
+	tp := NewProvider()
+	defer tp.Shutdown(context.Background())
+
+	handler := trace.NewHTTPHandler("test", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, span := trace.NewSpanFromContext(r.Context(), "", "middleware")
+		span.End()
+	}), tp)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api", nil))
+
+	roots := tp.RootSpans()
+	assert.Len(t, roots, 1)
+	assert.Equal(t, "GET /api", roots[0].Name)
+
+	tree := tp.SpanTree(roots[0].SpanContext.TraceID())
+	assert.NotNil(t, tree[0].Find("middleware"))
+}
@@ -0,0 +1,309 @@
+package trace
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// HTTPResendCountKey is the number of times the request was resent, set on the client
+	// spans of the retries. The first attempt doesn't have it, following the semantic conventions.
+	HTTPResendCountKey = attribute.Key("http.request.resend_count")
+	// HTTPDNSDurationKey is the duration of the DNS lookup of the request, in milliseconds.
+	HTTPDNSDurationKey = attribute.Key("http.client.dns.duration")
+	// HTTPConnectDurationKey is the duration of the connection establishment of the request, in milliseconds.
+	HTTPConnectDurationKey = attribute.Key("http.client.connect.duration")
+	// HTTPTLSDurationKey is the duration of the TLS handshake of the request, in milliseconds.
+	HTTPTLSDurationKey = attribute.Key("http.client.tls.duration")
+	// HTTPConnectionReusedKey reports whether the request used a connection from the pool.
+	HTTPConnectionReusedKey = attribute.Key("http.client.connection.reused")
+)
+
+// TransportOption configures the transport returned by NewHTTPTransport.
+type TransportOption interface {
+	apply(*transportConfig)
+}
+
+type transportOpts struct {
+	fn func(*transportConfig)
+}
+
+func (o *transportOpts) apply(cfg *transportConfig) {
+	o.fn(cfg)
+}
+
+type transportConfig struct {
+	retrySpans        bool
+	connectionTimings bool
+	clientTrace       bool
+}
+
+// TransportOptions returns the NewHTTPTransport options enabled in the configuration.
+//
+//	transport := trace.NewHTTPTransport(http.DefaultTransport, trace.TransportOptions(cfg)...)
+func TransportOptions(cfg *config.OpenTelemetry) []TransportOption {
+	opts := []TransportOption{}
+
+	if cfg.HTTPTransport.ClientTrace {
+		opts = append(opts, WithClientTrace())
+	}
+
+	if cfg.HTTPTransport.ConnectionTimings {
+		opts = append(opts, WithConnectionTimings())
+	}
+
+	return opts
+}
+
+/*
+	WithRetrySpans counts the attempts of the requests sent with a context prepared by
+	ContextWithRetries. Each attempt gets its own client span, and the retries are
+	flagged with the http.request.resend_count attribute.
+
+Example
+
+	transport := trace.NewHTTPTransport(http.DefaultTransport, trace.WithRetrySpans())
+
+	ctx = trace.ContextWithRetries(ctx)
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if resp, err := transport.RoundTrip(req); err == nil {
+			return resp, nil
+		}
+	}
+*/
+func WithRetrySpans() TransportOption {
+	return &transportOpts{
+		fn: func(cfg *transportConfig) {
+			cfg.retrySpans = true
+		},
+	}
+}
+
+/*
+	WithConnectionTimings records the DNS lookup, connection and TLS handshake durations
+	of the requests as attributes of the client spans, and whether the connection was reused.
+
+Example
+
+	transport := trace.NewHTTPTransport(http.DefaultTransport, trace.WithConnectionTimings())
+*/
+func WithConnectionTimings() TransportOption {
+	return &transportOpts{
+		fn: func(cfg *transportConfig) {
+			cfg.connectionTimings = true
+		},
+	}
+}
+
+/*
+	WithClientTrace records the low level events of the requests as events of the client spans:
+	the DNS lookup, the connection establishment, the TLS handshake, the connection obtained
+	and the first response byte.
+
+Example
+
+	transport := trace.NewHTTPTransport(http.DefaultTransport, trace.WithClientTrace())
+*/
+func WithClientTrace() TransportOption {
+	return &transportOpts{
+		fn: func(cfg *transportConfig) {
+			cfg.clientTrace = true
+		},
+	}
+}
+
+type retriesContextKey struct{}
+
+// ContextWithRetries returns a context that counts the attempts of the requests sent
+// with it through a transport created with WithRetrySpans. Each logical request,
+// including all its retries, must use its own context.
+func ContextWithRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retriesContextKey{}, new(atomic.Int64))
+}
+
+// attemptTransport is wrapped by the otelhttp transport, so the client span of the
+// attempt is in the context of the requests it receives.
+type attemptTransport struct {
+	base http.RoundTripper
+	cfg  transportConfig
+}
+
+func (t *attemptTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	span := trace.SpanFromContext(req.Context())
+
+	if t.cfg.retrySpans {
+		if attempts, ok := req.Context().Value(retriesContextKey{}).(*atomic.Int64); ok {
+			if resendCount := attempts.Add(1) - 1; resendCount > 0 {
+				span.SetAttributes(HTTPResendCountKey.Int64(resendCount))
+			}
+		}
+	}
+
+	if t.cfg.connectionTimings && span.IsRecording() {
+		timings := &connectionTimings{}
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), timings.clientTrace()))
+
+		defer func() {
+			span.SetAttributes(timings.attributes()...)
+		}()
+	}
+
+	if t.cfg.clientTrace && span.IsRecording() {
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), clientTraceEvents(span)))
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// clientTraceEvents returns a client trace that records the request events in the span.
+func clientTraceEvents(span trace.Span) *httptrace.ClientTrace {
+	withError := func(attrs []attribute.KeyValue, err error) []attribute.KeyValue {
+		if err != nil {
+			attrs = append(attrs, attribute.String("error", err.Error()))
+		}
+
+		return attrs
+	}
+
+	return &httptrace.ClientTrace{
+		GetConn: func(hostPort string) {
+			span.AddEvent("http.getconn.start", trace.WithAttributes(attribute.String("net.host.port", hostPort)))
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			span.AddEvent("http.getconn.done", trace.WithAttributes(
+				attribute.Bool("reused", info.Reused),
+				attribute.Bool("was_idle", info.WasIdle),
+			))
+		},
+		DNSStart: func(info httptrace.DNSStartInfo) {
+			span.AddEvent("http.dns.start", trace.WithAttributes(attribute.String("net.host.name", info.Host)))
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			addrs := make([]string, 0, len(info.Addrs))
+			for _, addr := range info.Addrs {
+				addrs = append(addrs, addr.String())
+			}
+
+			span.AddEvent("http.dns.done", trace.WithAttributes(
+				withError([]attribute.KeyValue{attribute.StringSlice("net.host.addresses", addrs)}, info.Err)...,
+			))
+		},
+		ConnectStart: func(network, addr string) {
+			span.AddEvent("http.connect.start", trace.WithAttributes(
+				attribute.String("net.peer.address", addr),
+				attribute.String("net.transport", network),
+			))
+		},
+		ConnectDone: func(network, addr string, err error) {
+			span.AddEvent("http.connect.done", trace.WithAttributes(
+				withError([]attribute.KeyValue{
+					attribute.String("net.peer.address", addr),
+					attribute.String("net.transport", network),
+				}, err)...,
+			))
+		},
+		TLSHandshakeStart: func() {
+			span.AddEvent("http.tls.start")
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			span.AddEvent("http.tls.done", trace.WithAttributes(
+				withError([]attribute.KeyValue{
+					attribute.String("tls.version", tls.VersionName(state.Version)),
+					attribute.Bool("tls.resumed", state.DidResume),
+				}, err)...,
+			))
+		},
+		GotFirstResponseByte: func() {
+			span.AddEvent("http.receive.first_byte")
+		},
+	}
+}
+
+// connectionTimings collects the connection durations of a request.
+// The httptrace hooks can be called concurrently, for example when dialing several addresses.
+type connectionTimings struct {
+	mu sync.Mutex
+
+	dnsStart, dnsDone         time.Time
+	connectStart, connectDone time.Time
+	tlsStart, tlsDone         time.Time
+	reused                    bool
+	gotConn                   bool
+}
+
+func (c *connectionTimings) clientTrace() *httptrace.ClientTrace {
+	record := func(t *time.Time) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		*t = time.Now()
+	}
+
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { record(&c.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { record(&c.dnsDone) },
+		ConnectStart: func(string, string) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+
+			// keep the first dial when several addresses are tried
+			if c.connectStart.IsZero() {
+				c.connectStart = time.Now()
+			}
+		},
+		ConnectDone:       func(string, string, error) { record(&c.connectDone) },
+		TLSHandshakeStart: func() { record(&c.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { record(&c.tlsDone) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+
+			c.gotConn = true
+			c.reused = info.Reused
+		},
+	}
+}
+
+func (c *connectionTimings) attributes() []attribute.KeyValue {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	attrs := []attribute.KeyValue{}
+
+	if c.gotConn {
+		attrs = append(attrs, HTTPConnectionReusedKey.Bool(c.reused))
+	}
+
+	if duration, ok := elapsed(c.dnsStart, c.dnsDone); ok {
+		attrs = append(attrs, HTTPDNSDurationKey.Float64(duration))
+	}
+
+	if duration, ok := elapsed(c.connectStart, c.connectDone); ok {
+		attrs = append(attrs, HTTPConnectDurationKey.Float64(duration))
+	}
+
+	if duration, ok := elapsed(c.tlsStart, c.tlsDone); ok {
+		attrs = append(attrs, HTTPTLSDurationKey.Float64(duration))
+	}
+
+	return attrs
+}
+
+// elapsed returns the milliseconds between start and end, if both were recorded.
+func elapsed(start, end time.Time) (float64, bool) {
+	if start.IsZero() || end.IsZero() {
+		return 0, false
+	}
+
+	return float64(end.Sub(start)) / float64(time.Millisecond), true
+}
@@ -0,0 +1,219 @@
+package trace
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TykTechnologies/opentelemetry/config"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func sendRequest(t *testing.T, ctx context.Context, transport http.RoundTripper, url string) int {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	assert.Nil(t, err)
+
+	resp, err := transport.RoundTrip(req)
+	assert.Nil(t, err)
+
+	_, err = io.Copy(io.Discard, resp.Body)
+	assert.Nil(t, err)
+	assert.Nil(t, resp.Body.Close())
+
+	return resp.StatusCode
+}
+
+func attributeValue(span tracetest.SpanStub, key attribute.Key) (attribute.Value, bool) {
+	for _, attr := range span.Attributes {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+
+	return attribute.Value{}, false
+}
+
+func Test_WithRetrySpans(t *testing.T) {
+	provider, exporter := newInMemoryProvider()
+	otel.SetTracerProvider(provider.TracerProvider())
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := NewHTTPTransport(http.DefaultTransport, WithRetrySpans())
+
+	ctx := ContextWithRetries(context.Background())
+	for attempt := 0; attempt < 3; attempt++ {
+		if sendRequest(t, ctx, transport, server.URL) == http.StatusOK {
+			break
+		}
+	}
+
+	// a new logical request starts counting from scratch
+	sendRequest(t, ContextWithRetries(context.Background()), transport, server.URL)
+
+	// requests without the retries context are not counted
+	sendRequest(t, context.Background(), transport, server.URL)
+	sendRequest(t, context.Background(), transport, server.URL)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 6)
+
+	expected := []int64{0, 1, 2, 0, 0, 0}
+	for i, span := range spans {
+		resendCount, ok := attributeValue(span, HTTPResendCountKey)
+		if expected[i] == 0 {
+			assert.False(t, ok, "span %d", i)
+			continue
+		}
+
+		assert.Equal(t, expected[i], resendCount.AsInt64(), "span %d", i)
+	}
+}
+
+func Test_WithConnectionTimings(t *testing.T) {
+	provider, exporter := newInMemoryProvider()
+	otel.SetTracerProvider(provider.TracerProvider())
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := NewHTTPTransport(server.Client().Transport, WithConnectionTimings())
+
+	sendRequest(t, context.Background(), transport, server.URL)
+	sendRequest(t, context.Background(), transport, server.URL)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+
+	// the first request establishes the connection
+	reused, ok := attributeValue(spans[0], HTTPConnectionReusedKey)
+	assert.True(t, ok)
+	assert.False(t, reused.AsBool())
+
+	for _, key := range []attribute.Key{HTTPConnectDurationKey, HTTPTLSDurationKey} {
+		duration, ok := attributeValue(spans[0], key)
+		assert.True(t, ok, key)
+		assert.GreaterOrEqual(t, duration.AsFloat64(), float64(0), key)
+	}
+
+	// the second one reuses it
+	reused, ok = attributeValue(spans[1], HTTPConnectionReusedKey)
+	assert.True(t, ok)
+	assert.True(t, reused.AsBool())
+
+	_, ok = attributeValue(spans[1], HTTPConnectDurationKey)
+	assert.False(t, ok)
+}
+
+func Test_NewHTTPTransportWithoutOptions(t *testing.T) {
+	provider, exporter := newInMemoryProvider()
+	otel.SetTracerProvider(provider.TracerProvider())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := NewHTTPTransport(http.DefaultTransport)
+
+	sendRequest(t, ContextWithRetries(context.Background()), transport, server.URL)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+
+	for _, key := range []attribute.Key{HTTPResendCountKey, HTTPConnectionReusedKey, HTTPConnectDurationKey} {
+		_, ok := attributeValue(spans[0], key)
+		assert.False(t, ok, key)
+	}
+}
+
+func Test_WithClientTrace(t *testing.T) {
+	provider, exporter := newInMemoryProvider()
+	otel.SetTracerProvider(provider.TracerProvider())
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := NewHTTPTransport(server.Client().Transport, WithClientTrace())
+
+	sendRequest(t, context.Background(), transport, server.URL)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+
+	events := []string{}
+	for _, event := range spans[0].Events {
+		events = append(events, event.Name)
+	}
+
+	// the server listens on an IP address, so there is no DNS lookup
+	assert.Equal(t, []string{
+		"http.getconn.start",
+		"http.connect.start",
+		"http.connect.done",
+		"http.tls.start",
+		"http.tls.done",
+		"http.getconn.done",
+		"http.receive.first_byte",
+	}, events)
+}
+
+func Test_TransportOptions(t *testing.T) {
+	tcs := []struct {
+		name     string
+		cfg      *config.OpenTelemetry
+		expected transportConfig
+	}{
+		{
+			name:     "disabled",
+			cfg:      &config.OpenTelemetry{},
+			expected: transportConfig{},
+		},
+		{
+			name: "client trace",
+			cfg: &config.OpenTelemetry{
+				HTTPTransport: config.HTTPTransport{ClientTrace: true},
+			},
+			expected: transportConfig{clientTrace: true},
+		},
+		{
+			name: "client trace and connection timings",
+			cfg: &config.OpenTelemetry{
+				HTTPTransport: config.HTTPTransport{ClientTrace: true, ConnectionTimings: true},
+			},
+			expected: transportConfig{clientTrace: true, connectionTimings: true},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := transportConfig{}
+			for _, opt := range TransportOptions(tc.cfg) {
+				opt.apply(&cfg)
+			}
+
+			assert.Equal(t, tc.expected, cfg)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package trace
+
+import (
+	"runtime/debug"
+)
+
+const (
+	// libraryName identifies this library in the exporters User-Agent and in the
+	// telemetry.distro.name resource attribute.
+	libraryName = "tyk-otel"
+	// libraryModulePath is the module path used to look up the library version in the build info.
+	libraryModulePath = "github.com/TykTechnologies/opentelemetry"
+	// develVersion is reported when the library version can't be resolved, for example
+	// when the library is built as the main module.
+	develVersion = "devel"
+)
+
+// libraryVersion returns the version of this library as recorded in the build info
+// of the running binary.
+func libraryVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return develVersion
+	}
+
+	modules := append([]*debug.Module{&info.Main}, info.Deps...)
+	for _, module := range modules {
+		if module == nil || module.Path != libraryModulePath {
+			continue
+		}
+
+		// replaced modules report the version of the replacement
+		if module.Replace != nil {
+			module = module.Replace
+		}
+
+		if module.Version == "" || module.Version == "(devel)" {
+			return develVersion
+		}
+
+		return module.Version
+	}
+
+	return develVersion
+}
+
+// defaultUserAgent returns the User-Agent sent by the OTLP exporters when
+// it's not configured, in the tyk-otel/<version> format.
+func defaultUserAgent() string {
+	return libraryName + "/" + libraryVersion()
+}